@@ -1,9 +1,19 @@
 package main
 
 import (
+	"context"
+	"net"
+	"net/smtp"
+	_ "time/tzdata"
+
+	"github.com/jeandeducla/api-plant/internal/geo"
 	"github.com/jeandeducla/api-plant/internal/plants"
 	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/notify"
+	"github.com/jeandeducla/api-plant/internal/outbox"
+	"github.com/jeandeducla/api-plant/internal/rpc"
 	"github.com/jeandeducla/api-plant/internal/server"
+	"github.com/jeandeducla/api-plant/internal/webhooks"
 )
 
 func main() {
@@ -20,6 +30,36 @@ func main() {
 
     // Business logic layer
     plantsService := plants.NewPlantsService(plantsDB)
+    plantsService.Notifier = newNotifier(config)
+    plantsService.Webhooks = webhooks.NewClient()
+    if config.natsAddr != "" {
+        plantsService.Sinks["nats"] = outbox.NewNATSSink(config.natsAddr, config.natsSubject)
+    }
+    if config.kafkaProxy != "" {
+        plantsService.Sinks["kafka"] = outbox.NewKafkaSink(config.kafkaProxy, config.kafkaTopic)
+    }
+    go plantsService.RelayOutbox(context.Background())
+    go plantsService.PurgeOutbox(context.Background())
+    go plantsService.DeliverWebhooks(context.Background())
+    go plantsService.PurgeIdempotencyKeys(context.Background())
+    go plantsService.SweepCommands(context.Background())
+    if config.gazetteer != "" {
+        plantsService.Geocoder, err = geo.LoadGazetteer(config.gazetteer)
+        if err != nil {
+            panic(err)
+        }
+    }
+
+    // gRPC layer
+    lis, err := net.Listen("tcp", config.grpcAddr)
+    if err != nil {
+        panic(err)
+    }
+    go func() {
+        if err := rpc.NewServer(plantsService).GRPCServer().Serve(lis); err != nil {
+            panic(err)
+        }
+    }()
 
     // http layer
     server, err := server.NewServer(plantsService)
@@ -29,3 +69,17 @@ func main() {
 
     server.Router().Run()
 }
+
+func newNotifier(config *Config) *notify.Notifier {
+    notifier := notify.NewNotifier()
+    notifier.Register(plants.NotificationWebhook, notify.NewWebhookSender())
+    if config.smtpAddr != "" {
+        var auth smtp.Auth
+        if config.smtpUser != "" {
+            host, _, _ := net.SplitHostPort(config.smtpAddr)
+            auth = smtp.PlainAuth("", config.smtpUser, config.smtpPassword, host)
+        }
+        notifier.Register(plants.NotificationEmail, notify.NewSMTPSender(config.smtpAddr, config.smtpFrom, auth))
+    }
+    return notifier
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type PlantAssignment struct {
+    ID              uint       `json:"id"`
+    PlantID         uint       `json:"plant_id"`
+    EnergyManagerID uint       `json:"energy_manager_id"`
+    Role            string     `json:"role"`
+    ValidFrom       time.Time  `json:"valid_from"`
+    ValidTo         *time.Time `json:"valid_to"`
+    CreatedAt       time.Time  `json:"created_at"`
+    UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func NewPlantAssignment(assignment *models.PlantAssignment) PlantAssignment {
+    return PlantAssignment{
+        ID: assignment.ID,
+        PlantID: assignment.PlantID,
+        EnergyManagerID: assignment.EnergyManagerID,
+        Role: assignment.Role,
+        ValidFrom: assignment.ValidFrom,
+        ValidTo: assignment.ValidTo,
+        CreatedAt: assignment.CreatedAt,
+        UpdatedAt: assignment.UpdatedAt,
+    }
+}
+
+func NewPlantAssignments(assignments []models.PlantAssignment) []PlantAssignment {
+    res := make([]PlantAssignment, len(assignments))
+    for i := range assignments {
+        res[i] = NewPlantAssignment(&assignments[i])
+    }
+    return res
+}
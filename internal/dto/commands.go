@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type Agent struct {
+    ID        uint      `json:"id"`
+    Name      string    `json:"name"`
+    PlantID   uint      `json:"plant_id"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Command struct {
+    ID                 uint                `json:"id"`
+    AgentID            uint                `json:"agent_id"`
+    AssetID            uint                `json:"asset_id"`
+    DispatchSetpointID *uint               `json:"dispatch_setpoint_id"`
+    Setpoint           models.Power        `json:"setpoint"`
+    Status             string              `json:"status"`
+    Attempts           uint                `json:"attempts"`
+    DeliveredAt        *time.Time          `json:"delivered_at"`
+    AckDeadline        *time.Time          `json:"ack_deadline"`
+    History            []CommandTransition `json:"history"`
+    CreatedAt          time.Time           `json:"created_at"`
+    UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+type CommandTransition struct {
+    ID        uint      `json:"id"`
+    From      string    `json:"from"`
+    To        string    `json:"to"`
+    Reason    string    `json:"reason"`
+    CreatedAt time.Time `json:"created_at"`
+}
+
+func NewAgent(agent *models.Agent) Agent {
+    return Agent{
+        ID: agent.ID,
+        Name: agent.Name,
+        PlantID: agent.PlantID,
+        CreatedAt: agent.CreatedAt,
+        UpdatedAt: agent.UpdatedAt,
+    }
+}
+
+func NewAgents(agents []models.Agent) []Agent {
+    res := make([]Agent, len(agents))
+    for i := range agents {
+        res[i] = NewAgent(&agents[i])
+    }
+    return res
+}
+
+func NewCommand(command *models.Command) Command {
+    history := make([]CommandTransition, len(command.History))
+    for i, transition := range command.History {
+        history[i] = CommandTransition{
+            ID: transition.ID,
+            From: transition.From,
+            To: transition.To,
+            Reason: transition.Reason,
+            CreatedAt: transition.CreatedAt,
+        }
+    }
+    return Command{
+        ID: command.ID,
+        AgentID: command.AgentID,
+        AssetID: command.AssetID,
+        DispatchSetpointID: command.DispatchSetpointID,
+        Setpoint: command.Setpoint,
+        Status: command.Status,
+        Attempts: command.Attempts,
+        DeliveredAt: command.DeliveredAt,
+        AckDeadline: command.AckDeadline,
+        History: history,
+        CreatedAt: command.CreatedAt,
+        UpdatedAt: command.UpdatedAt,
+    }
+}
+
+func NewCommands(commands []models.Command) []Command {
+    res := make([]Command, len(commands))
+    for i := range commands {
+        res[i] = NewCommand(&commands[i])
+    }
+    return res
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type GridConnection struct {
+    ID                uint         `json:"id"`
+    PlantID           uint         `json:"plant_id"`
+    ConnectionPointID string       `json:"connection_point_id"`
+    VoltageLevel      string       `json:"voltage_level"`
+    ImportCapacity    models.Power `json:"import_capacity"`
+    ExportCapacity    models.Power `json:"export_capacity"`
+    ValidFrom         time.Time    `json:"valid_from"`
+    ValidTo           *time.Time   `json:"valid_to"`
+    CreatedAt         time.Time    `json:"created_at"`
+    UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+type ComplianceIssue struct {
+    Level        string       `json:"level"`
+    Code         string       `json:"code"`
+    ConnectionID *uint        `json:"connection_id"`
+    At           *time.Time   `json:"at"`
+    Value        models.Power `json:"value"`
+    Limit        models.Power `json:"limit"`
+}
+
+type ConnectionCompliance struct {
+    PlantID    uint              `json:"plant_id"`
+    From       time.Time         `json:"from"`
+    To         time.Time         `json:"to"`
+    Connection *GridConnection   `json:"connection"`
+    AssetPower models.Power      `json:"asset_power"`
+    Compliant  bool              `json:"compliant"`
+    Issues     []ComplianceIssue `json:"issues"`
+}
+
+func NewGridConnection(connection *models.GridConnection) GridConnection {
+    return GridConnection{
+        ID: connection.ID,
+        PlantID: connection.PlantID,
+        ConnectionPointID: connection.ConnectionPointID,
+        VoltageLevel: connection.VoltageLevel,
+        ImportCapacity: connection.ImportCapacity,
+        ExportCapacity: connection.ExportCapacity,
+        ValidFrom: connection.ValidFrom,
+        ValidTo: connection.ValidTo,
+        CreatedAt: connection.CreatedAt,
+        UpdatedAt: connection.UpdatedAt,
+    }
+}
+
+func NewGridConnections(connections []models.GridConnection) []GridConnection {
+    res := make([]GridConnection, len(connections))
+    for i := range connections {
+        res[i] = NewGridConnection(&connections[i])
+    }
+    return res
+}
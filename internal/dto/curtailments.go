@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type CurtailmentEvent struct {
+    ID          uint      `json:"id"`
+    PlantID     uint      `json:"plant_id"`
+    TargetPower models.Power `json:"target_power"`
+    StartsAt    time.Time    `json:"starts_at"`
+    EndsAt      time.Time    `json:"ends_at"`
+    Status      string       `json:"status"`
+    CreatedAt   time.Time    `json:"created_at"`
+    UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type CurtailmentEvaluation struct {
+    CurtailmentID  uint         `json:"curtailment_id"`
+    RequestedPower models.Power `json:"requested_power"`
+    BaselinePower  float64      `json:"baseline_power"`
+    MeasuredPower  float64      `json:"measured_power"`
+    DeliveredPower float64      `json:"delivered_power"`
+    DeliveryRatio  float64      `json:"delivery_ratio"`
+    Met            bool         `json:"met"`
+}
+
+func NewCurtailmentEvent(curtailment *models.CurtailmentEvent) CurtailmentEvent {
+    return CurtailmentEvent{
+        ID: curtailment.ID,
+        PlantID: curtailment.PlantID,
+        TargetPower: curtailment.TargetPower,
+        StartsAt: curtailment.StartsAt,
+        EndsAt: curtailment.EndsAt,
+        Status: curtailment.Status,
+        CreatedAt: curtailment.CreatedAt,
+        UpdatedAt: curtailment.UpdatedAt,
+    }
+}
+
+func NewCurtailmentEvents(curtailments []models.CurtailmentEvent) []CurtailmentEvent {
+    res := make([]CurtailmentEvent, len(curtailments))
+    for i := range curtailments {
+        res[i] = NewCurtailmentEvent(&curtailments[i])
+    }
+    return res
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type DispatchPlan struct {
+    ID              uint               `json:"id"`
+    PlantID         uint               `json:"plant_id"`
+    CurtailmentID   *uint              `json:"curtailment_id"`
+    TargetReduction models.Power       `json:"target_reduction"`
+    StartsAt        time.Time          `json:"starts_at"`
+    EndsAt          time.Time          `json:"ends_at"`
+    Setpoints       []DispatchSetpoint `json:"setpoints"`
+    CreatedAt       time.Time          `json:"created_at"`
+    UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+type DispatchSetpoint struct {
+    ID           uint         `json:"id"`
+    AssetID      uint         `json:"asset_id"`
+    Reduction    models.Power `json:"reduction"`
+    Setpoint     models.Power `json:"setpoint"`
+    RampStartsAt time.Time    `json:"ramp_starts_at"`
+    StartsAt     time.Time    `json:"starts_at"`
+    EndsAt       time.Time    `json:"ends_at"`
+}
+
+func NewDispatchPlan(plan *models.DispatchPlan) DispatchPlan {
+    setpoints := make([]DispatchSetpoint, len(plan.Setpoints))
+    for i, setpoint := range plan.Setpoints {
+        setpoints[i] = DispatchSetpoint{
+            ID: setpoint.ID,
+            AssetID: setpoint.AssetID,
+            Reduction: setpoint.Reduction,
+            Setpoint: setpoint.Setpoint,
+            RampStartsAt: setpoint.RampStartsAt,
+            StartsAt: setpoint.StartsAt,
+            EndsAt: setpoint.EndsAt,
+        }
+    }
+    return DispatchPlan{
+        ID: plan.ID,
+        PlantID: plan.PlantID,
+        CurtailmentID: plan.CurtailmentID,
+        TargetReduction: plan.TargetReduction,
+        StartsAt: plan.StartsAt,
+        EndsAt: plan.EndsAt,
+        Setpoints: setpoints,
+        CreatedAt: plan.CreatedAt,
+        UpdatedAt: plan.UpdatedAt,
+    }
+}
+
+func NewDispatchPlans(plans []models.DispatchPlan) []DispatchPlan {
+    res := make([]DispatchPlan, len(plans))
+    for i := range plans {
+        res[i] = NewDispatchPlan(&plans[i])
+    }
+    return res
+}
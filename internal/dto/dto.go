@@ -0,0 +1,280 @@
+// Package dto holds the response bodies of the version 2 contract of the API.
+// They are written from the models field by field, so that a change to the
+// models does not show in the responses unless it is carried over here. Keys
+// are snake_case, like those of the request bodies. There is one file per
+// model file, energy managers, plants and assets being here.
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type EnergyManager struct {
+    ID        uint      `json:"id"`
+    Name      string    `json:"name"`
+    Surname   string    `json:"surname"`
+    Email     string    `json:"email"`
+    Phone     string    `json:"phone"`
+    Timezone  string    `json:"timezone"`
+    Language  string    `json:"language"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+    // Plants is only given when it is included.
+    Plants *[]Plant `json:"plants,omitempty"`
+}
+
+type Plant struct {
+    ID          uint         `json:"id"`
+    Name        string       `json:"name"`
+    Address     string       `json:"address"`
+    MaxPower    models.Power `json:"max_power"`
+    Latitude    *float64     `json:"latitude"`
+    Longitude   *float64     `json:"longitude"`
+    GridZone    string       `json:"grid_zone"`
+    CountryCode string       `json:"country_code"`
+    Timezone    string       `json:"timezone"`
+    TariffID    *uint        `json:"tariff_id"`
+    // EnergyManagerID is the id of the primary energy manager, always given.
+    EnergyManagerID *uint     `json:"energy_manager_id"`
+    CreatedAt   time.Time    `json:"created_at"`
+    UpdatedAt   time.Time    `json:"updated_at"`
+    // Assets and EnergyManager are only given when they are included.
+    Assets        *[]Asset       `json:"assets,omitempty"`
+    EnergyManager *EnergyManager `json:"energy_manager,omitempty"`
+}
+
+type Asset struct {
+    ID              uint         `json:"id"`
+    PlantID         uint         `json:"plant_id"`
+    Name            string       `json:"name"`
+    Type            string       `json:"type"`
+    Availability    string       `json:"availability"`
+    MaxPower        models.Power `json:"max_power"`
+    MinPower        models.Power `json:"min_power"`
+    RampUpRate      uint         `json:"ramp_up_rate"`
+    RampDownRate    uint         `json:"ramp_down_rate"`
+    MinRunTime      uint         `json:"min_run_time"`
+    MinOffTime      uint         `json:"min_off_time"`
+    MaxEventsPerDay uint         `json:"max_events_per_day"`
+    NoticePeriod    uint         `json:"notice_period"`
+    Priority        uint         `json:"priority"`
+    AgentID         *uint        `json:"agent_id"`
+    GroupID         *uint        `json:"group_id"`
+    CreatedAt       time.Time    `json:"created_at"`
+    UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+type NotificationPreference struct {
+    ID              uint      `json:"id"`
+    EnergyManagerID uint      `json:"energy_manager_id"`
+    Channel         string    `json:"channel"`
+    Enabled         bool      `json:"enabled"`
+    Target          string    `json:"target"`
+    CreatedAt       time.Time `json:"created_at"`
+    UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func NewEnergyManager(em *models.EnergyManager) EnergyManager {
+    res := EnergyManager{
+        ID: em.ID,
+        Name: em.Name,
+        Surname: em.Surname,
+        Email: em.Email,
+        Phone: em.Phone,
+        Timezone: em.Timezone,
+        Language: em.Language,
+        CreatedAt: em.CreatedAt,
+        UpdatedAt: em.UpdatedAt,
+    }
+    if em.Plants != nil {
+        plants := NewPlants(em.Plants)
+        res.Plants = &plants
+    }
+    return res
+}
+
+func NewEnergyManagers(ems []models.EnergyManager) []EnergyManager {
+    res := make([]EnergyManager, len(ems))
+    for i := range ems {
+        res[i] = NewEnergyManager(&ems[i])
+    }
+    return res
+}
+
+func NewPlant(plant *models.Plant) Plant {
+    res := Plant{
+        ID: plant.ID,
+        Name: plant.Name,
+        Address: plant.Address,
+        MaxPower: plant.MaxPower,
+        Latitude: plant.Latitude,
+        Longitude: plant.Longitude,
+        GridZone: plant.GridZone,
+        CountryCode: plant.CountryCode,
+        Timezone: plant.Timezone,
+        TariffID: plant.TariffID,
+        EnergyManagerID: plant.EnergyManagerID,
+        CreatedAt: plant.CreatedAt,
+        UpdatedAt: plant.UpdatedAt,
+    }
+    if plant.Assets != nil {
+        assets := NewAssets(plant.Assets)
+        res.Assets = &assets
+    }
+    if plant.EnergyManager != nil {
+        em := NewEnergyManager(plant.EnergyManager)
+        res.EnergyManager = &em
+    }
+    return res
+}
+
+func NewPlants(plants []models.Plant) []Plant {
+    res := make([]Plant, len(plants))
+    for i := range plants {
+        res[i] = NewPlant(&plants[i])
+    }
+    return res
+}
+
+func NewAsset(asset *models.Asset) Asset {
+    return Asset{
+        ID: asset.ID,
+        PlantID: asset.PlantID,
+        Name: asset.Name,
+        Type: asset.Type,
+        Availability: asset.Availability,
+        MaxPower: asset.MaxPower,
+        MinPower: asset.MinPower,
+        RampUpRate: asset.RampUpRate,
+        RampDownRate: asset.RampDownRate,
+        MinRunTime: asset.MinRunTime,
+        MinOffTime: asset.MinOffTime,
+        MaxEventsPerDay: asset.MaxEventsPerDay,
+        NoticePeriod: asset.NoticePeriod,
+        Priority: asset.Priority,
+        AgentID: asset.AgentID,
+        GroupID: asset.GroupID,
+        CreatedAt: asset.CreatedAt,
+        UpdatedAt: asset.UpdatedAt,
+    }
+}
+
+func NewAssets(assets []models.Asset) []Asset {
+    res := make([]Asset, len(assets))
+    for i := range assets {
+        res[i] = NewAsset(&assets[i])
+    }
+    return res
+}
+
+func NewNotificationPreference(preference *models.NotificationPreference) NotificationPreference {
+    return NotificationPreference{
+        ID: preference.ID,
+        EnergyManagerID: preference.EnergyManagerID,
+        Channel: preference.Channel,
+        Enabled: preference.Enabled,
+        Target: preference.Target,
+        CreatedAt: preference.CreatedAt,
+        UpdatedAt: preference.UpdatedAt,
+    }
+}
+
+func NewNotificationPreferences(preferences []models.NotificationPreference) []NotificationPreference {
+    res := make([]NotificationPreference, len(preferences))
+    for i := range preferences {
+        res[i] = NewNotificationPreference(&preferences[i])
+    }
+    return res
+}
+
+// New converts the resources and the results the service returns, alone or in
+// slices, to their response bodies. Single resources are returned as
+// pointers, for their powers to be rendered in a unit. Other values are
+// returned as they are.
+func New(v interface{}) interface{} {
+    switch v := v.(type) {
+    case *models.EnergyManager:
+        res := NewEnergyManager(v)
+        return &res
+    case []models.EnergyManager:
+        return NewEnergyManagers(v)
+    case *models.Plant:
+        res := NewPlant(v)
+        return &res
+    case []models.Plant:
+        return NewPlants(v)
+    case *models.Asset:
+        res := NewAsset(v)
+        return &res
+    case []models.Asset:
+        return NewAssets(v)
+    case []models.NotificationPreference:
+        return NewNotificationPreferences(v)
+    case *models.AssetMove:
+        res := NewAssetMove(v)
+        return &res
+    case []models.AssetMove:
+        return NewAssetMoves(v)
+    case *models.PlantAssignment:
+        res := NewPlantAssignment(v)
+        return &res
+    case []models.PlantAssignment:
+        return NewPlantAssignments(v)
+    case *models.GridConnection:
+        res := NewGridConnection(v)
+        return &res
+    case []models.GridConnection:
+        return NewGridConnections(v)
+    case *models.AssetGroup:
+        res := NewAssetGroup(v)
+        return &res
+    case []models.AssetGroup:
+        return NewAssetGroups(v)
+    case []models.MaintenanceWindow:
+        return NewMaintenanceWindows(v)
+    case *models.CurtailmentEvent:
+        res := NewCurtailmentEvent(v)
+        return &res
+    case []models.CurtailmentEvent:
+        return NewCurtailmentEvents(v)
+    case []models.Measurement:
+        return NewMeasurements(v)
+    case *models.DispatchPlan:
+        res := NewDispatchPlan(v)
+        return &res
+    case []models.DispatchPlan:
+        return NewDispatchPlans(v)
+    case *models.Command:
+        res := NewCommand(v)
+        return &res
+    case []models.Command:
+        return NewCommands(v)
+    case *models.Agent:
+        res := NewAgent(v)
+        return &res
+    case []models.Agent:
+        return NewAgents(v)
+    case *models.WebhookSubscription:
+        res := NewWebhookSubscription(v)
+        return &res
+    case []models.WebhookSubscription:
+        return NewWebhookSubscriptions(v)
+    case []models.WebhookDelivery:
+        return NewWebhookDeliveries(v)
+    case *models.Tariff:
+        res := NewTariff(v)
+        return &res
+    case []models.Tariff:
+        return NewTariffs(v)
+    case []models.EmissionFactor:
+        return NewEmissionFactors(v)
+    case *models.Event:
+        res := NewEventMessage(v)
+        return &res
+    case []models.Event:
+        return NewEventMessages(v)
+    }
+    return v
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type MainTestSuite struct {
+    suite.Suite
+}
+
+func TestDTO(t *testing.T) {
+    suite.Run(t, new(MainTestSuite))
+}
+
+func (t *MainTestSuite) TestNew() {
+    plant := models.Plant{Name: "plant", MaxPower: models.Kilowatts(1000)}
+    plant.ID = 1
+    b, err := json.Marshal(New(&plant))
+    t.Require().NoError(err)
+    var res map[string]interface{}
+    t.Require().NoError(json.Unmarshal(b, &res))
+    t.Equal(1000.0, res["max_power"])
+    t.NotContains(res, "DeletedAt")
+    t.NotContains(res, "assets")
+
+    // included relationships are given even when empty
+    plant.Assets = []models.Asset{}
+    b, _ = json.Marshal(New(&plant))
+    t.Contains(string(b), `"assets":[]`)
+
+    // other values are left as they are
+    t.Equal("plant", New("plant"))
+
+    // every resource has its body, without its secrets
+    subscription := models.WebhookSubscription{URL: "https://example.com", Secret: "secret"}
+    b, _ = json.Marshal(New(&subscription))
+    t.JSONEq(`{"id": 0, "url": "https://example.com", "events": "", "created_at": "0001-01-01T00:00:00Z", "updated_at": "0001-01-01T00:00:00Z"}`, string(b))
+    tariff := models.Tariff{Name: "green", Periods: []models.TariffPeriod{{Name: "all", EnergyPrice: 0.2}}}
+    b, _ = json.Marshal(New(&tariff))
+    t.Contains(string(b), `"periods":[{"id":0,"name":"all","start_month":0`)
+}
+
+func (t *MainTestSuite) TestSelect() {
+    fields, err := ParseFields("name, max_power")
+    t.Require().NoError(err)
+    t.Equal([]string{"name", "max_power"}, fields)
+    fields, err = ParseFields("")
+    t.Require().NoError(err)
+    t.Nil(fields)
+    _, err = ParseFields("name,")
+    t.ErrorIs(err, ErrFields)
+
+    plants := NewPlants([]models.Plant{{Name: "a"}, {Name: "b"}})
+    selected, err := Select(plants, []string{"name"})
+    t.Require().NoError(err)
+    b, _ := json.Marshal(selected)
+    t.JSONEq(`[{"id": 0, "name": "a"}, {"id": 0, "name": "b"}]`, string(b))
+
+    em := NewEnergyManager(&models.EnergyManager{Name: "Gerard", Surname: "Depardieu"})
+    selected, err = Select(&em, []string{"surname"})
+    t.Require().NoError(err)
+    b, _ = json.Marshal(selected)
+    t.JSONEq(`{"id": 0, "surname": "Depardieu"}`, string(b))
+
+    _, err = Select(&em, []string{"max_power"})
+    t.ErrorIs(err, ErrFields)
+    selected, err = Select(&em, nil)
+    t.Require().NoError(err)
+    t.Equal(&em, selected)
+}
+
+func (t *MainTestSuite) TestSelectEmbedded() {
+    // the fields of embedded structs can be selected too
+    costs := &PlantCosts{PlantID: 1, CostBreakdown: CostBreakdown{TotalCost: 12.5}}
+    selected, err := Select(costs, []string{"total_cost"})
+    t.Require().NoError(err)
+    b, _ := json.Marshal(selected)
+    t.JSONEq(`{"total_cost": 12.5}`, string(b))
+}
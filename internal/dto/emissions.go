@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type EmissionFactor struct {
+    ID        uint       `json:"id"`
+    Zone      string     `json:"zone"`
+    StartsAt  *time.Time `json:"starts_at"`
+    Factor    float64    `json:"factor"`
+    CreatedAt time.Time  `json:"created_at"`
+    UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type EmissionsBreakdown struct {
+    Energy        float64 `json:"energy"`
+    Emissions     float64 `json:"emissions"`
+    UnknownEnergy float64 `json:"unknown_energy"`
+}
+
+type AssetEmissions struct {
+    AssetID uint `json:"asset_id"`
+    EmissionsBreakdown
+}
+
+type PlantEmissions struct {
+    PlantID uint      `json:"plant_id"`
+    From    time.Time `json:"from"`
+    To      time.Time `json:"to"`
+    EmissionsBreakdown
+    Assets  []AssetEmissions `json:"assets"`
+}
+
+type EnergyManagerEmissions struct {
+    EnergyManagerID uint      `json:"energy_manager_id"`
+    From            time.Time `json:"from"`
+    To              time.Time `json:"to"`
+    EmissionsBreakdown
+    Plants          []PlantEmissions `json:"plants"`
+}
+
+func NewEmissionFactor(factor *models.EmissionFactor) EmissionFactor {
+    return EmissionFactor{
+        ID: factor.ID,
+        Zone: factor.Zone,
+        StartsAt: factor.StartsAt,
+        Factor: factor.Factor,
+        CreatedAt: factor.CreatedAt,
+        UpdatedAt: factor.UpdatedAt,
+    }
+}
+
+func NewEmissionFactors(factors []models.EmissionFactor) []EmissionFactor {
+    res := make([]EmissionFactor, len(factors))
+    for i := range factors {
+        res[i] = NewEmissionFactor(&factors[i])
+    }
+    return res
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// EventMessage is how an event of the log is sent to streams and webhooks:
+// what happened, when, and the resource it happened to as this contract
+// renders it. Deleted resources are only given by their ids.
+type EventMessage struct {
+    ID         uint            `json:"id"`
+    Type       string          `json:"type"`
+    OccurredAt time.Time       `json:"occurred_at"`
+    Data       json.RawMessage `json:"data"`
+}
+
+func NewEventMessage(event *models.Event) EventMessage {
+    return EventMessage{
+        ID: event.ID,
+        Type: event.Type,
+        OccurredAt: event.CreatedAt.UTC(),
+        Data: event.Data,
+    }
+}
+
+func NewEventMessages(events []models.Event) []EventMessage {
+    res := make([]EventMessage, len(events))
+    for i := range events {
+        res[i] = NewEventMessage(&events[i])
+    }
+    return res
+}
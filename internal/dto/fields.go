@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var (
+    ErrFields = errors.New("Fields must be a comma separated list of fields of the resource, like 'name,max_power'")
+)
+
+// ParseFields reads a comma separated list of fields, like 'name,max_power'.
+// An empty list selects every field.
+func ParseFields(param string) ([]string, error) {
+    if strings.TrimSpace(param) == "" {
+        return nil, nil
+    }
+    fields := []string{}
+    for _, field := range strings.Split(param, ",") {
+        field = strings.TrimSpace(field)
+        if field == "" {
+            return nil, ErrFields
+        }
+        fields = append(fields, field)
+    }
+    return fields, nil
+}
+
+// jsonFields returns the keys a struct is rendered with, those of its
+// embedded structs included.
+func jsonFields(t reflect.Type) map[string]bool {
+    keys := map[string]bool{}
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        name := strings.Split(field.Tag.Get("json"), ",")[0]
+        if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+            for key := range jsonFields(field.Type) {
+                keys[key] = true
+            }
+        } else if name != "" && name != "-" {
+            keys[name] = true
+        }
+    }
+    return keys
+}
+
+// Select keeps the given fields of a resource, or of every resource of a
+// slice, along with its id. It returns v as it is when fields is empty, and
+// ErrFields when one of them is not a field of the resource.
+func Select(v interface{}, fields []string) (interface{}, error) {
+    if len(fields) == 0 {
+        return v, nil
+    }
+    t := reflect.TypeOf(v)
+    for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+        t = t.Elem()
+    }
+    if t.Kind() != reflect.Struct {
+        return nil, ErrFields
+    }
+    known := jsonFields(t)
+    keep := map[string]bool{"id": true}
+    for _, field := range fields {
+        if !known[field] {
+            return nil, ErrFields
+        }
+        keep[field] = true
+    }
+
+    b, err := json.Marshal(v)
+    if err != nil {
+        return nil, err
+    }
+    filter := func(resource map[string]json.RawMessage) {
+        for key := range resource {
+            if !keep[key] {
+                delete(resource, key)
+            }
+        }
+    }
+    if reflect.TypeOf(v).Kind() == reflect.Slice {
+        var resources []map[string]json.RawMessage
+        if err := json.Unmarshal(b, &resources); err != nil {
+            return nil, err
+        }
+        for _, resource := range resources {
+            filter(resource)
+        }
+        return resources, nil
+    }
+    var resource map[string]json.RawMessage
+    if err := json.Unmarshal(b, &resource); err != nil {
+        return nil, err
+    }
+    filter(resource)
+    return resource, nil
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type AssetGroup struct {
+    ID        uint         `json:"id"`
+    PlantID   uint         `json:"plant_id"`
+    ParentID  *uint        `json:"parent_id"`
+    Name      string       `json:"name"`
+    Kind      string       `json:"kind"`
+    MaxPower  models.Power `json:"max_power"`
+    CreatedAt time.Time    `json:"created_at"`
+    UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// GroupNode is a group of a PlantTree, with its assets and subgroups.
+type GroupNode struct {
+    AssetGroup
+    Assets   []Asset     `json:"assets"`
+    Children []GroupNode `json:"children"`
+}
+
+type PlantTree struct {
+    PlantID uint        `json:"plant_id"`
+    Assets  []Asset     `json:"assets"`
+    Groups  []GroupNode `json:"groups"`
+}
+
+func NewAssetGroup(group *models.AssetGroup) AssetGroup {
+    return AssetGroup{
+        ID: group.ID,
+        PlantID: group.PlantID,
+        ParentID: group.ParentID,
+        Name: group.Name,
+        Kind: group.Kind,
+        MaxPower: group.MaxPower,
+        CreatedAt: group.CreatedAt,
+        UpdatedAt: group.UpdatedAt,
+    }
+}
+
+func NewAssetGroups(groups []models.AssetGroup) []AssetGroup {
+    res := make([]AssetGroup, len(groups))
+    for i := range groups {
+        res[i] = NewAssetGroup(&groups[i])
+    }
+    return res
+}
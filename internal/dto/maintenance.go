@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type MaintenanceWindow struct {
+    ID        uint      `json:"id"`
+    AssetID   uint      `json:"asset_id"`
+    StartsAt  time.Time `json:"starts_at"`
+    EndsAt    time.Time `json:"ends_at"`
+    RRule     string    `json:"rrule"`
+    Reason    string    `json:"reason"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+type MaintenanceOccurrence struct {
+    MaintenanceID uint      `json:"maintenance_id"`
+    StartsAt      time.Time `json:"starts_at"`
+    EndsAt        time.Time `json:"ends_at"`
+}
+
+type AssetAvailability struct {
+    AssetID   uint      `json:"asset_id"`
+    From      time.Time `json:"from"`
+    To        time.Time `json:"to"`
+    Available bool      `json:"available"`
+    Reason    string    `json:"reason,omitempty"`
+}
+
+func NewMaintenanceWindow(window *models.MaintenanceWindow) MaintenanceWindow {
+    return MaintenanceWindow{
+        ID: window.ID,
+        AssetID: window.AssetID,
+        StartsAt: window.StartsAt,
+        EndsAt: window.EndsAt,
+        RRule: window.RRule,
+        Reason: window.Reason,
+        CreatedAt: window.CreatedAt,
+        UpdatedAt: window.UpdatedAt,
+    }
+}
+
+func NewMaintenanceWindows(windows []models.MaintenanceWindow) []MaintenanceWindow {
+    res := make([]MaintenanceWindow, len(windows))
+    for i := range windows {
+        res[i] = NewMaintenanceWindow(&windows[i])
+    }
+    return res
+}
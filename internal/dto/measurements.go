@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type Measurement struct {
+    ID        uint      `json:"id"`
+    PlantID   uint      `json:"plant_id"`
+    AssetID   *uint     `json:"asset_id"`
+    Timestamp time.Time `json:"timestamp"`
+    Power     float64   `json:"power"`
+}
+
+type LoadBucket struct {
+    StartsAt     time.Time `json:"starts_at"`
+    EndsAt       time.Time `json:"ends_at"`
+    Readings     int       `json:"readings"`
+    AveragePower *float64  `json:"average_power"`
+    Energy       *float64  `json:"energy"`
+}
+
+type LoadCurve struct {
+    PlantID  uint         `json:"plant_id"`
+    Timezone string       `json:"timezone"`
+    Interval string       `json:"interval"`
+    Buckets  []LoadBucket `json:"buckets"`
+}
+
+func NewMeasurement(measurement *models.Measurement) Measurement {
+    return Measurement{
+        ID: measurement.ID,
+        PlantID: measurement.PlantID,
+        AssetID: measurement.AssetID,
+        Timestamp: measurement.Timestamp,
+        Power: measurement.Power,
+    }
+}
+
+func NewMeasurements(measurements []models.Measurement) []Measurement {
+    res := make([]Measurement, len(measurements))
+    for i := range measurements {
+        res[i] = NewMeasurement(&measurements[i])
+    }
+    return res
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type AssetMove struct {
+    ID          uint      `json:"id"`
+    AssetID     uint      `json:"asset_id"`
+    FromPlantID uint      `json:"from_plant_id"`
+    ToPlantID   uint      `json:"to_plant_id"`
+    FromGroupID *uint     `json:"from_group_id"`
+    ToGroupID   *uint     `json:"to_group_id"`
+    Reason      string    `json:"reason"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewAssetMove(move *models.AssetMove) AssetMove {
+    return AssetMove{
+        ID: move.ID,
+        AssetID: move.AssetID,
+        FromPlantID: move.FromPlantID,
+        ToPlantID: move.ToPlantID,
+        FromGroupID: move.FromGroupID,
+        ToGroupID: move.ToGroupID,
+        Reason: move.Reason,
+        CreatedAt: move.CreatedAt,
+    }
+}
+
+func NewAssetMoves(moves []models.AssetMove) []AssetMove {
+    res := make([]AssetMove, len(moves))
+    for i := range moves {
+        res[i] = NewAssetMove(&moves[i])
+    }
+    return res
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type Tariff struct {
+    ID        uint           `json:"id"`
+    Name      string         `json:"name"`
+    Currency  string         `json:"currency"`
+    Periods   []TariffPeriod `json:"periods"`
+    CreatedAt time.Time      `json:"created_at"`
+    UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type TariffPeriod struct {
+    ID          uint    `json:"id"`
+    Name        string  `json:"name"`
+    StartMonth  uint    `json:"start_month"`
+    EndMonth    uint    `json:"end_month"`
+    Days        string  `json:"days"`
+    StartTime   string  `json:"start_time"`
+    EndTime     string  `json:"end_time"`
+    EnergyPrice float64 `json:"energy_price"`
+    DemandPrice float64 `json:"demand_price"`
+}
+
+type CostBreakdown struct {
+    Energy     float64 `json:"energy"`
+    EnergyCost float64 `json:"energy_cost"`
+    DemandCost float64 `json:"demand_cost"`
+    TotalCost  float64 `json:"total_cost"`
+}
+
+type PeriodCost struct {
+    PeriodID uint   `json:"period_id"`
+    Name     string `json:"name"`
+    CostBreakdown
+}
+
+type AssetCost struct {
+    AssetID uint `json:"asset_id"`
+    CostBreakdown
+}
+
+type PlantCosts struct {
+    PlantID  uint      `json:"plant_id"`
+    TariffID uint      `json:"tariff_id"`
+    Currency string    `json:"currency"`
+    From     time.Time `json:"from"`
+    To       time.Time `json:"to"`
+    CostBreakdown
+    Periods  []PeriodCost `json:"periods"`
+    Assets   []AssetCost  `json:"assets"`
+}
+
+func NewTariff(tariff *models.Tariff) Tariff {
+    periods := make([]TariffPeriod, len(tariff.Periods))
+    for i, period := range tariff.Periods {
+        periods[i] = TariffPeriod{
+            ID: period.ID,
+            Name: period.Name,
+            StartMonth: period.StartMonth,
+            EndMonth: period.EndMonth,
+            Days: period.Days,
+            StartTime: period.StartTime,
+            EndTime: period.EndTime,
+            EnergyPrice: period.EnergyPrice,
+            DemandPrice: period.DemandPrice,
+        }
+    }
+    return Tariff{
+        ID: tariff.ID,
+        Name: tariff.Name,
+        Currency: tariff.Currency,
+        Periods: periods,
+        CreatedAt: tariff.CreatedAt,
+        UpdatedAt: tariff.UpdatedAt,
+    }
+}
+
+func NewTariffs(tariffs []models.Tariff) []Tariff {
+    res := make([]Tariff, len(tariffs))
+    for i := range tariffs {
+        res[i] = NewTariff(&tariffs[i])
+    }
+    return res
+}
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type PlantAssignment struct {
+    Model
+    PlantID         uint
+    EnergyManagerID uint
+    Role            string
+    ValidFrom       time.Time
+    ValidTo         *time.Time
+}
+
+func NewPlantAssignment(assignment *models.PlantAssignment) PlantAssignment {
+    return PlantAssignment{
+        Model: newModel(assignment.Model),
+        PlantID: assignment.PlantID,
+        EnergyManagerID: assignment.EnergyManagerID,
+        Role: assignment.Role,
+        ValidFrom: assignment.ValidFrom,
+        ValidTo: assignment.ValidTo,
+    }
+}
+
+func NewPlantAssignments(assignments []models.PlantAssignment) []PlantAssignment {
+    res := make([]PlantAssignment, len(assignments))
+    for i := range assignments {
+        res[i] = NewPlantAssignment(&assignments[i])
+    }
+    return res
+}
@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type Agent struct {
+    Model
+    Name    string
+    PlantID uint
+}
+
+type Command struct {
+    Model
+    AgentID            uint
+    AssetID            uint
+    DispatchSetpointID *uint
+    Setpoint           models.Power
+    Status             string
+    Attempts           uint
+    DeliveredAt        *time.Time
+    AckDeadline        *time.Time
+    History            []CommandTransition
+}
+
+type CommandTransition struct {
+    Model
+    CommandID uint
+    From      string
+    To        string
+    Reason    string
+}
+
+func NewAgent(agent *models.Agent) Agent {
+    return Agent{
+        Model: newModel(agent.Model),
+        Name: agent.Name,
+        PlantID: agent.PlantID,
+    }
+}
+
+func NewAgents(agents []models.Agent) []Agent {
+    res := make([]Agent, len(agents))
+    for i := range agents {
+        res[i] = NewAgent(&agents[i])
+    }
+    return res
+}
+
+func NewCommand(command *models.Command) Command {
+    res := Command{
+        Model: newModel(command.Model),
+        AgentID: command.AgentID,
+        AssetID: command.AssetID,
+        DispatchSetpointID: command.DispatchSetpointID,
+        Setpoint: command.Setpoint,
+        Status: command.Status,
+        Attempts: command.Attempts,
+        DeliveredAt: command.DeliveredAt,
+        AckDeadline: command.AckDeadline,
+    }
+    if command.History != nil {
+        res.History = make([]CommandTransition, len(command.History))
+        for i, transition := range command.History {
+            res.History[i] = CommandTransition{
+                Model: newModel(transition.Model),
+                CommandID: transition.CommandID,
+                From: transition.From,
+                To: transition.To,
+                Reason: transition.Reason,
+            }
+        }
+    }
+    return res
+}
+
+func NewCommands(commands []models.Command) []Command {
+    res := make([]Command, len(commands))
+    for i := range commands {
+        res[i] = NewCommand(&commands[i])
+    }
+    return res
+}
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type GridConnection struct {
+    Model
+    PlantID           uint
+    ConnectionPointID string
+    VoltageLevel      string
+    ImportCapacity    models.Power
+    ExportCapacity    models.Power
+    ValidFrom         time.Time
+    ValidTo           *time.Time
+}
+
+type ComplianceIssue struct {
+    Level        string       `json:"level"`
+    Code         string       `json:"code"`
+    ConnectionID *uint        `json:"connection_id"`
+    At           *time.Time   `json:"at"`
+    Value        models.Power `json:"value"`
+    Limit        models.Power `json:"limit"`
+}
+
+type ConnectionCompliance struct {
+    PlantID    uint              `json:"plant_id"`
+    From       time.Time         `json:"from"`
+    To         time.Time         `json:"to"`
+    Connection *GridConnection   `json:"connection"`
+    AssetPower models.Power      `json:"asset_power"`
+    Compliant  bool              `json:"compliant"`
+    Issues     []ComplianceIssue `json:"issues"`
+}
+
+func NewGridConnection(connection *models.GridConnection) GridConnection {
+    return GridConnection{
+        Model: newModel(connection.Model),
+        PlantID: connection.PlantID,
+        ConnectionPointID: connection.ConnectionPointID,
+        VoltageLevel: connection.VoltageLevel,
+        ImportCapacity: connection.ImportCapacity,
+        ExportCapacity: connection.ExportCapacity,
+        ValidFrom: connection.ValidFrom,
+        ValidTo: connection.ValidTo,
+    }
+}
+
+func NewGridConnections(connections []models.GridConnection) []GridConnection {
+    res := make([]GridConnection, len(connections))
+    for i := range connections {
+        res[i] = NewGridConnection(&connections[i])
+    }
+    return res
+}
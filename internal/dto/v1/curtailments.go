@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type CurtailmentEvent struct {
+    Model
+    PlantID     uint
+    TargetPower uint
+    StartsAt    time.Time
+    EndsAt      time.Time
+    Status      string
+}
+
+type CurtailmentEvaluation struct {
+    CurtailmentID  uint    `json:"curtailment_id"`
+    RequestedPower uint    `json:"requested_power"`
+    BaselinePower  float64 `json:"baseline_power"`
+    MeasuredPower  float64 `json:"measured_power"`
+    DeliveredPower float64 `json:"delivered_power"`
+    DeliveryRatio  float64 `json:"delivery_ratio"`
+    Met            bool    `json:"met"`
+}
+
+func NewCurtailmentEvent(curtailment *models.CurtailmentEvent) CurtailmentEvent {
+    return CurtailmentEvent{
+        Model: newModel(curtailment.Model),
+        PlantID: curtailment.PlantID,
+        TargetPower: Kilowatts(curtailment.TargetPower),
+        StartsAt: curtailment.StartsAt,
+        EndsAt: curtailment.EndsAt,
+        Status: curtailment.Status,
+    }
+}
+
+func NewCurtailmentEvents(curtailments []models.CurtailmentEvent) []CurtailmentEvent {
+    res := make([]CurtailmentEvent, len(curtailments))
+    for i := range curtailments {
+        res[i] = NewCurtailmentEvent(&curtailments[i])
+    }
+    return res
+}
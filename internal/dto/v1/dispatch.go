@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type DispatchPlan struct {
+    Model
+    PlantID         uint
+    CurtailmentID   *uint
+    TargetReduction models.Power
+    StartsAt        time.Time
+    EndsAt          time.Time
+    Setpoints       []DispatchSetpoint
+}
+
+type DispatchSetpoint struct {
+    Model
+    DispatchPlanID uint
+    AssetID        uint
+    Reduction      models.Power
+    Setpoint       models.Power
+    RampStartsAt   time.Time
+    StartsAt       time.Time
+    EndsAt         time.Time
+}
+
+func NewDispatchPlan(plan *models.DispatchPlan) DispatchPlan {
+    res := DispatchPlan{
+        Model: newModel(plan.Model),
+        PlantID: plan.PlantID,
+        CurtailmentID: plan.CurtailmentID,
+        TargetReduction: plan.TargetReduction,
+        StartsAt: plan.StartsAt,
+        EndsAt: plan.EndsAt,
+    }
+    if plan.Setpoints != nil {
+        res.Setpoints = make([]DispatchSetpoint, len(plan.Setpoints))
+        for i, setpoint := range plan.Setpoints {
+            res.Setpoints[i] = DispatchSetpoint{
+                Model: newModel(setpoint.Model),
+                DispatchPlanID: setpoint.DispatchPlanID,
+                AssetID: setpoint.AssetID,
+                Reduction: setpoint.Reduction,
+                Setpoint: setpoint.Setpoint,
+                RampStartsAt: setpoint.RampStartsAt,
+                StartsAt: setpoint.StartsAt,
+                EndsAt: setpoint.EndsAt,
+            }
+        }
+    }
+    return res
+}
+
+func NewDispatchPlans(plans []models.DispatchPlan) []DispatchPlan {
+    res := make([]DispatchPlan, len(plans))
+    for i := range plans {
+        res[i] = NewDispatchPlan(&plans[i])
+    }
+    return res
+}
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type EmissionFactor struct {
+    Model
+    Zone     string
+    StartsAt *time.Time
+    Factor   float64
+}
+
+type EmissionsBreakdown struct {
+    Energy        float64 `json:"energy"`
+    Emissions     float64 `json:"emissions"`
+    UnknownEnergy float64 `json:"unknown_energy"`
+}
+
+type AssetEmissions struct {
+    AssetID uint `json:"asset_id"`
+    EmissionsBreakdown
+}
+
+type PlantEmissions struct {
+    PlantID uint      `json:"plant_id"`
+    From    time.Time `json:"from"`
+    To      time.Time `json:"to"`
+    EmissionsBreakdown
+    Assets  []AssetEmissions `json:"assets"`
+}
+
+type EnergyManagerEmissions struct {
+    EnergyManagerID uint      `json:"energy_manager_id"`
+    From            time.Time `json:"from"`
+    To              time.Time `json:"to"`
+    EmissionsBreakdown
+    Plants          []PlantEmissions `json:"plants"`
+}
+
+func NewEmissionFactors(factors []models.EmissionFactor) []EmissionFactor {
+    res := make([]EmissionFactor, len(factors))
+    for i, factor := range factors {
+        res[i] = EmissionFactor{
+            Model: newModel(factor.Model),
+            Zone: factor.Zone,
+            StartsAt: factor.StartsAt,
+            Factor: factor.Factor,
+        }
+    }
+    return res
+}
@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// EventMessage keeps the snake_case envelope the streams always had, its
+// data is the resource as version 1 renders it.
+type EventMessage struct {
+    ID         uint            `json:"id"`
+    Type       string          `json:"type"`
+    OccurredAt time.Time       `json:"occurred_at"`
+    Data       json.RawMessage `json:"data"`
+}
+
+func NewEventMessage(event *models.Event) EventMessage {
+    data := event.DataV1
+    if data == nil {
+        // logged before the data was kept in both contracts, when it was
+        // the models as they are rendered here
+        data = event.Data
+    }
+    return EventMessage{
+        ID: event.ID,
+        Type: event.Type,
+        OccurredAt: event.CreatedAt.UTC(),
+        Data: data,
+    }
+}
+
+func NewEventMessages(events []models.Event) []EventMessage {
+    res := make([]EventMessage, len(events))
+    for i := range events {
+        res[i] = NewEventMessage(&events[i])
+    }
+    return res
+}
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type AssetFlexibility struct {
+    AssetID   uint         `json:"asset_id"`
+    Upward    models.Power `json:"upward"`
+    Downward  models.Power `json:"downward"`
+    Available bool         `json:"available"`
+    Reason    string       `json:"reason,omitempty"`
+}
+
+type PlantFlexibility struct {
+    PlantID  uint               `json:"plant_id"`
+    From     time.Time          `json:"from"`
+    To       time.Time          `json:"to"`
+    Upward   models.Power       `json:"upward"`
+    Downward models.Power       `json:"downward"`
+    Assets   []AssetFlexibility `json:"assets"`
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type AssetGroup struct {
+    Model
+    PlantID  uint
+    ParentID *uint
+    Name     string
+    Kind     string
+    MaxPower models.Power
+}
+
+type GroupNode struct {
+    AssetGroup
+    Assets   []Asset     `json:"assets"`
+    Children []GroupNode `json:"children"`
+}
+
+type PlantTree struct {
+    PlantID uint        `json:"plant_id"`
+    Assets  []Asset     `json:"assets"`
+    Groups  []GroupNode `json:"groups"`
+}
+
+func NewAssetGroup(group *models.AssetGroup) AssetGroup {
+    return AssetGroup{
+        Model: newModel(group.Model),
+        PlantID: group.PlantID,
+        ParentID: group.ParentID,
+        Name: group.Name,
+        Kind: group.Kind,
+        MaxPower: group.MaxPower,
+    }
+}
+
+func NewAssetGroups(groups []models.AssetGroup) []AssetGroup {
+    res := make([]AssetGroup, len(groups))
+    for i := range groups {
+        res[i] = NewAssetGroup(&groups[i])
+    }
+    return res
+}
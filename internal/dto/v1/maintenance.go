@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type MaintenanceWindow struct {
+    Model
+    AssetID  uint
+    StartsAt time.Time
+    EndsAt   time.Time
+    RRule    string
+    Reason   string
+}
+
+type MaintenanceOccurrence struct {
+    MaintenanceID uint      `json:"maintenance_id"`
+    StartsAt      time.Time `json:"starts_at"`
+    EndsAt        time.Time `json:"ends_at"`
+}
+
+type AssetAvailability struct {
+    AssetID   uint      `json:"asset_id"`
+    From      time.Time `json:"from"`
+    To        time.Time `json:"to"`
+    Available bool      `json:"available"`
+    Reason    string    `json:"reason,omitempty"`
+}
+
+func NewMaintenanceWindows(windows []models.MaintenanceWindow) []MaintenanceWindow {
+    res := make([]MaintenanceWindow, len(windows))
+    for i, window := range windows {
+        res[i] = MaintenanceWindow{
+            Model: newModel(window.Model),
+            AssetID: window.AssetID,
+            StartsAt: window.StartsAt,
+            EndsAt: window.EndsAt,
+            RRule: window.RRule,
+            Reason: window.Reason,
+        }
+    }
+    return res
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type Measurement struct {
+    Model
+    PlantID   uint
+    AssetID   *uint
+    Timestamp time.Time
+    Power     float64
+}
+
+type LoadBucket struct {
+    StartsAt     time.Time `json:"starts_at"`
+    EndsAt       time.Time `json:"ends_at"`
+    Readings     int       `json:"readings"`
+    AveragePower *float64  `json:"average_power"`
+    Energy       *float64  `json:"energy"`
+}
+
+type LoadCurve struct {
+    PlantID  uint         `json:"plant_id"`
+    Timezone string       `json:"timezone"`
+    Interval string       `json:"interval"`
+    Buckets  []LoadBucket `json:"buckets"`
+}
+
+func NewMeasurements(measurements []models.Measurement) []Measurement {
+    res := make([]Measurement, len(measurements))
+    for i, measurement := range measurements {
+        res[i] = Measurement{
+            Model: newModel(measurement.Model),
+            PlantID: measurement.PlantID,
+            AssetID: measurement.AssetID,
+            Timestamp: measurement.Timestamp,
+            Power: measurement.Power,
+        }
+    }
+    return res
+}
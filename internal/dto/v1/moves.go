@@ -0,0 +1,33 @@
+package v1
+
+import "github.com/jeandeducla/api-plant/internal/models"
+
+type AssetMove struct {
+    Model
+    AssetID     uint
+    FromPlantID uint
+    ToPlantID   uint
+    FromGroupID *uint
+    ToGroupID   *uint
+    Reason      string
+}
+
+func NewAssetMove(move *models.AssetMove) AssetMove {
+    return AssetMove{
+        Model: newModel(move.Model),
+        AssetID: move.AssetID,
+        FromPlantID: move.FromPlantID,
+        ToPlantID: move.ToPlantID,
+        FromGroupID: move.FromGroupID,
+        ToGroupID: move.ToGroupID,
+        Reason: move.Reason,
+    }
+}
+
+func NewAssetMoves(moves []models.AssetMove) []AssetMove {
+    res := make([]AssetMove, len(moves))
+    for i := range moves {
+        res[i] = NewAssetMove(&moves[i])
+    }
+    return res
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type Tariff struct {
+    Model
+    Name     string
+    Currency string
+    Periods  []TariffPeriod
+}
+
+type TariffPeriod struct {
+    Model
+    TariffID    uint
+    Name        string
+    StartMonth  uint
+    EndMonth    uint
+    Days        string
+    StartTime   string
+    EndTime     string
+    EnergyPrice float64
+    DemandPrice float64
+}
+
+type CostBreakdown struct {
+    Energy     float64 `json:"energy"`
+    EnergyCost float64 `json:"energy_cost"`
+    DemandCost float64 `json:"demand_cost"`
+    TotalCost  float64 `json:"total_cost"`
+}
+
+type PeriodCost struct {
+    PeriodID uint   `json:"period_id"`
+    Name     string `json:"name"`
+    CostBreakdown
+}
+
+type AssetCost struct {
+    AssetID uint `json:"asset_id"`
+    CostBreakdown
+}
+
+type PlantCosts struct {
+    PlantID  uint      `json:"plant_id"`
+    TariffID uint      `json:"tariff_id"`
+    Currency string    `json:"currency"`
+    From     time.Time `json:"from"`
+    To       time.Time `json:"to"`
+    CostBreakdown
+    Periods  []PeriodCost `json:"periods"`
+    Assets   []AssetCost  `json:"assets"`
+}
+
+func NewTariff(tariff *models.Tariff) Tariff {
+    res := Tariff{
+        Model: newModel(tariff.Model),
+        Name: tariff.Name,
+        Currency: tariff.Currency,
+    }
+    if tariff.Periods != nil {
+        res.Periods = make([]TariffPeriod, len(tariff.Periods))
+        for i, period := range tariff.Periods {
+            res.Periods[i] = TariffPeriod{
+                Model: newModel(period.Model),
+                TariffID: period.TariffID,
+                Name: period.Name,
+                StartMonth: period.StartMonth,
+                EndMonth: period.EndMonth,
+                Days: period.Days,
+                StartTime: period.StartTime,
+                EndTime: period.EndTime,
+                EnergyPrice: period.EnergyPrice,
+                DemandPrice: period.DemandPrice,
+            }
+        }
+    }
+    return res
+}
+
+func NewTariffs(tariffs []models.Tariff) []Tariff {
+    res := make([]Tariff, len(tariffs))
+    for i := range tariffs {
+        res[i] = NewTariff(&tariffs[i])
+    }
+    return res
+}
@@ -0,0 +1,288 @@
+// Package v1 holds the response bodies of the version 1 contract of the API,
+// which is deprecated and frozen. They have the keys the models were rendered
+// with when version 2 came out, like `MaxPower` and `DeletedAt`, and are
+// written from the models field by field, so that no change to the models
+// shows in them. Nothing is to be added here: new fields go to version 2.
+// Powers are whole numbers of kW and the primary energy manager of a plant is
+// 0 when it has none, as they were when the API started.
+package v1
+
+import (
+	"math"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// Model is the gorm.Model the models embed.
+type Model struct {
+    ID        uint
+    CreatedAt time.Time
+    UpdatedAt time.Time
+    DeletedAt *time.Time
+}
+
+type EnergyManager struct {
+    Model
+    Name     string
+    Surname  string
+    Email    string
+    Phone    string
+    Timezone string
+    Language string
+    Plants   []Plant
+}
+
+type NotificationPreference struct {
+    Model
+    EnergyManagerID uint
+    Channel         string
+    Enabled         bool
+    Target          string
+}
+
+type Plant struct {
+    Model
+    Name            string
+    Address         string
+    MaxPower        uint
+    Latitude        *float64
+    Longitude       *float64
+    GridZone        string
+    CountryCode     string
+    Timezone        string
+    TariffID        *uint
+    EnergyManagerID uint
+    Assets          []Asset
+    EnergyManager   *EnergyManager `json:",omitempty"`
+}
+
+type Asset struct {
+    Model
+    Name            string
+    MaxPower        uint
+    Type            string
+    Availability    string
+    PlantID         uint
+    GroupID         *uint
+    AgentID         *uint
+    MinPower        uint
+    RampUpRate      uint
+    RampDownRate    uint
+    MinRunTime      uint
+    MinOffTime      uint
+    MaxEventsPerDay uint
+    NoticePeriod    uint
+    Priority        uint
+}
+
+func newModel(model gorm.Model) Model {
+    return Model{
+        ID: model.ID,
+        CreatedAt: model.CreatedAt,
+        UpdatedAt: model.UpdatedAt,
+        DeletedAt: model.DeletedAt,
+    }
+}
+
+// Kilowatts rounds a power to the whole kW version 1 renders.
+func Kilowatts(power models.Power) uint {
+    return uint(math.Round(power.Kilowatts()))
+}
+
+func NewEnergyManager(em *models.EnergyManager) EnergyManager {
+    res := EnergyManager{
+        Model: newModel(em.Model),
+        Name: em.Name,
+        Surname: em.Surname,
+        Email: em.Email,
+        Phone: em.Phone,
+        Timezone: em.Timezone,
+        Language: em.Language,
+    }
+    if em.Plants != nil {
+        res.Plants = NewPlants(em.Plants)
+    }
+    return res
+}
+
+func NewEnergyManagers(ems []models.EnergyManager) []EnergyManager {
+    res := make([]EnergyManager, len(ems))
+    for i := range ems {
+        res[i] = NewEnergyManager(&ems[i])
+    }
+    return res
+}
+
+func NewNotificationPreferences(preferences []models.NotificationPreference) []NotificationPreference {
+    res := make([]NotificationPreference, len(preferences))
+    for i, preference := range preferences {
+        res[i] = NotificationPreference{
+            Model: newModel(preference.Model),
+            EnergyManagerID: preference.EnergyManagerID,
+            Channel: preference.Channel,
+            Enabled: preference.Enabled,
+            Target: preference.Target,
+        }
+    }
+    return res
+}
+
+func NewPlant(plant *models.Plant) Plant {
+    res := Plant{
+        Model: newModel(plant.Model),
+        Name: plant.Name,
+        Address: plant.Address,
+        MaxPower: Kilowatts(plant.MaxPower),
+        Latitude: plant.Latitude,
+        Longitude: plant.Longitude,
+        GridZone: plant.GridZone,
+        CountryCode: plant.CountryCode,
+        Timezone: plant.Timezone,
+        TariffID: plant.TariffID,
+    }
+    if plant.EnergyManagerID != nil {
+        res.EnergyManagerID = *plant.EnergyManagerID
+    }
+    if plant.Assets != nil {
+        res.Assets = NewAssets(plant.Assets)
+    }
+    if plant.EnergyManager != nil {
+        em := NewEnergyManager(plant.EnergyManager)
+        res.EnergyManager = &em
+    }
+    return res
+}
+
+func NewPlants(plants []models.Plant) []Plant {
+    res := make([]Plant, len(plants))
+    for i := range plants {
+        res[i] = NewPlant(&plants[i])
+    }
+    return res
+}
+
+func NewAsset(asset *models.Asset) Asset {
+    return Asset{
+        Model: newModel(asset.Model),
+        Name: asset.Name,
+        MaxPower: Kilowatts(asset.MaxPower),
+        Type: asset.Type,
+        Availability: asset.Availability,
+        PlantID: asset.PlantID,
+        GroupID: asset.GroupID,
+        AgentID: asset.AgentID,
+        MinPower: Kilowatts(asset.MinPower),
+        RampUpRate: asset.RampUpRate,
+        RampDownRate: asset.RampDownRate,
+        MinRunTime: asset.MinRunTime,
+        MinOffTime: asset.MinOffTime,
+        MaxEventsPerDay: asset.MaxEventsPerDay,
+        NoticePeriod: asset.NoticePeriod,
+        Priority: asset.Priority,
+    }
+}
+
+// NewAssets keeps a nil slice nil, rendered as null, as the models did.
+func NewAssets(assets []models.Asset) []Asset {
+    if assets == nil {
+        return nil
+    }
+    res := make([]Asset, len(assets))
+    for i := range assets {
+        res[i] = NewAsset(&assets[i])
+    }
+    return res
+}
+
+// New converts the resources and the results the service returns, alone or in
+// slices, to their response bodies. Single resources are returned as
+// pointers, for their powers to be rendered in a unit. Other values are
+// returned as they are.
+func New(v interface{}) interface{} {
+    switch v := v.(type) {
+    case *models.EnergyManager:
+        res := NewEnergyManager(v)
+        return &res
+    case []models.EnergyManager:
+        return NewEnergyManagers(v)
+    case *models.Plant:
+        res := NewPlant(v)
+        return &res
+    case []models.Plant:
+        return NewPlants(v)
+    case *models.Asset:
+        res := NewAsset(v)
+        return &res
+    case []models.Asset:
+        return NewAssets(v)
+    case []models.NotificationPreference:
+        return NewNotificationPreferences(v)
+    case *models.AssetMove:
+        res := NewAssetMove(v)
+        return &res
+    case []models.AssetMove:
+        return NewAssetMoves(v)
+    case *models.PlantAssignment:
+        res := NewPlantAssignment(v)
+        return &res
+    case []models.PlantAssignment:
+        return NewPlantAssignments(v)
+    case *models.GridConnection:
+        res := NewGridConnection(v)
+        return &res
+    case []models.GridConnection:
+        return NewGridConnections(v)
+    case *models.AssetGroup:
+        res := NewAssetGroup(v)
+        return &res
+    case []models.AssetGroup:
+        return NewAssetGroups(v)
+    case []models.MaintenanceWindow:
+        return NewMaintenanceWindows(v)
+    case *models.CurtailmentEvent:
+        res := NewCurtailmentEvent(v)
+        return &res
+    case []models.CurtailmentEvent:
+        return NewCurtailmentEvents(v)
+    case []models.Measurement:
+        return NewMeasurements(v)
+    case *models.DispatchPlan:
+        res := NewDispatchPlan(v)
+        return &res
+    case []models.DispatchPlan:
+        return NewDispatchPlans(v)
+    case *models.Command:
+        res := NewCommand(v)
+        return &res
+    case []models.Command:
+        return NewCommands(v)
+    case *models.Agent:
+        res := NewAgent(v)
+        return &res
+    case []models.Agent:
+        return NewAgents(v)
+    case *models.WebhookSubscription:
+        res := NewWebhookSubscription(v)
+        return &res
+    case []models.WebhookSubscription:
+        return NewWebhookSubscriptions(v)
+    case []models.WebhookDelivery:
+        return NewWebhookDeliveries(v)
+    case *models.Tariff:
+        res := NewTariff(v)
+        return &res
+    case []models.Tariff:
+        return NewTariffs(v)
+    case []models.EmissionFactor:
+        return NewEmissionFactors(v)
+    case *models.Event:
+        res := NewEventMessage(v)
+        return &res
+    case []models.Event:
+        return NewEventMessages(v)
+    }
+    return v
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type MainTestSuite struct {
+    suite.Suite
+}
+
+func TestV1(t *testing.T) {
+    suite.Run(t, new(MainTestSuite))
+}
+
+// TestNew pins the bodies of version 1: they must never change.
+func (t *MainTestSuite) TestNew() {
+    at := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    em_id := uint(1)
+    plant := models.Plant{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        Timezone: "UTC",
+        EnergyManagerID: &em_id,
+    }
+    plant.ID = 1
+    plant.CreatedAt = at
+    plant.UpdatedAt = at
+    b, err := json.Marshal(New(&plant))
+    t.Require().NoError(err)
+    t.JSONEq(`{
+        "ID": 1,
+        "CreatedAt": "2022-05-02T08:00:00Z",
+        "UpdatedAt": "2022-05-02T08:00:00Z",
+        "DeletedAt": null,
+        "Name": "plant1",
+        "Address": "17 rue truc",
+        "MaxPower": 1000,
+        "Latitude": null,
+        "Longitude": null,
+        "GridZone": "",
+        "CountryCode": "",
+        "Timezone": "UTC",
+        "TariffID": null,
+        "EnergyManagerID": 1,
+        "Assets": null
+    }`, string(b))
+
+    asset := models.Asset{Name: "furnace", Type: "furnace", MaxPower: models.Kilowatts(200), PlantID: 1}
+    asset.ID = 3
+    asset.CreatedAt = at
+    asset.UpdatedAt = at
+    b, err = json.Marshal(New(&asset))
+    t.Require().NoError(err)
+    t.JSONEq(`{
+        "ID": 3,
+        "CreatedAt": "2022-05-02T08:00:00Z",
+        "UpdatedAt": "2022-05-02T08:00:00Z",
+        "DeletedAt": null,
+        "Name": "furnace",
+        "MaxPower": 200,
+        "Type": "furnace",
+        "Availability": "",
+        "PlantID": 1,
+        "GroupID": null,
+        "AgentID": null,
+        "MinPower": 0,
+        "RampUpRate": 0,
+        "RampDownRate": 0,
+        "MinRunTime": 0,
+        "MinOffTime": 0,
+        "MaxEventsPerDay": 0,
+        "NoticePeriod": 0,
+        "Priority": 0
+    }`, string(b))
+
+    // the secret of a webhook is never given
+    subscription := models.WebhookSubscription{URL: "https://example.com", Secret: "secret", Events: "plant.*"}
+    subscription.ID = 2
+    subscription.CreatedAt = at
+    subscription.UpdatedAt = at
+    b, err = json.Marshal(New([]models.WebhookSubscription{subscription}))
+    t.Require().NoError(err)
+    t.JSONEq(`[{
+        "ID": 2,
+        "CreatedAt": "2022-05-02T08:00:00Z",
+        "UpdatedAt": "2022-05-02T08:00:00Z",
+        "DeletedAt": null,
+        "URL": "https://example.com",
+        "Events": "plant.*"
+    }]`, string(b))
+
+    // included relationships are given as they were
+    plant.EnergyManager = &models.EnergyManager{Name: "Gerard", Surname: "Depardieu"}
+    plant.Assets = []models.Asset{asset}
+    b, err = json.Marshal(New(&plant))
+    t.Require().NoError(err)
+    var res map[string]interface{}
+    t.Require().NoError(json.Unmarshal(b, &res))
+    t.Equal("Gerard", res["EnergyManager"].(map[string]interface{})["Name"])
+    t.Nil(res["EnergyManager"].(map[string]interface{})["Plants"])
+    t.Equal("furnace", res["Assets"].([]interface{})[0].(map[string]interface{})["Name"])
+
+    // other values are left as they are
+    t.Equal("plant", New("plant"))
+}
+
+// TestBaseline compares the bodies to the responses of the first release of
+// the API, in testdata: every key they had is still given, with the same
+// value. Powers are whole kW and a plant without energy manager has 0.
+func (t *MainTestSuite) TestBaseline() {
+    at := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    em := models.EnergyManager{Name: "Gerard", Surname: "Depardieu"}
+    em.ID = 1
+    plant := models.Plant{Name: "plant1", Address: "17 rue truc", MaxPower: models.Kilowatts(1000)}
+    plant.ID = 1
+    asset := models.Asset{Name: "furnace", Type: "furnace", MaxPower: models.Watts(199600), PlantID: 1}
+    asset.ID = 3
+    for _, model := range []*gorm.Model{&em.Model, &plant.Model, &asset.Model} {
+        model.CreatedAt = at
+        model.UpdatedAt = at
+    }
+
+    for golden, res := range map[string]interface{}{
+        "em.json": New(&em),
+        "plant.json": New(&plant),
+        "asset.json": New(&asset),
+    } {
+        b, err := os.ReadFile(filepath.Join("testdata", golden))
+        t.Require().NoError(err)
+        var expected map[string]interface{}
+        t.Require().NoError(json.Unmarshal(b, &expected))
+        b, err = json.Marshal(res)
+        t.Require().NoError(err)
+        var actual map[string]interface{}
+        t.Require().NoError(json.Unmarshal(b, &actual))
+        for key, value := range expected {
+            t.Contains(actual, key, golden)
+            t.Equal(value, actual[key], golden+": "+key)
+        }
+    }
+}
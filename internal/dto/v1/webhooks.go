@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+type WebhookSubscription struct {
+    Model
+    URL    string
+    Events string
+}
+
+type WebhookDelivery struct {
+    Model
+    SubscriptionID uint
+    EventID        uint
+    Event          string
+    Payload        json.RawMessage
+    Status         string
+    Attempts       uint
+    NextAttemptAt  time.Time
+    LastError      string
+    DeliveredAt    *time.Time
+}
+
+func NewWebhookSubscription(subscription *models.WebhookSubscription) WebhookSubscription {
+    return WebhookSubscription{
+        Model: newModel(subscription.Model),
+        URL: subscription.URL,
+        Events: subscription.Events,
+    }
+}
+
+func NewWebhookSubscriptions(subscriptions []models.WebhookSubscription) []WebhookSubscription {
+    res := make([]WebhookSubscription, len(subscriptions))
+    for i := range subscriptions {
+        res[i] = NewWebhookSubscription(&subscriptions[i])
+    }
+    return res
+}
+
+func NewWebhookDeliveries(deliveries []models.WebhookDelivery) []WebhookDelivery {
+    res := make([]WebhookDelivery, len(deliveries))
+    for i, delivery := range deliveries {
+        res[i] = WebhookDelivery{
+            Model: newModel(delivery.Model),
+            SubscriptionID: delivery.SubscriptionID,
+            EventID: delivery.EventID,
+            Event: delivery.Event,
+            Payload: delivery.Payload,
+            Status: delivery.Status,
+            Attempts: delivery.Attempts,
+            NextAttemptAt: delivery.NextAttemptAt,
+            LastError: delivery.LastError,
+            DeliveredAt: delivery.DeliveredAt,
+        }
+    }
+    return res
+}
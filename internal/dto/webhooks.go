@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// WebhookSubscription never gives the secret of the subscription.
+type WebhookSubscription struct {
+    ID        uint      `json:"id"`
+    URL       string    `json:"url"`
+    Events    string    `json:"events"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+    ID             uint            `json:"id"`
+    SubscriptionID uint            `json:"subscription_id"`
+    EventID        uint            `json:"event_id"`
+    Event          string          `json:"event"`
+    Payload        json.RawMessage `json:"payload"`
+    Status         string          `json:"status"`
+    Attempts       uint            `json:"attempts"`
+    NextAttemptAt  time.Time       `json:"next_attempt_at"`
+    LastError      string          `json:"last_error"`
+    DeliveredAt    *time.Time      `json:"delivered_at"`
+    CreatedAt      time.Time       `json:"created_at"`
+    UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+func NewWebhookSubscription(subscription *models.WebhookSubscription) WebhookSubscription {
+    return WebhookSubscription{
+        ID: subscription.ID,
+        URL: subscription.URL,
+        Events: subscription.Events,
+        CreatedAt: subscription.CreatedAt,
+        UpdatedAt: subscription.UpdatedAt,
+    }
+}
+
+func NewWebhookSubscriptions(subscriptions []models.WebhookSubscription) []WebhookSubscription {
+    res := make([]WebhookSubscription, len(subscriptions))
+    for i := range subscriptions {
+        res[i] = NewWebhookSubscription(&subscriptions[i])
+    }
+    return res
+}
+
+func NewWebhookDelivery(delivery *models.WebhookDelivery) WebhookDelivery {
+    return WebhookDelivery{
+        ID: delivery.ID,
+        SubscriptionID: delivery.SubscriptionID,
+        EventID: delivery.EventID,
+        Event: delivery.Event,
+        Payload: delivery.Payload,
+        Status: delivery.Status,
+        Attempts: delivery.Attempts,
+        NextAttemptAt: delivery.NextAttemptAt,
+        LastError: delivery.LastError,
+        DeliveredAt: delivery.DeliveredAt,
+        CreatedAt: delivery.CreatedAt,
+        UpdatedAt: delivery.UpdatedAt,
+    }
+}
+
+func NewWebhookDeliveries(deliveries []models.WebhookDelivery) []WebhookDelivery {
+    res := make([]WebhookDelivery, len(deliveries))
+    for i := range deliveries {
+        res[i] = NewWebhookDelivery(&deliveries[i])
+    }
+    return res
+}
@@ -0,0 +1,109 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadius is the mean radius of the earth, in km.
+const earthRadius = 6371.0088
+
+var (
+    ErrPoint = errors.New("A point must be written 'latitude,longitude' with a latitude in [-90, 90] and a longitude in [-180, 180]")
+    ErrBBox = errors.New("A bbox must be written 'min_longitude,min_latitude,max_longitude,max_latitude'")
+)
+
+type Point struct {
+    Latitude  float64
+    Longitude float64
+}
+
+func (p Point) Valid() bool {
+    return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+// Distance is the great circle distance between two points, in km.
+func Distance(a Point, b Point) float64 {
+    lat1 := a.Latitude * math.Pi / 180
+    lat2 := b.Latitude * math.Pi / 180
+    dLat := lat2 - lat1
+    dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+    h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+    return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// LatitudeSpan is how many degrees of latitude a distance in km covers.
+func LatitudeSpan(km float64) float64 {
+    return km / earthRadius * 180 / math.Pi
+}
+
+func parseFloats(s string, n int) ([]float64, bool) {
+    parts := strings.Split(s, ",")
+    if len(parts) != n {
+        return nil, false
+    }
+    res := make([]float64, n)
+    for i, part := range parts {
+        f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+        if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+            return nil, false
+        }
+        res[i] = f
+    }
+    return res, true
+}
+
+// ParsePoint reads a 'latitude,longitude' pair.
+func ParsePoint(s string) (Point, error) {
+    f, ok := parseFloats(s, 2)
+    if !ok {
+        return Point{}, ErrPoint
+    }
+    p := Point{Latitude: f[0], Longitude: f[1]}
+    if !p.Valid() {
+        return Point{}, ErrPoint
+    }
+    return p, nil
+}
+
+// BBox is a latitude/longitude rectangle. MinLongitude is bigger than
+// MaxLongitude when the box crosses the antimeridian.
+type BBox struct {
+    MinLongitude float64
+    MinLatitude  float64
+    MaxLongitude float64
+    MaxLatitude  float64
+}
+
+// ParseBBox reads a box in the GeoJSON order:
+// 'min_longitude,min_latitude,max_longitude,max_latitude'.
+func ParseBBox(s string) (BBox, error) {
+    f, ok := parseFloats(s, 4)
+    if !ok {
+        return BBox{}, ErrBBox
+    }
+    b := BBox{MinLongitude: f[0], MinLatitude: f[1], MaxLongitude: f[2], MaxLatitude: f[3]}
+    min := Point{Latitude: b.MinLatitude, Longitude: b.MinLongitude}
+    max := Point{Latitude: b.MaxLatitude, Longitude: b.MaxLongitude}
+    if !min.Valid() || !max.Valid() || b.MinLatitude > b.MaxLatitude {
+        return BBox{}, ErrBBox
+    }
+    return b, nil
+}
+
+func (b BBox) CrossesAntimeridian() bool {
+    return b.MinLongitude > b.MaxLongitude
+}
+
+func (b BBox) Contains(p Point) bool {
+    if p.Latitude < b.MinLatitude || p.Latitude > b.MaxLatitude {
+        return false
+    }
+    if b.CrossesAntimeridian() {
+        return p.Longitude >= b.MinLongitude || p.Longitude <= b.MaxLongitude
+    }
+    return p.Longitude >= b.MinLongitude && p.Longitude <= b.MaxLongitude
+}
@@ -0,0 +1,80 @@
+package geo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MainTestSuite struct {
+    suite.Suite
+}
+
+func TestGeo(t *testing.T) {
+    suite.Run(t, new(MainTestSuite))
+}
+
+func (t *MainTestSuite) TestDistance() {
+    paris := Point{Latitude: 48.8566, Longitude: 2.3522}
+    lyon := Point{Latitude: 45.7640, Longitude: 4.8357}
+    t.InDelta(392, Distance(paris, lyon), 2)
+    t.InDelta(0, Distance(paris, paris), 1e-9)
+
+    // the shortest way goes through the antimeridian
+    a := Point{Latitude: 0, Longitude: 179.5}
+    b := Point{Latitude: 0, Longitude: -179.5}
+    t.InDelta(111.2, Distance(a, b), 0.5)
+}
+
+func (t *MainTestSuite) TestParse() {
+    p, err := ParsePoint("48.85, 2.35")
+    t.Require().NoError(err)
+    t.Equal(Point{Latitude: 48.85, Longitude: 2.35}, p)
+    for _, s := range []string{"", "48.85", "91,2", "48.85,181", "a,b", "1,2,3"} {
+        _, err := ParsePoint(s)
+        t.ErrorIs(err, ErrPoint, s)
+    }
+
+    b, err := ParseBBox("-5,42,8,51")
+    t.Require().NoError(err)
+    t.True(b.Contains(Point{Latitude: 48.85, Longitude: 2.35}))
+    t.False(b.Contains(Point{Latitude: 52.52, Longitude: 13.40}))
+    for _, s := range []string{"-5,42,8", "-5,51,8,42", "-5,42,8,95"} {
+        _, err := ParseBBox(s)
+        t.ErrorIs(err, ErrBBox, s)
+    }
+
+    // a box around Fiji crosses the antimeridian
+    b, err = ParseBBox("177,-20,-178,-15")
+    t.Require().NoError(err)
+    t.True(b.CrossesAntimeridian())
+    t.True(b.Contains(Point{Latitude: -18, Longitude: 178.4}))
+    t.True(b.Contains(Point{Latitude: -18, Longitude: -179}))
+    t.False(b.Contains(Point{Latitude: -18, Longitude: 0}))
+}
+
+func (t *MainTestSuite) TestGazetteer() {
+    g, err := NewGazetteer(strings.NewReader(`# name,latitude,longitude,country_code
+Paris,48.8566,2.3522,fr
+Lyon,45.7640,4.8357,FR
+Saint-Denis,48.9362,2.3574,FR
+`))
+    t.Require().NoError(err)
+
+    location, err := g.Geocode(context.Background(), "17 rue de la Paix, 75002 Paris")
+    t.Require().NoError(err)
+    t.Equal("FR", location.CountryCode)
+    t.Equal(48.8566, location.Latitude)
+
+    // the most specific place wins and words must match entirely
+    location, err = g.Geocode(context.Background(), "1 avenue de Paris, Saint-Denis")
+    t.Require().NoError(err)
+    t.Equal(48.9362, location.Latitude)
+    _, err = g.Geocode(context.Background(), "12 rue Lyonnaise, Marseille")
+    t.ErrorIs(err, ErrNotFound)
+
+    _, err = NewGazetteer(strings.NewReader("Paris,448.8,2.35,FR\n"))
+    t.Error(err)
+}
@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+    ErrNotFound = errors.New("Address could not be located")
+)
+
+// Location is where a geocoder placed an address.
+type Location struct {
+    Point
+    CountryCode string
+}
+
+// Geocoder locates free text addresses.
+type Geocoder interface {
+    Geocode(ctx context.Context, address string) (Location, error)
+}
+
+type place struct {
+    name     string
+    location Location
+}
+
+// Gazetteer is an offline geocoder looking addresses up in a list of known
+// places, typically cities. An address is placed at the longest place name
+// it contains.
+type Gazetteer struct {
+    places []place
+}
+
+// NewGazetteer reads places from CSV records 'name,latitude,longitude,country_code'.
+func NewGazetteer(r io.Reader) (*Gazetteer, error) {
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = 4
+    reader.Comment = '#'
+
+    g := &Gazetteer{}
+    for line := 1; ; line++ {
+        record, err := reader.Read()
+        if err == io.EOF {
+            return g, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        lat, err1 := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+        lon, err2 := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+        p := Point{Latitude: lat, Longitude: lon}
+        if err1 != nil || err2 != nil || !p.Valid() {
+            return nil, fmt.Errorf("gazetteer line %d: invalid coordinates", line)
+        }
+        g.places = append(g.places, place{
+            name: strings.ToLower(strings.TrimSpace(record[0])),
+            location: Location{Point: p, CountryCode: strings.ToUpper(strings.TrimSpace(record[3]))},
+        })
+    }
+}
+
+func LoadGazetteer(path string) (*Gazetteer, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return NewGazetteer(f)
+}
+
+func isLetter(r byte) bool {
+    return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r >= 0x80
+}
+
+// containsWord tells whether word appears in s between word boundaries.
+func containsWord(s string, word string) bool {
+    for start := 0; ; {
+        i := strings.Index(s[start:], word)
+        if i < 0 {
+            return false
+        }
+        i += start
+        end := i + len(word)
+        if (i == 0 || !isLetter(s[i-1])) && (end == len(s) || !isLetter(s[end])) {
+            return true
+        }
+        start = i + 1
+    }
+}
+
+func (g *Gazetteer) Geocode(ctx context.Context, address string) (Location, error) {
+    address = strings.ToLower(address)
+    best := -1
+    for i, p := range g.places {
+        if p.name == "" || !containsWord(address, p.name) {
+            continue
+        }
+        if best < 0 || len(p.name) > len(g.places[best].name) {
+            best = i
+        }
+    }
+    if best < 0 {
+        return Location{}, ErrNotFound
+    }
+    return g.places[best].location, nil
+}
@@ -2,10 +2,28 @@ package models
 
 import "github.com/jinzhu/gorm"
 
+// Asset flexibility parameters: ramp rates are in kW per minute, run, off
+// and notice times in minutes. A zero value means no constraint. Assets with
+// a lower Priority are curtailed first. Availability is set to unavailable
+// to take an asset out of service until further notice.
 type Asset struct {
     gorm.Model
-    Name     string
-    MaxPower uint
-    Type     string
-    PlantID  uint
+    Name               string
+    MaxPower           Power  `gorm:"column:max_power_watts"`
+    Type               string
+    Availability       string `gorm:"default:available"`
+    PlantID            uint
+    GroupID            *uint
+    AgentID            *uint
+    MinPower           Power  `gorm:"column:min_power_watts"`
+    RampUpRate         uint
+    RampDownRate       uint
+    MinRunTime         uint
+    MinOffTime         uint
+    MaxEventsPerDay    uint
+    NoticePeriod       uint
+    Priority           uint
+    Commands           []Command           `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    MaintenanceWindows []MaintenanceWindow `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    Moves              []AssetMove         `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
 }
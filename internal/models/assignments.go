@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// PlantAssignment puts an energy manager in charge of a plant with a role
+// (primary, backup or on_call) from ValidFrom until ValidTo. A nil ValidTo
+// means the assignment has no end.
+type PlantAssignment struct {
+    gorm.Model
+    PlantID         uint       `gorm:"index"`
+    EnergyManagerID uint       `gorm:"index"`
+    Role            string
+    ValidFrom       time.Time
+    ValidTo         *time.Time
+}
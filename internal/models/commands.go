@@ -0,0 +1,38 @@
+package models
+
+import (
+    "time"
+
+    "github.com/jinzhu/gorm"
+)
+
+// Agent is an edge device relaying setpoints to the assets of a plant.
+type Agent struct {
+    gorm.Model
+    Name     string
+    PlantID  uint
+    Assets   []Asset   `gorm:"constraint:OnDelete:SET NULL;" json:"-"`
+    Commands []Command `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+type Command struct {
+    gorm.Model
+    AgentID            uint
+    AssetID            uint
+    DispatchSetpointID *uint
+    Setpoint           Power      `gorm:"column:setpoint_watts"`
+    Status             string
+    Attempts           uint
+    DeliveredAt        *time.Time
+    AckDeadline        *time.Time
+    History            []CommandTransition `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// CommandTransition records every status change of a command.
+type CommandTransition struct {
+    gorm.Model
+    CommandID uint
+    From      string
+    To        string
+    Reason    string
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// GridConnection is the contract connecting a plant to the grid at
+// ConnectionPointID from ValidFrom until ValidTo, a nil ValidTo meaning the
+// contract has no end. The plant may draw up to ImportCapacity from the grid
+// and inject up to ExportCapacity into it. VoltageLevel is one of lv, mv, hv
+// or ehv.
+type GridConnection struct {
+    gorm.Model
+    PlantID           uint       `gorm:"index"`
+    ConnectionPointID string
+    VoltageLevel      string
+    ImportCapacity    Power      `gorm:"column:import_capacity_watts"`
+    ExportCapacity    Power      `gorm:"column:export_capacity_watts"`
+    ValidFrom         time.Time
+    ValidTo           *time.Time
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+    "time"
+
+    "github.com/jinzhu/gorm"
+)
+
+type CurtailmentEvent struct {
+    gorm.Model
+    PlantID     uint
+    TargetPower Power `gorm:"column:target_power_watts"`
+    StartsAt    time.Time
+    EndsAt      time.Time
+    Status      string
+}
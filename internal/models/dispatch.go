@@ -0,0 +1,31 @@
+package models
+
+import (
+    "time"
+
+    "github.com/jinzhu/gorm"
+)
+
+type DispatchPlan struct {
+    gorm.Model
+    PlantID         uint
+    CurtailmentID   *uint
+    TargetReduction Power              `gorm:"column:target_reduction_watts"`
+    StartsAt        time.Time
+    EndsAt          time.Time
+    Setpoints       []DispatchSetpoint `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// DispatchSetpoint asks an asset to run at Setpoint, Reduction below its
+// full power, between StartsAt and EndsAt. The asset starts ramping down at
+// RampStartsAt so that the setpoint is reached when the window opens.
+type DispatchSetpoint struct {
+    gorm.Model
+    DispatchPlanID uint
+    AssetID        uint
+    Reduction      Power     `gorm:"column:reduction_watts"`
+    Setpoint       Power     `gorm:"column:setpoint_watts"`
+    RampStartsAt   time.Time
+    StartsAt       time.Time
+    EndsAt         time.Time
+}
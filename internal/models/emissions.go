@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// EmissionFactor is the carbon intensity of the electricity of a zone, a
+// grid zone or a country code, in gCO2e per kWh. Static factors have no
+// StartsAt and apply at any time; hourly factors apply from StartsAt for an
+// hour and take precedence over static ones.
+type EmissionFactor struct {
+    gorm.Model
+    Zone     string     `gorm:"index:idx_emission_factor_zone"`
+    StartsAt *time.Time `gorm:"index:idx_emission_factor_zone"`
+    Factor   float64
+}
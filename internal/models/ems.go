@@ -2,9 +2,30 @@ package models
 
 import "github.com/jinzhu/gorm"
 
+// EnergyManager is a person in charge of plants. Timezone is an IANA name
+// and Language a BCP 47 tag, both used to write the notifications sent to
+// them. Plants are the plants it is currently assigned to, only read when
+// they are included.
 type EnergyManager struct {
     gorm.Model
-    Name    string
-    Surname string
-    Plants  []Plant `gorm:"constraint:OnDelete:SET NULL;"`
+    Name                    string
+    Surname                 string
+    Email                   string
+    Phone                   string
+    Timezone                string `gorm:"default:UTC"`
+    Language                string `gorm:"default:en"`
+    Assignments             []PlantAssignment        `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    NotificationPreferences []NotificationPreference `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    Plants                  []Plant                  `gorm:"-" json:",omitempty"`
+}
+
+// NotificationPreference tells whether an energy manager wants to be
+// notified on a channel. Target is the address to use on that channel, the
+// email of the energy manager is used when it is empty on the email channel.
+type NotificationPreference struct {
+    gorm.Model
+    EnergyManagerID uint `gorm:"index"`
+    Channel         string
+    Enabled         bool
+    Target          string
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Event is a change to a resource, as kept in the event log. Its ID orders
+// the log. EntityType is what changed ('em', 'plant', 'asset',
+// 'curtailment' or 'alarm') and EntityID its id, PlantID the plant it belongs
+// to and EnergyManagerID the energy manager it is, if any. Data is the
+// resource as version 2 of the API rendered it then, DataV1 as version 1 did.
+type Event struct {
+    gorm.Model
+    Type            string
+    EntityType      string          `gorm:"index"`
+    EntityID        uint
+    PlantID         *uint           `gorm:"index"`
+    EnergyManagerID *uint           `gorm:"index"`
+    Data            json.RawMessage `gorm:"type:jsonb"`
+    DataV1          json.RawMessage `gorm:"type:jsonb"`
+}
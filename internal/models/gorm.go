@@ -11,7 +11,71 @@ func NewDB(dsn string) (*gorm.DB, error) {
         return nil, err
     }
 
-    db.AutoMigrate(EnergyManager{}, Plant{}, Asset{})
+    db.AutoMigrate(EnergyManager{}, Tariff{}, TariffPeriod{}, Plant{}, Asset{}, CurtailmentEvent{}, Measurement{}, DispatchPlan{}, DispatchSetpoint{}, Agent{}, Command{}, CommandTransition{}, MaintenanceWindow{}, AssetGroup{}, PlantAssignment{}, NotificationPreference{}, EmissionFactor{}, GridConnection{}, AssetMove{}, Event{}, OutboxMessage{}, WebhookSubscription{}, WebhookDelivery{}, IdempotencyKey{})
+
+    if err := migratePlantEnergyManagers(db); err != nil {
+        return nil, err
+    }
+    if err := migratePowerColumns(db); err != nil {
+        return nil, err
+    }
 
     return db, nil
 }
+
+// migratePlantEnergyManagers turns the energy manager a plant used to have
+// into its primary assignment, then drops the old column.
+func migratePlantEnergyManagers(db *gorm.DB) error {
+    if !db.Migrator().HasColumn(&Plant{}, "energy_manager_id") {
+        return nil
+    }
+    return db.Transaction(func(tx *gorm.DB) error {
+        err := tx.Exec(`
+            INSERT INTO plant_assignments (created_at, updated_at, plant_id, energy_manager_id, role, valid_from)
+            SELECT now(), now(), id, energy_manager_id, 'primary', created_at
+            FROM plants
+            WHERE energy_manager_id IN (SELECT id FROM energy_managers) AND deleted_at IS NULL
+        `).Error
+        if err != nil {
+            return err
+        }
+        return tx.Migrator().DropColumn(&Plant{}, "energy_manager_id")
+    })
+}
+
+// migratePowerColumns converts the powers that used to be whole kW into
+// watts, then drops the old columns.
+func migratePowerColumns(db *gorm.DB) error {
+    columns := []struct {
+        model interface{}
+        from  string
+        to    string
+    }{
+        {&Plant{}, "max_power", "max_power_watts"},
+        {&Asset{}, "max_power", "max_power_watts"},
+        {&Asset{}, "min_power", "min_power_watts"},
+        {&AssetGroup{}, "max_power", "max_power_watts"},
+        {&DispatchPlan{}, "target_reduction", "target_reduction_watts"},
+        {&DispatchSetpoint{}, "reduction", "reduction_watts"},
+        {&DispatchSetpoint{}, "setpoint", "setpoint_watts"},
+        {&Command{}, "setpoint", "setpoint_watts"},
+        {&CurtailmentEvent{}, "target_power", "target_power_watts"},
+    }
+    return db.Transaction(func(tx *gorm.DB) error {
+        for _, column := range columns {
+            if !tx.Migrator().HasColumn(column.model, column.from) {
+                continue
+            }
+            err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).
+                Model(column.model).
+                UpdateColumn(column.to, gorm.Expr(column.from + " * 1000")).Error
+            if err != nil {
+                return err
+            }
+            if err := tx.Migrator().DropColumn(column.model, column.from); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
@@ -0,0 +1,18 @@
+package models
+
+import "github.com/jinzhu/gorm"
+
+// AssetGroup is a building, production line or zone of a plant. Groups form
+// a tree under their plant through ParentID. When MaxPower is set, the
+// assets attached to the group or to any of its descendants cannot draw
+// more than that.
+type AssetGroup struct {
+    gorm.Model
+    PlantID  uint
+    ParentID *uint
+    Name     string
+    Kind     string
+    MaxPower Power        `gorm:"column:max_power_watts"`
+    Children []AssetGroup `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE;" json:"-"`
+    Assets   []Asset      `gorm:"foreignKey:GroupID;constraint:OnDelete:SET NULL;" json:"-"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "github.com/jinzhu/gorm"
+
+// IdempotencyKey is a request made with an Idempotency-Key header, along with
+// the response it got. RequestHash tells apart the requests reusing a key.
+// Status is 0 while the request is being handled.
+type IdempotencyKey struct {
+    gorm.Model
+    Key         string `gorm:"uniqueIndex"`
+    RequestHash string
+    Status      int
+    ContentType string
+    Body        []byte
+}
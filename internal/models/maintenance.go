@@ -0,0 +1,19 @@
+package models
+
+import (
+    "time"
+
+    "github.com/jinzhu/gorm"
+)
+
+// MaintenanceWindow takes an asset out of service from StartsAt to EndsAt.
+// When RRule is set the window repeats following this RFC 5545 recurrence
+// rule, starting at StartsAt, every occurrence lasting EndsAt - StartsAt.
+type MaintenanceWindow struct {
+    gorm.Model
+    AssetID  uint
+    StartsAt time.Time
+    EndsAt   time.Time
+    RRule    string
+    Reason   string
+}
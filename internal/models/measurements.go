@@ -0,0 +1,17 @@
+package models
+
+import (
+    "time"
+
+    "github.com/jinzhu/gorm"
+)
+
+// Measurement is a load reading in kW. Readings with no AssetID come from
+// the plant's main meter, the others from the asset itself.
+type Measurement struct {
+    gorm.Model
+    PlantID   uint `gorm:"index"`
+    AssetID   *uint
+    Timestamp time.Time `gorm:"index"`
+    Power     float64
+}
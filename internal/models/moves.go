@@ -0,0 +1,15 @@
+package models
+
+import "github.com/jinzhu/gorm"
+
+// AssetMove records the relocation of an asset from a plant, and possibly a
+// group, to another. The move happened at CreatedAt.
+type AssetMove struct {
+    gorm.Model
+    AssetID     uint  `gorm:"index"`
+    FromPlantID uint
+    ToPlantID   uint
+    FromGroupID *uint
+    ToGroupID   *uint
+    Reason      string
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// OutboxMessage is an event of the log to publish to a sink. It is written
+// in the same transaction as the change the event comes from. Key is the
+// aggregate the event is about, like "asset:3": a message is only published
+// once the previous messages of its key to the same sink are.
+type OutboxMessage struct {
+    gorm.Model
+    EventID       uint      `gorm:"index"`
+    Event         Event     `gorm:"constraint:OnDelete:CASCADE"`
+    Sink          string    `gorm:"index:idx_outbox_messages_key"`
+    Key           string    `gorm:"index:idx_outbox_messages_key"`
+    Status        string    `gorm:"index"`
+    Attempts      uint
+    NextAttemptAt time.Time `gorm:"index"`
+    LastError     string
+    PublishedAt   *time.Time
+}
@@ -2,12 +2,34 @@ package models
 
 import "github.com/jinzhu/gorm"
 
+// Plant is a site whose assets are managed together. Latitude and Longitude
+// are nil when the plant has not been located. GridZone is the congestion or
+// bidding zone of the grid the plant is connected to and CountryCode an ISO
+// 3166-1 alpha-2 code. Timezone is the IANA name of the local time of the
+// plant, days, weeks and months are those of that timezone. EnergyManagerID
+// is the id of its current primary energy manager, nil when it has none; it
+// is read from the assignments of the plant, never written. EnergyManager is
+// that energy manager, only read when it is included.
 type Plant struct {
     gorm.Model
     Name            string
     Address         string
-    MaxPower        uint
-    EnergyManagerID uint
-    Assets          []Asset `gorm:"constraint:OnDelete:CASCADE;"`
+    MaxPower        Power              `gorm:"column:max_power_watts"`
+    Latitude        *float64
+    Longitude       *float64
+    GridZone        string             `gorm:"index"`
+    CountryCode     string             `gorm:"index"`
+    Timezone        string             `gorm:"default:UTC"`
+    TariffID        *uint
+    EnergyManagerID *uint              `gorm:"->;-:migration"`
+    Assets          []Asset            `gorm:"constraint:OnDelete:CASCADE;"`
+    Curtailments    []CurtailmentEvent `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    Measurements    []Measurement      `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    DispatchPlans   []DispatchPlan     `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    Agents          []Agent            `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    Groups          []AssetGroup       `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    Assignments     []PlantAssignment  `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    Connections     []GridConnection   `gorm:"constraint:OnDelete:CASCADE;" json:"-"`
+    EnergyManager   *EnergyManager     `gorm:"-" json:",omitempty"`
 }
 
@@ -0,0 +1,245 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+type PowerUnit string
+
+const (
+    W  PowerUnit = "W"
+    KW PowerUnit = "kW"
+    MW PowerUnit = "MW"
+    GW PowerUnit = "GW"
+)
+
+var (
+    ErrPowerUnit = errors.New("Power unit must be one of 'W', 'kW', 'MW' or 'GW'")
+    ErrPowerValue = errors.New("Power must be a non negative number of whole watts")
+)
+
+var unitWatts = map[PowerUnit]int64{W: 1, KW: 1000, MW: 1000000, GW: 1000000000}
+
+// ParsePowerUnit reads a power unit, whatever its case.
+func ParsePowerUnit(s string) (PowerUnit, error) {
+    for unit := range unitWatts {
+        if strings.EqualFold(s, string(unit)) {
+            return unit, nil
+        }
+    }
+    return "", ErrPowerUnit
+}
+
+// Power is an electric power. It is kept as a whole number of watts, so that
+// a 2.5 MW asset is exact and sums do not drift, along with the unit it is
+// rendered in, kW by default. It is stored as a bigint of watts.
+type Power struct {
+    watts int64
+    unit  PowerUnit
+}
+
+func Watts(watts int64) Power {
+    return Power{watts: watts}
+}
+
+func Kilowatts(kw uint) Power {
+    return Power{watts: int64(kw) * unitWatts[KW]}
+}
+
+// NewPower converts a decimal value given in unit. It fails when the value
+// is negative or finer than a watt.
+func NewPower(value string, unit PowerUnit) (Power, error) {
+    factor, ok := unitWatts[unit]
+    if !ok {
+        return Power{}, ErrPowerUnit
+    }
+    r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+    if !ok || r.Sign() < 0 {
+        return Power{}, ErrPowerValue
+    }
+    r.Mul(r, new(big.Rat).SetInt64(factor))
+    if !r.IsInt() || !r.Num().IsInt64() {
+        return Power{}, ErrPowerValue
+    }
+    return Power{watts: r.Num().Int64()}, nil
+}
+
+// ParsePower reads a power such as '2.5 MW' or '2500kW'. A bare number is in
+// kW.
+func ParsePower(s string) (Power, error) {
+    s = strings.TrimSpace(s)
+    i := strings.IndexFunc(s, func(r rune) bool {
+        return (r < '0' || r > '9') && r != '.' && r != '-' && r != '+'
+    })
+    if i < 0 {
+        return NewPower(s, KW)
+    }
+    unit, err := ParsePowerUnit(strings.TrimSpace(s[i:]))
+    if err != nil {
+        return Power{}, err
+    }
+    return NewPower(s[:i], unit)
+}
+
+func (p Power) Watts() int64 {
+    return p.watts
+}
+
+func (p Power) Kilowatts() float64 {
+    return float64(p.watts) / float64(unitWatts[KW])
+}
+
+func (p Power) Add(q Power) Power {
+    return Power{watts: p.watts + q.watts, unit: p.unit}
+}
+
+func (p Power) Sub(q Power) Power {
+    return Power{watts: p.watts - q.watts, unit: p.unit}
+}
+
+func (p Power) IsZero() bool {
+    return p.watts == 0
+}
+
+// In returns the same power, rendered in unit.
+func (p Power) In(unit PowerUnit) Power {
+    return Power{watts: p.watts, unit: unit}
+}
+
+// Unit is the unit the power is rendered in.
+func (p Power) Unit() PowerUnit {
+    if p.unit == "" {
+        return KW
+    }
+    return p.unit
+}
+
+// String renders the power in its unit without losing precision, '2.5 MW'
+// for instance.
+func (p Power) String() string {
+    return p.value() + " " + string(p.Unit())
+}
+
+func (p Power) value() string {
+    factor := unitWatts[p.Unit()]
+    s := new(big.Rat).SetFrac64(p.watts, factor).FloatString(len(fmt.Sprint(factor)) - 1)
+    if strings.Contains(s, ".") {
+        s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+    }
+    return s
+}
+
+func (p Power) Value() (driver.Value, error) {
+    return p.watts, nil
+}
+
+func (p *Power) Scan(src interface{}) error {
+    switch v := src.(type) {
+    case int64:
+        p.watts = v
+    case nil:
+        p.watts = 0
+    default:
+        return fmt.Errorf("cannot scan %T into Power", src)
+    }
+    return nil
+}
+
+// MarshalJSON renders the power as a number in its unit.
+func (p Power) MarshalJSON() ([]byte, error) {
+    return []byte(p.value()), nil
+}
+
+// UnmarshalJSON accepts a number of kW, a string such as "2.5 MW" or an
+// object such as {"value": 2.5, "unit": "MW"}.
+func (p *Power) UnmarshalJSON(b []byte) error {
+    b = bytes.TrimSpace(b)
+    if len(b) == 0 {
+        return ErrPowerValue
+    }
+    if bytes.Equal(b, []byte("null")) {
+        return nil
+    }
+    var res Power
+    var err error
+    switch b[0] {
+    case '"':
+        var s string
+        if err := json.Unmarshal(b, &s); err != nil {
+            return err
+        }
+        res, err = ParsePower(s)
+    case '{':
+        var v struct {
+            Value json.Number `json:"value"`
+            Unit  string      `json:"unit"`
+        }
+        if err := json.Unmarshal(b, &v); err != nil {
+            return err
+        }
+        unit := KW
+        if v.Unit != "" {
+            if unit, err = ParsePowerUnit(v.Unit); err != nil {
+                return err
+            }
+        }
+        res, err = NewPower(v.Value.String(), unit)
+    default:
+        res, err = NewPower(string(b), KW)
+    }
+    if err != nil {
+        return err
+    }
+    *p = res
+    return nil
+}
+
+var powerType = reflect.TypeOf(Power{})
+
+// RenderPowerIn sets the unit every Power reachable from v, through
+// pointers, slices, maps and struct fields, is rendered in. v must be a
+// pointer or a slice for the powers to be settable.
+func RenderPowerIn(v interface{}, unit PowerUnit) {
+    renderPowerIn(reflect.ValueOf(v), unit)
+}
+
+func renderPowerIn(v reflect.Value, unit PowerUnit) {
+    switch v.Kind() {
+    case reflect.Ptr, reflect.Interface:
+        if !v.IsNil() {
+            renderPowerIn(v.Elem(), unit)
+        }
+    case reflect.Slice, reflect.Array:
+        for i := 0; i < v.Len(); i++ {
+            renderPowerIn(v.Index(i), unit)
+        }
+    case reflect.Map:
+        // map values are not addressable, they are copied back
+        iter := v.MapRange()
+        for iter.Next() {
+            value := reflect.New(iter.Value().Type()).Elem()
+            value.Set(iter.Value())
+            renderPowerIn(value, unit)
+            v.SetMapIndex(iter.Key(), value)
+        }
+    case reflect.Struct:
+        if v.Type() == powerType {
+            if v.CanSet() {
+                v.Set(reflect.ValueOf(v.Interface().(Power).In(unit)))
+            }
+            return
+        }
+        for i := 0; i < v.NumField(); i++ {
+            if v.Type().Field(i).IsExported() {
+                renderPowerIn(v.Field(i), unit)
+            }
+        }
+    }
+}
@@ -0,0 +1,33 @@
+package models
+
+import "github.com/jinzhu/gorm"
+
+// Tariff prices the electricity of the plants it is attached to. Its periods
+// cover every time of the year exactly once.
+type Tariff struct {
+    gorm.Model
+    Name     string
+    Currency string         `gorm:"default:EUR"`
+    Periods  []TariffPeriod `gorm:"constraint:OnDelete:CASCADE;"`
+    Plants   []Plant        `gorm:"constraint:OnDelete:SET NULL;" json:"-"`
+}
+
+// TariffPeriod is a time-of-use period of a tariff. It applies, in the local
+// time of the plant, on Days (comma separated 'mon' to 'sun', every day when
+// empty) from StartTime to EndTime ('HH:MM', wrapping over midnight when
+// EndTime is before StartTime) during the months StartMonth to EndMonth
+// (1 to 12, wrapping over new year, all year when both are 0).
+// EnergyPrice is charged per kWh and DemandPrice per kW of the monthly peak
+// demand during the period.
+type TariffPeriod struct {
+    gorm.Model
+    TariffID    uint
+    Name        string
+    StartMonth  uint
+    EndMonth    uint
+    Days        string
+    StartTime   string
+    EndTime     string
+    EnergyPrice float64
+    DemandPrice float64
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// WebhookSubscription sends the events whose type is in Events (comma
+// separated, every event when empty) to URL, signed with Secret.
+type WebhookSubscription struct {
+    gorm.Model
+    URL        string
+    Secret     string            `json:"-"`
+    Events     string
+    Deliveries []WebhookDelivery `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// WebhookDelivery is an event on its way to a subscription. It stays pending
+// while attempts fail, until it is delivered or given up on: dead ones are
+// kept until redelivered.
+type WebhookDelivery struct {
+    gorm.Model
+    SubscriptionID uint            `gorm:"index"`
+    EventID        uint            `gorm:"index"`
+    Event          string
+    Payload        json.RawMessage `gorm:"type:jsonb"`
+    Status         string          `gorm:"index"`
+    Attempts       uint
+    NextAttemptAt  time.Time       `gorm:"index"`
+    LastError      string
+    DeliveredAt    *time.Time
+}
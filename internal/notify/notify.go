@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+    ErrUnknownChannel = errors.New("No sender registered for this channel")
+)
+
+// Message is a notification to deliver to a single recipient. To is an
+// email address for the email channel and an URL for the webhook one.
+type Message struct {
+    To      string
+    Subject string
+    Body    string
+}
+
+// Sender delivers messages over one channel.
+type Sender interface {
+    Send(ctx context.Context, msg Message) error
+}
+
+// Notifier routes messages to the sender of their channel.
+type Notifier struct {
+    mu      sync.RWMutex
+    senders map[string]Sender
+}
+
+func NewNotifier() *Notifier {
+    return &Notifier{senders: map[string]Sender{}}
+}
+
+// Register makes sender deliver the messages of channel, replacing the
+// sender previously registered for it if any.
+func (n *Notifier) Register(channel string, sender Sender) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    n.senders[channel] = sender
+}
+
+func (n *Notifier) Send(ctx context.Context, channel string, msg Message) error {
+    n.mu.RLock()
+    sender, ok := n.senders[channel]
+    n.mu.RUnlock()
+    if !ok {
+        return fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+    }
+    return sender.Send(ctx, msg)
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MainTestSuite struct {
+    suite.Suite
+}
+
+func TestNotify(t *testing.T) {
+    suite.Run(t, new(MainTestSuite))
+}
+
+// fakeSMTPServer accepts a single SMTP session and hands over the data
+// of the mail it received.
+func fakeSMTPServer(t *MainTestSuite) (string, chan string) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    t.Require().NoError(err)
+    mails := make(chan string, 1)
+
+    go func() {
+        defer listener.Close()
+        conn, err := listener.Accept()
+        if err != nil {
+            return
+        }
+        defer conn.Close()
+        reader := bufio.NewReader(conn)
+        write := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+        write("220 localhost ESMTP")
+        for {
+            line, err := reader.ReadString('\n')
+            if err != nil {
+                return
+            }
+            command := strings.ToUpper(strings.TrimSpace(line))
+            switch {
+            case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+                write("250 localhost")
+            case strings.HasPrefix(command, "DATA"):
+                write("354 go ahead")
+                var data strings.Builder
+                for {
+                    line, err := reader.ReadString('\n')
+                    if err != nil {
+                        return
+                    }
+                    if line == ".\r\n" {
+                        break
+                    }
+                    data.WriteString(line)
+                }
+                mails <- data.String()
+                write("250 queued")
+            case strings.HasPrefix(command, "QUIT"):
+                write("221 bye")
+                return
+            default:
+                write("250 ok")
+            }
+        }
+    }()
+    return listener.Addr().String(), mails
+}
+
+func (t *MainTestSuite) TestSMTPSender() {
+    addr, mails := fakeSMTPServer(t)
+    sender := NewSMTPSender(addr, "api-plant@example.com", nil)
+    sender.now = func() time.Time { return time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC) }
+
+    err := sender.Send(context.Background(), Message{
+        To: "gerard@example.com",
+        Subject: "Curtailment\nplanned",
+        Body: "first line\nsecond line",
+    })
+    t.Require().NoError(err)
+
+    mail := <-mails
+    t.Contains(mail, "From: api-plant@example.com\r\n")
+    t.Contains(mail, "To: gerard@example.com\r\n")
+    t.Contains(mail, "Subject: Curtailment planned\r\n")
+    t.Contains(mail, "Date: Mon, 02 May 2022 08:00:00 +0000\r\n")
+    t.Contains(mail, "\r\n\r\nfirst line\r\nsecond line\r\n")
+}
+
+func (t *MainTestSuite) TestWebhookSender() {
+    received := make(chan webhookPayload, 1)
+    status := http.StatusOK
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var payload webhookPayload
+        json.NewDecoder(r.Body).Decode(&payload)
+        received <- payload
+        w.WriteHeader(status)
+    }))
+    defer server.Close()
+
+    sender := NewWebhookSender()
+    err := sender.Send(context.Background(), Message{To: server.URL, Subject: "hello", Body: "world"})
+    t.Require().NoError(err)
+    t.Equal(webhookPayload{Subject: "hello", Body: "world"}, <-received)
+
+    // an error status is reported to the caller
+    status = http.StatusInternalServerError
+    err = sender.Send(context.Background(), Message{To: server.URL, Subject: "hello"})
+    t.Require().Error(err)
+    <-received
+}
+
+type recordingSender struct {
+    messages []Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg Message) error {
+    s.messages = append(s.messages, msg)
+    return nil
+}
+
+func (t *MainTestSuite) TestNotifier() {
+    notifier := NewNotifier()
+    email := &recordingSender{}
+    notifier.Register("email", email)
+
+    err := notifier.Send(context.Background(), "email", Message{To: "gerard@example.com"})
+    t.Require().NoError(err)
+    t.Len(email.messages, 1)
+
+    err = notifier.Send(context.Background(), "pigeon", Message{To: "gerard"})
+    t.True(errors.Is(err, ErrUnknownChannel))
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPSender sends messages as plain text emails through an SMTP relay.
+type SMTPSender struct {
+    // Addr is the host:port of the relay.
+    Addr string
+    From string
+    // Auth is optional, leave it nil for relays without authentication.
+    Auth smtp.Auth
+
+    now func() time.Time
+}
+
+func NewSMTPSender(addr string, from string, auth smtp.Auth) *SMTPSender {
+    return &SMTPSender{Addr: addr, From: from, Auth: auth, now: time.Now}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+    // header values cannot span several lines
+    subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Subject)
+
+    var b strings.Builder
+    fmt.Fprintf(&b, "From: %s\r\n", s.From)
+    fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+    fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+    fmt.Fprintf(&b, "Date: %s\r\n", s.now().Format(time.RFC1123Z))
+    b.WriteString("MIME-Version: 1.0\r\n")
+    b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+    b.WriteString("\r\n")
+    b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+    b.WriteString("\r\n")
+
+    return smtp.SendMail(s.Addr, s.Auth, s.From, []string{msg.To}, []byte(b.String()))
+}
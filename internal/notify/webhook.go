@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSender posts messages as JSON to the URL they are addressed to.
+type WebhookSender struct {
+    Client *http.Client
+}
+
+func NewWebhookSender() *WebhookSender {
+    return &WebhookSender{Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type webhookPayload struct {
+    Subject string `json:"subject"`
+    Body    string `json:"body"`
+}
+
+func (s *WebhookSender) Send(ctx context.Context, msg Message) error {
+    payload, err := json.Marshal(webhookPayload{Subject: msg.Subject, Body: msg.Body})
+    if err != nil {
+        return err
+    }
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.To, bytes.NewReader(payload))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+
+    res, err := s.Client.Do(req)
+    if err != nil {
+        return err
+    }
+    defer res.Body.Close()
+    if res.StatusCode < 200 || res.StatusCode >= 300 {
+        return fmt.Errorf("webhook %s answered %d", msg.To, res.StatusCode)
+    }
+    return nil
+}
@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaSink publishes messages to a Kafka topic through an HTTP proxy
+// speaking the REST Proxy v2 API, like the Confluent REST Proxy or the
+// Redpanda HTTP proxy. The key of the records is the key of the messages,
+// so that the messages of an aggregate go to the same partition, in order.
+type KafkaSink struct {
+    URL   string
+    Topic string
+    HTTP  *http.Client
+}
+
+func NewKafkaSink(proxy string, topic string) *KafkaSink {
+    return &KafkaSink{URL: proxy, Topic: topic, HTTP: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type kafkaRecord struct {
+    Key   string          `json:"key"`
+    Value json.RawMessage `json:"value"`
+}
+
+type kafkaRecords struct {
+    Records []kafkaRecord `json:"records"`
+}
+
+type kafkaOffsets struct {
+    Offsets []struct {
+        ErrorCode *int   `json:"error_code"`
+        Error     string `json:"error"`
+    } `json:"offsets"`
+}
+
+func (s *KafkaSink) Publish(ctx context.Context, msg Message) error {
+    body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: msg.Key, Value: msg.Payload}}})
+    if err != nil {
+        return err
+    }
+    target := strings.TrimRight(s.URL, "/") + "/topics/" + url.PathEscape(s.Topic)
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", kafkaContentType)
+    req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+    res, err := s.HTTP.Do(req)
+    if err != nil {
+        return err
+    }
+    defer res.Body.Close()
+    if res.StatusCode < 200 || res.StatusCode >= 300 {
+        return fmt.Errorf("kafka proxy %s answered %d", s.URL, res.StatusCode)
+    }
+    // the proxy answers 200 even when the broker did not take the record
+    var offsets kafkaOffsets
+    if err := json.NewDecoder(res.Body).Decode(&offsets); err != nil {
+        return err
+    }
+    for _, offset := range offsets.Offsets {
+        if offset.ErrorCode != nil {
+            return fmt.Errorf("kafka topic %s: %s", s.Topic, offset.Error)
+        }
+    }
+    return nil
+}
@@ -0,0 +1,132 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+    ErrNATSProtocol = errors.New("Unexpected answer from the NATS server")
+)
+
+// NATSSink publishes messages to a NATS server, on the subject
+// `<Subject>.<event type>` like `api-plant.asset.updated`, over the core
+// protocol. Each message is followed by a PING: the PONG answer tells the
+// server processed it. Its subscribers only get it when they are connected,
+// a JetStream stream on the subjects keeps the messages for the others.
+type NATSSink struct {
+    Addr    string
+    Subject string
+    Timeout time.Duration
+
+    mu     sync.Mutex
+    conn   net.Conn
+    reader *bufio.Reader
+}
+
+func NewNATSSink(addr string, subject string) *NATSSink {
+    return &NATSSink{Addr: addr, Subject: subject, Timeout: 10 * time.Second}
+}
+
+func (s *NATSSink) Publish(ctx context.Context, msg Message) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if err := s.publish(ctx, msg); err != nil {
+        // the connection is in an unknown state, a new one is made next time
+        s.close()
+        return err
+    }
+    return nil
+}
+
+func (s *NATSSink) publish(ctx context.Context, msg Message) error {
+    if s.conn == nil {
+        if err := s.connect(ctx); err != nil {
+            return err
+        }
+    }
+    deadline := time.Now().Add(s.Timeout)
+    if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+        deadline = d
+    }
+    s.conn.SetDeadline(deadline)
+
+    subject := s.Subject + "." + msg.Type
+    frame := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(msg.Payload), msg.Payload)
+    if _, err := s.conn.Write([]byte(frame)); err != nil {
+        return err
+    }
+    for {
+        line, err := s.readLine()
+        if err != nil {
+            return err
+        }
+        switch {
+        case line == "PONG":
+            return nil
+        case line == "PING":
+            if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+                return err
+            }
+        case line == "+OK" || strings.HasPrefix(line, "INFO "):
+        case strings.HasPrefix(line, "-ERR"):
+            return fmt.Errorf("nats %s: %s", s.Addr, line)
+        default:
+            return fmt.Errorf("%w: %s", ErrNATSProtocol, line)
+        }
+    }
+}
+
+// connect opens a connection and answers the INFO the server greets it with.
+func (s *NATSSink) connect(ctx context.Context) error {
+    var dialer net.Dialer
+    ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+    defer cancel()
+    conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+    if err != nil {
+        return err
+    }
+    s.conn = conn
+    s.reader = bufio.NewReader(conn)
+    s.conn.SetDeadline(time.Now().Add(s.Timeout))
+
+    line, err := s.readLine()
+    if err != nil {
+        return err
+    }
+    if !strings.HasPrefix(line, "INFO ") {
+        return fmt.Errorf("%w: %s", ErrNATSProtocol, line)
+    }
+    _, err = s.conn.Write([]byte(`CONNECT {"verbose":false,"pedantic":false,"name":"api-plant"}` + "\r\n"))
+    return err
+}
+
+func (s *NATSSink) readLine() (string, error) {
+    line, err := s.reader.ReadString('\n')
+    if err != nil {
+        return "", err
+    }
+    return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (s *NATSSink) close() {
+    if s.conn != nil {
+        s.conn.Close()
+        s.conn = nil
+        s.reader = nil
+    }
+}
+
+// Close closes the connection to the server, if any.
+func (s *NATSSink) Close() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.close()
+    return nil
+}
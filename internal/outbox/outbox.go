@@ -0,0 +1,24 @@
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Message is an event published by the relay of the outbox. Key names the
+// aggregate the event is about, like "asset:3": the messages of a key are
+// published in order, and the sinks that partition keep them together.
+type Message struct {
+    ID         uint
+    Type       string
+    Key        string
+    OccurredAt time.Time
+    Payload    []byte
+}
+
+// Sink publishes messages to a broker. Publish must only return nil once the
+// broker has the message, since a message is published again until then:
+// consumers get every message at least once and must cope with duplicates.
+type Sink interface {
+    Publish(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,135 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MainTestSuite struct {
+    suite.Suite
+}
+
+func TestOutbox(t *testing.T) {
+    suite.Run(t, new(MainTestSuite))
+}
+
+var message = Message{
+    ID: 7,
+    Type: "asset.updated",
+    Key: "asset:3",
+    OccurredAt: time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC),
+    Payload: []byte(`{"id":7,"type":"asset.updated"}`),
+}
+
+// fakeNATSServer accepts a single connection and hands over the subjects and
+// payloads published on it. It answers the n-th PING with an error.
+func fakeNATSServer(t *MainTestSuite, fail_at int) (string, chan string) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    t.Require().NoError(err)
+    published := make(chan string, 10)
+
+    go func() {
+        defer listener.Close()
+        conn, err := listener.Accept()
+        if err != nil {
+            return
+        }
+        defer conn.Close()
+        reader := bufio.NewReader(conn)
+        write := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+        write(`INFO {"server_id":"fake","max_payload":1048576}`)
+        pings := 0
+        for {
+            line, err := reader.ReadString('\n')
+            if err != nil {
+                return
+            }
+            fields := strings.Fields(line)
+            switch {
+            case len(fields) == 0:
+            case fields[0] == "PUB":
+                payload, _ := reader.ReadString('\n')
+                published <- fields[1] + " " + strings.TrimRight(payload, "\r\n")
+            case fields[0] == "PING":
+                pings++
+                if pings == fail_at {
+                    write("-ERR 'Permissions Violation'")
+                    return
+                }
+                write("PONG")
+            }
+        }
+    }()
+    return listener.Addr().String(), published
+}
+
+func (t *MainTestSuite) TestNATSSink() {
+    addr, published := fakeNATSServer(t, 0)
+    sink := NewNATSSink(addr, "api-plant")
+    defer sink.Close()
+
+    // the connection is kept for the next messages
+    for i := 0; i < 2; i++ {
+        err := sink.Publish(context.Background(), message)
+        t.Require().NoError(err)
+        t.Equal(`api-plant.asset.updated {"id":7,"type":"asset.updated"}`, <-published)
+    }
+}
+
+func (t *MainTestSuite) TestNATSSinkError() {
+    addr, _ := fakeNATSServer(t, 1)
+    sink := NewNATSSink(addr, "api-plant")
+    defer sink.Close()
+
+    err := sink.Publish(context.Background(), message)
+    t.Require().Error(err)
+    t.Contains(err.Error(), "Permissions Violation")
+
+    // the server is gone, the next message is not acknowledged either
+    err = sink.Publish(context.Background(), message)
+    t.Require().Error(err)
+}
+
+func (t *MainTestSuite) TestKafkaSink() {
+    var records kafkaRecords
+    answer := `{"offsets": [{"partition": 0, "offset": 12}]}`
+    proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/topics/api-plant.events" || r.Header.Get("Content-Type") != kafkaContentType {
+            w.WriteHeader(http.StatusNotFound)
+            return
+        }
+        body, _ := io.ReadAll(r.Body)
+        json.Unmarshal(body, &records)
+        w.Write([]byte(answer))
+    }))
+    defer proxy.Close()
+
+    sink := NewKafkaSink(proxy.URL, "api-plant.events")
+    err := sink.Publish(context.Background(), message)
+    t.Require().NoError(err)
+    t.Require().Len(records.Records, 1)
+    t.Equal("asset:3", records.Records[0].Key)
+    t.JSONEq(string(message.Payload), string(records.Records[0].Value))
+
+    // the broker did not take the record
+    answer = `{"offsets": [{"partition": null, "offset": null, "error_code": 50003, "error": "timeout"}]}`
+    err = sink.Publish(context.Background(), message)
+    t.Require().Error(err)
+    t.Contains(err.Error(), "timeout")
+
+    sink.Topic = "unknown"
+    err = sink.Publish(context.Background(), message)
+    t.Require().Error(err)
+    t.Contains(err.Error(), "404")
+}
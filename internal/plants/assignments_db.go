@@ -0,0 +1,92 @@
+package plants
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"gorm.io/gorm"
+)
+
+func (db *PlantsDB) GetAssignmentsByPlantId(id uint) ([]models.PlantAssignment, error) {
+    var assignments []models.PlantAssignment
+    result := db.gorm.Where("plant_id = ?", id).Order("valid_from, id").Find(&assignments)
+    if result.Error != nil {
+        return assignments, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return assignments, ErrEmptyResult
+    }
+    return assignments, nil
+}
+
+func (db *PlantsDB) CreateAssignment(assignment *models.PlantAssignment) error {
+    result := db.gorm.Create(assignment)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetAssignmentByPlantId(plant_id uint, assignment_id uint) (*models.PlantAssignment, error) {
+    var assignment models.PlantAssignment
+    result := db.gorm.Where("plant_id = ?", plant_id).Find(&assignment, assignment_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &assignment, nil
+}
+
+func (db *PlantsDB) DeleteAssignmentById(assignment_id uint) error {
+    result := db.gorm.Delete(&models.PlantAssignment{}, assignment_id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) UpdateAssignment(assignment *models.PlantAssignment) error {
+    // ValidTo can be set back to nil to reopen an assignment
+    result := db.gorm.Model(assignment).Select("*").Updates(assignment)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+// ReplacePrimaryAssignment ends at `at` the primary assignments of a plant
+// still running then, and makes em_id the primary energy manager from then on.
+func (db *PlantsDB) ReplacePrimaryAssignment(plant_id uint, em_id uint, at time.Time) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        err := tx.Model(&models.PlantAssignment{}).
+            Where("plant_id = ? AND role = ?", plant_id, "primary").
+            Where("valid_to IS NULL OR valid_to > ?", at).
+            Update("valid_to", at).Error
+        if err != nil {
+            return err
+        }
+        // primary assignments planned for later are superseded as well
+        err = tx.Where("plant_id = ? AND role = ? AND valid_from >= ?", plant_id, "primary", at).
+            Delete(&models.PlantAssignment{}).Error
+        if err != nil {
+            return err
+        }
+        return tx.Create(&models.PlantAssignment{
+            PlantID: plant_id,
+            EnergyManagerID: em_id,
+            Role: "primary",
+            ValidFrom: at,
+        }).Error
+    })
+}
@@ -0,0 +1,167 @@
+package plants
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    AssignmentPrimary = "primary"
+    AssignmentBackup  = "backup"
+    AssignmentOnCall  = "on_call"
+)
+
+var (
+    ErrAssignmentRole = errors.New("Assignment Role must be one of 'primary', 'backup' or 'on_call'")
+    ErrAssignmentWindow = errors.New("Assignment must end after it starts")
+    ErrAssignmentOverlap = errors.New("Assignment overlaps another assignment of the plant")
+)
+
+// assignmentNow is the current time at the precision postgres stores, so
+// that an assignment starting now is already valid when read back.
+func (s *Service) assignmentNow() time.Time {
+    return s.now().Truncate(time.Microsecond)
+}
+
+func isAssignmentRole(role string) bool {
+    return role == AssignmentPrimary || role == AssignmentBackup || role == AssignmentOnCall
+}
+
+// assignmentsOverlap tells whether two validity periods share an instant.
+// A nil end means the period never ends.
+func assignmentsOverlap(a models.PlantAssignment, b models.PlantAssignment) bool {
+    if a.ValidTo != nil && !a.ValidTo.After(b.ValidFrom) {
+        return false
+    }
+    if b.ValidTo != nil && !b.ValidTo.After(a.ValidFrom) {
+        return false
+    }
+    return true
+}
+
+// checkAssignment makes sure a plant has a single primary energy manager at
+// any time, and that an energy manager does not hold the same role twice.
+func (s *Service) checkAssignment(assignment models.PlantAssignment) error {
+    if !isAssignmentRole(assignment.Role) {
+        return ErrAssignmentRole
+    }
+    if assignment.ValidTo != nil && !assignment.ValidTo.After(assignment.ValidFrom) {
+        return ErrAssignmentWindow
+    }
+    if _, err := s.DB.GetEnergyManagerById(assignment.EnergyManagerID); err != nil {
+        return err
+    }
+
+    existing, err := s.DB.GetAssignmentsByPlantId(assignment.PlantID)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    for _, other := range existing {
+        if other.ID == assignment.ID || other.Role != assignment.Role {
+            continue
+        }
+        if other.Role != AssignmentPrimary && other.EnergyManagerID != assignment.EnergyManagerID {
+            continue
+        }
+        if assignmentsOverlap(other, assignment) {
+            return ErrAssignmentOverlap
+        }
+    }
+    return nil
+}
+
+func (s *Service) GetPlantAssignments(id uint) ([]models.PlantAssignment, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+    assignments, err := s.DB.GetAssignmentsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return assignments, nil
+}
+
+type CreateAssignmentInput struct {
+    EnergyManagerID uint       `json:"energy_manager_id" binding:"required"`
+    Role            string     `json:"role"              binding:"required"`
+    ValidFrom       *time.Time `json:"valid_from"`
+    ValidTo         *time.Time `json:"valid_to"`
+}
+
+// CreateAssignment assigns an energy manager to a plant. The assignment
+// starts right away unless valid_from says otherwise.
+func (s *Service) CreateAssignment(id uint, input CreateAssignmentInput) error {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return err
+    }
+    assignment := models.PlantAssignment{
+        PlantID: id,
+        EnergyManagerID: input.EnergyManagerID,
+        Role: input.Role,
+        ValidFrom: s.assignmentNow(),
+        ValidTo: input.ValidTo,
+    }
+    if input.ValidFrom != nil {
+        assignment.ValidFrom = *input.ValidFrom
+    }
+    if err := s.checkAssignment(assignment); err != nil {
+        return err
+    }
+    return s.DB.CreateAssignment(&assignment)
+}
+
+func (s *Service) GetPlantAssignment(plant_id uint, assignment_id uint) (*models.PlantAssignment, error) {
+    if _, err := s.DB.GetPlantById(plant_id); err != nil {
+        return nil, err
+    }
+    return s.DB.GetAssignmentByPlantId(plant_id, assignment_id)
+}
+
+func (s *Service) DeletePlantAssignment(plant_id uint, assignment_id uint) error {
+    if _, err := s.GetPlantAssignment(plant_id, assignment_id); err != nil {
+        return err
+    }
+    return s.DB.DeleteAssignmentById(assignment_id)
+}
+
+type UpdateAssignmentInput struct {
+    EnergyManagerID uint       `json:"energy_manager_id" binding:"required"`
+    Role            string     `json:"role"              binding:"required"`
+    ValidFrom       time.Time  `json:"valid_from"        binding:"required"`
+    ValidTo         *time.Time `json:"valid_to"`
+}
+
+func (s *Service) UpdatePlantAssignment(plant_id uint, assignment_id uint, input UpdateAssignmentInput) error {
+    assignment, err := s.GetPlantAssignment(plant_id, assignment_id)
+    if err != nil {
+        return err
+    }
+    assignment.EnergyManagerID = input.EnergyManagerID
+    assignment.Role = input.Role
+    assignment.ValidFrom = input.ValidFrom
+    assignment.ValidTo = input.ValidTo
+    if err := s.checkAssignment(*assignment); err != nil {
+        return err
+    }
+    return s.DB.UpdateAssignment(assignment)
+}
+
+// replacePrimaryEnergyManager makes em_id the primary energy manager of a
+// plant from now on, unless it already is, through db which can be a
+// transaction.
+func (s *Service) replacePrimaryEnergyManager(db DB, plant_id uint, em_id uint) error {
+    now := s.assignmentNow()
+    assignments, err := db.GetAssignmentsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    for _, assignment := range assignments {
+        current := !assignment.ValidFrom.After(now) && (assignment.ValidTo == nil || assignment.ValidTo.After(now))
+        if current && assignment.Role == AssignmentPrimary && assignment.EnergyManagerID == em_id {
+            return nil
+        }
+    }
+    return db.ReplacePrimaryAssignment(plant_id, em_id, now)
+}
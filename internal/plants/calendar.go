@@ -0,0 +1,108 @@
+package plants
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    IntervalHour  = "hour"
+    IntervalDay   = "day"
+    IntervalWeek  = "week"
+    IntervalMonth = "month"
+)
+
+var (
+    ErrPlantTimezone = errors.New("Plant Timezone must be an IANA timezone, like Europe/Paris")
+    ErrInterval = errors.New("Interval must be one of 'hour', 'day', 'week' or 'month'")
+)
+
+// plantTimezoneOrDefault validates a timezone input, empty meaning UTC.
+func plantTimezoneOrDefault(timezone string) (string, error) {
+    if timezone == "" {
+        return "UTC", nil
+    }
+    if _, err := time.LoadLocation(timezone); err != nil {
+        return "", ErrPlantTimezone
+    }
+    return timezone, nil
+}
+
+// plantLocation is the local time of a plant, UTC for plants whose timezone
+// cannot be loaded.
+func plantLocation(plant *models.Plant) *time.Location {
+    location, err := time.LoadLocation(plant.Timezone)
+    if err != nil {
+        return time.UTC
+    }
+    return location
+}
+
+// offsetStep divides every UTC offset: stepping by it from a local hour
+// boundary meets the next one, whatever the clock changes in between.
+const offsetStep = 15 * time.Minute
+
+func isInterval(interval string) bool {
+    return interval == IntervalHour || interval == IntervalDay || interval == IntervalWeek || interval == IntervalMonth
+}
+
+// bucketStart returns the start of the local hour, day, week or month t is
+// in. Weeks start on Monday.
+func bucketStart(t time.Time, location *time.Location, interval string) time.Time {
+    t = t.In(location)
+    year, month, day := t.Date()
+    switch interval {
+    case IntervalHour:
+        // going back in absolute time keeps the right one of the two
+        // local hours that share a wall clock when clocks go back
+        start := t.Truncate(offsetStep)
+        for start.Minute() != 0 {
+            start = start.Add(-offsetStep)
+        }
+        return start
+    case IntervalWeek:
+        monday := (int(t.Weekday()) + 6) % 7
+        return time.Date(year, month, day-monday, 0, 0, 0, 0, location)
+    case IntervalMonth:
+        return time.Date(year, month, 1, 0, 0, 0, 0, location)
+    default:
+        return time.Date(year, month, day, 0, 0, 0, 0, location)
+    }
+}
+
+// bucketEnd returns the start of the bucket following the one starting at
+// start. Local days last 23 or 25 hours when clocks change, local hours 30
+// or 90 minutes when they change by half an hour.
+func bucketEnd(start time.Time, interval string) time.Time {
+    year, month, day := start.Date()
+    location := start.Location()
+    switch interval {
+    case IntervalHour:
+        // a local hour lasts 90 minutes when clocks go back half an hour
+        end := start.Add(offsetStep)
+        for end.Minute() != 0 {
+            end = end.Add(offsetStep)
+        }
+        return end
+    case IntervalWeek:
+        return time.Date(year, month, day+7, 0, 0, 0, 0, location)
+    case IntervalMonth:
+        return time.Date(year, month+1, 1, 0, 0, 0, 0, location)
+    default:
+        return time.Date(year, month, day+1, 0, 0, 0, 0, location)
+    }
+}
+
+// localBuckets splits [from, to) along the local calendar. The first and
+// last buckets are whole, they may start before from and end after to.
+func localBuckets(from time.Time, to time.Time, location *time.Location, interval string) [][2]time.Time {
+    buckets := [][2]time.Time{}
+    for start := bucketStart(from, location, interval); start.Before(to); {
+        end := bucketEnd(start, interval)
+        buckets = append(buckets, [2]time.Time{start, end})
+        start = end
+    }
+    return buckets
+}
@@ -0,0 +1,174 @@
+package plants
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+func (db *PlantsDB) GetAllAgents() ([]models.Agent, error) {
+    var agents []models.Agent
+    if err := db.gorm.Find(&agents).Error; err != nil {
+        return nil, err
+    }
+    return agents, nil
+}
+
+func (db *PlantsDB) CreateAgent(agent *models.Agent) error {
+    result := db.gorm.Create(agent)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetAgentById(id uint) (*models.Agent, error) {
+    var agent models.Agent
+    result := db.gorm.Find(&agent, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &agent, nil
+}
+
+func (db *PlantsDB) DeleteAgentById(id uint) error {
+    result := db.gorm.Delete(&models.Agent{}, id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+// CreateCommand saves a command along with its first transition.
+func (db *PlantsDB) CreateCommand(command *models.Command) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        result := tx.Create(command)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+        return tx.Create(&models.CommandTransition{
+            CommandID: command.ID,
+            To: command.Status,
+        }).Error
+    })
+}
+
+// GetCommandsByDispatchPlanId returns the commands sent for the setpoints of
+// a dispatch plan that are in one of statuses.
+func (db *PlantsDB) GetCommandsByDispatchPlanId(plan_id uint, statuses []string) ([]models.Command, error) {
+    var commands []models.Command
+    result := db.gorm.
+        Joins("JOIN dispatch_setpoints ON dispatch_setpoints.id = commands.dispatch_setpoint_id").
+        Where("dispatch_setpoints.dispatch_plan_id = ?", plan_id).
+        Where("commands.status IN ?", statuses).
+        Order("commands.id").
+        Find(&commands)
+    if result.Error != nil {
+        return commands, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return commands, ErrEmptyResult
+    }
+    return commands, nil
+}
+
+func (db *PlantsDB) GetCommandsByAgentId(agent_id uint, status string) ([]models.Command, error) {
+    var commands []models.Command
+    result := db.gorm.Where("agent_id = ? AND status = ?", agent_id, status).Order("id").Find(&commands)
+    if result.Error != nil {
+        return commands, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return commands, ErrEmptyResult
+    }
+    return commands, nil
+}
+
+// GetTimedOutCommands returns the delivered commands whose acknowledgement
+// deadline is past at `at`, whatever their agent.
+func (db *PlantsDB) GetTimedOutCommands(at time.Time) ([]models.Command, error) {
+    var commands []models.Command
+    result := db.gorm.Where("status = ? AND ack_deadline <= ?", CommandDelivered, at).Order("id").Find(&commands)
+    if result.Error != nil {
+        return commands, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return commands, ErrEmptyResult
+    }
+    return commands, nil
+}
+
+func (db *PlantsDB) GetCommandByAgentId(agent_id uint, command_id uint) (*models.Command, error) {
+    var command models.Command
+    result := db.gorm.Where("agent_id = ?", agent_id).Find(&command, command_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &command, nil
+}
+
+func (db *PlantsDB) GetCommandsByAssetId(asset_id uint) ([]models.Command, error) {
+    var commands []models.Command
+    result := db.gorm.Preload("History").Where("asset_id = ?", asset_id).Order("id").Find(&commands)
+    if result.Error != nil {
+        return commands, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return commands, ErrEmptyResult
+    }
+    return commands, nil
+}
+
+func (db *PlantsDB) GetCommandByAssetId(asset_id uint, command_id uint) (*models.Command, error) {
+    var command models.Command
+    result := db.gorm.Preload("History").Where("asset_id = ?", asset_id).Find(&command, command_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &command, nil
+}
+
+// TransitionCommand saves command, whose Status has been changed from
+// `from`, and records the transition. It returns ErrEmptyResult if the
+// command was not in the `from` status anymore, which happens when another
+// request moved it first.
+func (db *PlantsDB) TransitionCommand(command *models.Command, from string, reason string) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        result := tx.Model(command).
+            Where("status = ?", from).
+            Select("status", "attempts", "delivered_at", "ack_deadline").
+            Updates(command)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+        return tx.Create(&models.CommandTransition{
+            CommandID: command.ID,
+            From: from,
+            To: command.Status,
+            Reason: reason,
+        }).Error
+    })
+}
@@ -0,0 +1,471 @@
+package plants
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    CommandQueued       = "queued"
+    CommandDelivered    = "delivered"
+    CommandAcknowledged = "acknowledged"
+    CommandRejected     = "rejected"
+    CommandExpired      = "expired"
+    CommandCancelled    = "cancelled"
+)
+
+const (
+    // CommandAckTimeout is how long an agent has to acknowledge a command
+    // before it is queued again.
+    CommandAckTimeout = 30 * time.Second
+    // CommandMaxAttempts is how many times a command is delivered before
+    // giving up on it.
+    CommandMaxAttempts = 3
+    // commandPollInterval bounds how long a long-polling agent can miss a
+    // command queued by another instance of the service.
+    commandPollInterval = time.Second
+    // commandSweepInterval is how often the commands no agent acknowledged
+    // in time are looked for, whether their agent polls or not.
+    commandSweepInterval = 10 * time.Second
+)
+
+var (
+    ErrAssetAgent = errors.New("Asset agent must belong to the same plant")
+    ErrAssetNoAgent = errors.New("Asset has no agent to deliver commands to")
+    ErrCommandSetpoint = errors.New("Command Setpoint is too big for the asset")
+    ErrCommandStatus = errors.New("Command is not waiting for an acknowledgement")
+)
+
+// signals wakes up the requests long-polling for something: the commands
+// of an agent, keyed by its id, or the events of the event log.
+type signals struct {
+    mu      sync.Mutex
+    waiting map[uint][]chan struct{}
+}
+
+func newSignals() *signals {
+    return &signals{waiting: map[uint][]chan struct{}{}}
+}
+
+func (c *signals) wait(key uint) chan struct{} {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    ch := make(chan struct{})
+    c.waiting[key] = append(c.waiting[key], ch)
+    return ch
+}
+
+// forget unregisters a channel that is not waited on anymore.
+func (c *signals) forget(key uint, ch chan struct{}) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    waiting := c.waiting[key]
+    for i := range waiting {
+        if waiting[i] == ch {
+            c.waiting[key] = append(waiting[:i], waiting[i+1:]...)
+            break
+        }
+    }
+    if len(c.waiting[key]) == 0 {
+        delete(c.waiting, key)
+    }
+}
+
+func (c *signals) notify(key uint) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    for _, ch := range c.waiting[key] {
+        close(ch)
+    }
+    delete(c.waiting, key)
+}
+
+func (s *Service) GetAllAgents() ([]models.Agent, error) {
+    return s.DB.GetAllAgents()
+}
+
+type CreateAgentInput struct {
+    Name    string `json:"name"     binding:"required"`
+    PlantID uint   `json:"plant_id" binding:"required"`
+}
+
+func (s *Service) CreateAgent(input CreateAgentInput) error {
+    if _, err := s.DB.GetPlantById(input.PlantID); err != nil {
+        return err
+    }
+    return s.DB.CreateAgent(&models.Agent{
+        Name: input.Name,
+        PlantID: input.PlantID,
+    })
+}
+
+func (s *Service) GetAgent(id uint) (*models.Agent, error) {
+    return s.DB.GetAgentById(id)
+}
+
+func (s *Service) DeleteAgent(id uint) error {
+    return s.DB.DeleteAgentById(id)
+}
+
+// checkAssetAgent makes sure agent_id, if any, relays commands for plant_id.
+func (s *Service) checkAssetAgent(plant_id uint, agent_id *uint) error {
+    if agent_id == nil {
+        return nil
+    }
+    agent, err := s.DB.GetAgentById(*agent_id)
+    if err != nil || agent.PlantID != plant_id {
+        return ErrAssetAgent
+    }
+    return nil
+}
+
+type CreateCommandInput struct {
+    Setpoint models.Power `json:"setpoint"`
+}
+
+// liveCommandStatuses are those of the commands still carrying out their
+// setpoint, or done with it.
+var liveCommandStatuses = []string{CommandQueued, CommandDelivered, CommandAcknowledged}
+
+// checkCommand makes sure setpoint can be sent to asset now.
+func (s *Service) checkCommand(asset *models.Asset, setpoint models.Power) error {
+    if asset.AgentID == nil {
+        return ErrAssetNoAgent
+    }
+    if setpoint.Watts() > asset.MaxPower.Watts() {
+        return ErrCommandSetpoint
+    }
+    available, err := s.isAssetAvailableNow(asset)
+    if err != nil {
+        return err
+    }
+    if !available {
+        return ErrAssetUnavailable
+    }
+    return nil
+}
+
+func (s *Service) queueCommand(asset *models.Asset, setpoint models.Power) error {
+    if err := s.checkCommand(asset, setpoint); err != nil {
+        return err
+    }
+    err := s.DB.CreateCommand(&models.Command{
+        AgentID: *asset.AgentID,
+        AssetID: asset.ID,
+        Setpoint: setpoint,
+        Status: CommandQueued,
+    })
+    if err != nil {
+        return err
+    }
+    s.commandSignals.notify(*asset.AgentID)
+    return nil
+}
+
+func (s *Service) CreateAssetCommand(plant_id uint, asset_id uint, input CreateCommandInput) error {
+    asset, err := s.GetPlantAsset(plant_id, asset_id)
+    if err != nil {
+        return err
+    }
+    return s.queueCommand(asset, input.Setpoint)
+}
+
+// QueueDispatchPlanCommands sends every setpoint of a dispatch plan to the
+// agent of its asset. Either every setpoint is queued or none is, and those
+// that already have a live command are left alone, so that the plan can be
+// queued again after a failure.
+func (s *Service) QueueDispatchPlanCommands(plant_id uint, plan_id uint) error {
+    plan, err := s.GetPlantDispatchPlan(plant_id, plan_id)
+    if err != nil {
+        return err
+    }
+    assets := make([]*models.Asset, len(plan.Setpoints))
+    for i, setpoint := range plan.Setpoints {
+        asset, err := s.DB.GetAssetByPlantId(plant_id, setpoint.AssetID)
+        if err != nil {
+            return err
+        }
+        if err := s.checkCommand(asset, setpoint.Setpoint); err != nil {
+            return err
+        }
+        assets[i] = asset
+    }
+
+    agents := map[uint]bool{}
+    err = s.DB.Transaction(func(tx DB) error {
+        // queuing the same plan twice at once waits for the first one
+        if err := tx.LockDispatchPlanById(plan.ID); err != nil {
+            return err
+        }
+        live, err := tx.GetCommandsByDispatchPlanId(plan.ID, liveCommandStatuses)
+        if err != nil && err != ErrEmptyResult {
+            return err
+        }
+        queued := map[uint]bool{}
+        for _, command := range live {
+            queued[*command.DispatchSetpointID] = true
+        }
+        for i, setpoint := range plan.Setpoints {
+            if queued[setpoint.ID] {
+                continue
+            }
+            setpoint_id := setpoint.ID
+            err := tx.CreateCommand(&models.Command{
+                AgentID: *assets[i].AgentID,
+                AssetID: assets[i].ID,
+                DispatchSetpointID: &setpoint_id,
+                Setpoint: setpoint.Setpoint,
+                Status: CommandQueued,
+            })
+            if err != nil {
+                return err
+            }
+            agents[*assets[i].AgentID] = true
+        }
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+    for agent_id := range agents {
+        s.commandSignals.notify(agent_id)
+    }
+    return nil
+}
+
+func (s *Service) GetAssetCommands(plant_id uint, asset_id uint) ([]models.Command, error) {
+    asset, err := s.GetPlantAsset(plant_id, asset_id)
+    if err != nil {
+        return nil, err
+    }
+    if asset.AgentID != nil {
+        if err := s.requeueTimedOutCommands(*asset.AgentID); err != nil {
+            return nil, err
+        }
+    }
+    commands, err := s.DB.GetCommandsByAssetId(asset_id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return commands, nil
+}
+
+func (s *Service) GetAssetCommand(plant_id uint, asset_id uint, command_id uint) (*models.Command, error) {
+    if _, err := s.GetPlantAsset(plant_id, asset_id); err != nil {
+        return nil, err
+    }
+    return s.DB.GetCommandByAssetId(asset_id, command_id)
+}
+
+// requeueTimedOutCommands puts back in the queue the commands delivered to
+// an agent that were not acknowledged in time, or expires them once they
+// have been delivered CommandMaxAttempts times.
+func (s *Service) requeueTimedOutCommands(agent_id uint) error {
+    delivered, err := s.DB.GetCommandsByAgentId(agent_id, CommandDelivered)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    return s.requeueCommands(delivered)
+}
+
+// SweepCommands requeues or expires the commands not acknowledged in time
+// until ctx is done, so that an agent gone for good does not keep its
+// commands delivered forever.
+func (s *Service) SweepCommands(ctx context.Context) {
+    for {
+        if err := s.sweepCommands(); err != nil {
+            log.Printf("commands: %v", err)
+        }
+        select {
+        case <-ctx.Done():
+            return
+        case <-time.After(commandSweepInterval):
+        }
+    }
+}
+
+func (s *Service) sweepCommands() error {
+    delivered, err := s.DB.GetTimedOutCommands(s.now())
+    if err == ErrEmptyResult {
+        return nil
+    } else if err != nil {
+        return err
+    }
+    if err := s.requeueCommands(delivered); err != nil {
+        return err
+    }
+    // the agents polling get their commands back right away
+    agents := map[uint]bool{}
+    for _, command := range delivered {
+        if command.Status == CommandQueued {
+            agents[command.AgentID] = true
+        }
+    }
+    for agent_id := range agents {
+        s.commandSignals.notify(agent_id)
+    }
+    return nil
+}
+
+// requeueCommands puts back in the queue the delivered commands whose
+// acknowledgement deadline is past, or expires them.
+func (s *Service) requeueCommands(delivered []models.Command) error {
+    now := s.now()
+    for i := range delivered {
+        command := &delivered[i]
+        if command.AckDeadline == nil || now.Before(*command.AckDeadline) {
+            continue
+        }
+        command.Status = CommandQueued
+        if command.Attempts >= CommandMaxAttempts {
+            command.Status = CommandExpired
+        }
+        err := s.transitionCommand(command, CommandDelivered, "acknowledgement timed out")
+        if err != nil && err != ErrEmptyResult {
+            return err
+        }
+    }
+    return nil
+}
+
+// deliverQueuedCommands hands the queued commands of an agent over to it.
+func (s *Service) deliverQueuedCommands(agent_id uint) ([]models.Command, error) {
+    if err := s.requeueTimedOutCommands(agent_id); err != nil {
+        return nil, err
+    }
+    queued, err := s.DB.GetCommandsByAgentId(agent_id, CommandQueued)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+
+    now := s.now()
+    deadline := now.Add(CommandAckTimeout)
+    delivered := []models.Command{}
+    for _, command := range queued {
+        // the asset may have gone into maintenance since the command was queued
+        asset, err := s.DB.GetAssetById(command.AssetID)
+        if err != nil {
+            return nil, err
+        }
+        available, err := s.isAssetAvailableNow(asset)
+        if err != nil {
+            return nil, err
+        }
+        if !available {
+            command.Status = CommandExpired
+            err = s.transitionCommand(&command, CommandQueued, "asset unavailable")
+            if err != nil && err != ErrEmptyResult {
+                return nil, err
+            }
+            continue
+        }
+
+        command.Status = CommandDelivered
+        command.Attempts++
+        command.DeliveredAt = &now
+        command.AckDeadline = &deadline
+        err = s.DB.TransitionCommand(&command, CommandQueued, "")
+        if err == ErrEmptyResult {
+            // another poll of the same agent got it first
+            continue
+        } else if err != nil {
+            return nil, err
+        }
+        delivered = append(delivered, command)
+    }
+    return delivered, nil
+}
+
+// FetchAgentCommands returns the commands waiting for an agent. When there
+// is none it waits up to `wait` for one to be queued.
+func (s *Service) FetchAgentCommands(ctx context.Context, agent_id uint, wait time.Duration) ([]models.Command, error) {
+    if _, err := s.DB.GetAgentById(agent_id); err != nil {
+        return nil, err
+    }
+
+    timeout := time.NewTimer(wait)
+    defer timeout.Stop()
+    for {
+        signal := s.commandSignals.wait(agent_id)
+        commands, err := s.deliverQueuedCommands(agent_id)
+        if err != nil || len(commands) > 0 {
+            s.commandSignals.forget(agent_id, signal)
+            return commands, err
+        }
+
+        done := false
+        select {
+        case <-signal:
+        case <-time.After(commandPollInterval):
+        case <-timeout.C:
+            done = true
+        case <-ctx.Done():
+            done = true
+        }
+        s.commandSignals.forget(agent_id, signal)
+        if done {
+            return commands, nil
+        }
+    }
+}
+
+// transitionCommand moves a command from the status `from` to its Status,
+// and opens an alarm when it is rejected or expires.
+func (s *Service) transitionCommand(command *models.Command, from string, reason string) error {
+    code := AlarmCommandRejected
+    if command.Status == CommandExpired {
+        code = AlarmCommandExpired
+    } else if command.Status != CommandRejected {
+        return s.DB.TransitionCommand(command, from, reason)
+    }
+    asset, err := s.DB.GetAssetById(command.AssetID)
+    if err != nil {
+        return err
+    }
+    return s.transaction(func(tx DB) error {
+        if err := tx.TransitionCommand(command, from, reason); err != nil {
+            return err
+        }
+        return s.openAlarm(tx, alarm{
+            Code: code,
+            Source: "command",
+            SourceID: command.ID,
+            PlantID: asset.PlantID,
+            AssetID: &asset.ID,
+            Reason: reason,
+        })
+    })
+}
+
+func (s *Service) resolveAgentCommand(agent_id uint, command_id uint, status string, reason string) error {
+    command, err := s.DB.GetCommandByAgentId(agent_id, command_id)
+    if err != nil {
+        return err
+    }
+    if command.Status != CommandDelivered {
+        return ErrCommandStatus
+    }
+    command.Status = status
+    err = s.transitionCommand(command, CommandDelivered, reason)
+    if err == ErrEmptyResult {
+        return ErrCommandStatus
+    }
+    return err
+}
+
+func (s *Service) AcknowledgeAgentCommand(agent_id uint, command_id uint) error {
+    return s.resolveAgentCommand(agent_id, command_id, CommandAcknowledged, "")
+}
+
+type RejectCommandInput struct {
+    Reason string `json:"reason"`
+}
+
+func (s *Service) RejectAgentCommand(agent_id uint, command_id uint, input RejectCommandInput) error {
+    return s.resolveAgentCommand(agent_id, command_id, CommandRejected, input.Reason)
+}
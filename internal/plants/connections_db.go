@@ -0,0 +1,63 @@
+package plants
+
+import (
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+func (db *PlantsDB) GetConnectionsByPlantId(id uint) ([]models.GridConnection, error) {
+    var connections []models.GridConnection
+    result := db.gorm.Where("plant_id = ?", id).Order("valid_from, id").Find(&connections)
+    if result.Error != nil {
+        return connections, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return connections, ErrEmptyResult
+    }
+    return connections, nil
+}
+
+func (db *PlantsDB) CreateConnection(connection *models.GridConnection) error {
+    result := db.gorm.Create(connection)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetConnectionByPlantId(plant_id uint, connection_id uint) (*models.GridConnection, error) {
+    var connection models.GridConnection
+    result := db.gorm.Where("plant_id = ?", plant_id).Find(&connection, connection_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &connection, nil
+}
+
+func (db *PlantsDB) DeleteConnectionById(connection_id uint) error {
+    result := db.gorm.Delete(&models.GridConnection{}, connection_id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) UpdateConnection(connection *models.GridConnection) error {
+    // ValidTo can be set back to nil to extend a contract indefinitely
+    result := db.gorm.Model(connection).Select("*").Updates(connection)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
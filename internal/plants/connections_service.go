@@ -0,0 +1,300 @@
+package plants
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    ComplianceWarning = "warning"
+    ComplianceError   = "error"
+)
+
+// compliance issue codes
+const (
+    IssueNoConnection            = "no_connection"
+    IssuePlantPowerAboveImport   = "plant_power_above_import"
+    IssueAssetPowerAboveImport   = "asset_power_above_import"
+    IssueImportPeakAboveCapacity = "import_peak_above_capacity"
+    IssueExportPeakAboveCapacity = "export_peak_above_capacity"
+)
+
+var (
+    ErrConnectionVoltage = errors.New("Connection VoltageLevel must be one of 'lv', 'mv', 'hv' or 'ehv'")
+    ErrConnectionWindow = errors.New("Connection must end after it starts")
+    ErrConnectionOverlap = errors.New("Connection overlaps another connection of the plant")
+)
+
+// ComplianceIssue is a limit of the grid connection of a plant that is, or
+// may be, exceeded. Warnings are about what the plant could draw, errors
+// about what it did draw; At is when the worst reading was taken then.
+type ComplianceIssue struct {
+    Level        string       `json:"level"`
+    Code         string       `json:"code"`
+    ConnectionID *uint        `json:"connection_id"`
+    At           *time.Time   `json:"at"`
+    Value        models.Power `json:"value"`
+    Limit        models.Power `json:"limit"`
+}
+
+// ConnectionCompliance checks a plant against its current grid connection,
+// and the readings of its main meter over [From, To) against the connection
+// valid when they were taken.
+type ConnectionCompliance struct {
+    PlantID    uint                   `json:"plant_id"`
+    From       time.Time              `json:"from"`
+    To         time.Time              `json:"to"`
+    Connection *models.GridConnection `json:"connection"`
+    AssetPower models.Power           `json:"asset_power"`
+    Compliant  bool                   `json:"compliant"`
+    Issues     []ComplianceIssue      `json:"issues"`
+}
+
+func isVoltageLevel(level string) bool {
+    return level == "lv" || level == "mv" || level == "hv" || level == "ehv"
+}
+
+// connectionValidAt tells whether a connection is valid at t.
+func connectionValidAt(connection models.GridConnection, t time.Time) bool {
+    return !connection.ValidFrom.After(t) && (connection.ValidTo == nil || connection.ValidTo.After(t))
+}
+
+// connectionsOverlap tells whether two validity periods share an instant.
+// A nil end means the period never ends.
+func connectionsOverlap(a models.GridConnection, b models.GridConnection) bool {
+    if a.ValidTo != nil && !a.ValidTo.After(b.ValidFrom) {
+        return false
+    }
+    if b.ValidTo != nil && !b.ValidTo.After(a.ValidFrom) {
+        return false
+    }
+    return true
+}
+
+// checkConnection makes sure a plant has a single grid connection at any
+// time.
+func (s *Service) checkConnection(connection models.GridConnection) error {
+    if !isVoltageLevel(connection.VoltageLevel) {
+        return ErrConnectionVoltage
+    }
+    if connection.ValidTo != nil && !connection.ValidTo.After(connection.ValidFrom) {
+        return ErrConnectionWindow
+    }
+    existing, err := s.DB.GetConnectionsByPlantId(connection.PlantID)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    for _, other := range existing {
+        if other.ID != connection.ID && connectionsOverlap(other, connection) {
+            return ErrConnectionOverlap
+        }
+    }
+    return nil
+}
+
+// GetPlantConnections returns the history of the grid connections of a
+// plant, oldest first.
+func (s *Service) GetPlantConnections(id uint) ([]models.GridConnection, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+    connections, err := s.DB.GetConnectionsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return connections, nil
+}
+
+type CreateConnectionInput struct {
+    ConnectionPointID string       `json:"connection_point_id" binding:"required"`
+    VoltageLevel      string       `json:"voltage_level"       binding:"required"`
+    ImportCapacity    models.Power `json:"import_capacity"     binding:"required"`
+    ExportCapacity    models.Power `json:"export_capacity"`
+    ValidFrom         *time.Time   `json:"valid_from"`
+    ValidTo           *time.Time   `json:"valid_to"`
+}
+
+// CreateConnection adds a grid connection to a plant. It is valid right
+// away unless valid_from says otherwise.
+func (s *Service) CreateConnection(id uint, input CreateConnectionInput) error {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return err
+    }
+    connection := models.GridConnection{
+        PlantID: id,
+        ConnectionPointID: input.ConnectionPointID,
+        VoltageLevel: input.VoltageLevel,
+        ImportCapacity: input.ImportCapacity,
+        ExportCapacity: input.ExportCapacity,
+        ValidFrom: s.assignmentNow(),
+        ValidTo: input.ValidTo,
+    }
+    if input.ValidFrom != nil {
+        connection.ValidFrom = *input.ValidFrom
+    }
+    if err := s.checkConnection(connection); err != nil {
+        return err
+    }
+    return s.DB.CreateConnection(&connection)
+}
+
+func (s *Service) GetPlantConnection(plant_id uint, connection_id uint) (*models.GridConnection, error) {
+    if _, err := s.DB.GetPlantById(plant_id); err != nil {
+        return nil, err
+    }
+    return s.DB.GetConnectionByPlantId(plant_id, connection_id)
+}
+
+func (s *Service) DeletePlantConnection(plant_id uint, connection_id uint) error {
+    if _, err := s.GetPlantConnection(plant_id, connection_id); err != nil {
+        return err
+    }
+    return s.DB.DeleteConnectionById(connection_id)
+}
+
+type UpdateConnectionInput struct {
+    ConnectionPointID string       `json:"connection_point_id" binding:"required"`
+    VoltageLevel      string       `json:"voltage_level"       binding:"required"`
+    ImportCapacity    models.Power `json:"import_capacity"     binding:"required"`
+    ExportCapacity    models.Power `json:"export_capacity"`
+    ValidFrom         time.Time    `json:"valid_from"          binding:"required"`
+    ValidTo           *time.Time   `json:"valid_to"`
+}
+
+func (s *Service) UpdatePlantConnection(plant_id uint, connection_id uint, input UpdateConnectionInput) error {
+    connection, err := s.GetPlantConnection(plant_id, connection_id)
+    if err != nil {
+        return err
+    }
+    connection.ConnectionPointID = input.ConnectionPointID
+    connection.VoltageLevel = input.VoltageLevel
+    connection.ImportCapacity = input.ImportCapacity
+    connection.ExportCapacity = input.ExportCapacity
+    connection.ValidFrom = input.ValidFrom
+    connection.ValidTo = input.ValidTo
+    if err := s.checkConnection(*connection); err != nil {
+        return err
+    }
+    return s.DB.UpdateConnection(connection)
+}
+
+// measuredPower converts a reading in kW to a Power, rounded to the watt.
+func measuredPower(kw float64) models.Power {
+    return models.Watts(int64(math.Round(kw * 1000)))
+}
+
+// connectionPeaks returns, for each connection, the issues raised by the
+// highest import and export readings taken while it was valid.
+func connectionPeaks(connections []models.GridConnection, measurements []models.Measurement) []ComplianceIssue {
+    issues := []ComplianceIssue{}
+    for i := range connections {
+        connection := connections[i]
+        var import_peak, export_peak *models.Measurement
+        for j := range measurements {
+            m := &measurements[j]
+            if !connectionValidAt(connection, m.Timestamp) {
+                continue
+            }
+            if m.Power > 0 && (import_peak == nil || m.Power > import_peak.Power) {
+                import_peak = m
+            }
+            if m.Power < 0 && (export_peak == nil || m.Power < export_peak.Power) {
+                export_peak = m
+            }
+        }
+        if import_peak != nil && measuredPower(import_peak.Power).Watts() > connection.ImportCapacity.Watts() {
+            issues = append(issues, ComplianceIssue{
+                Level: ComplianceError,
+                Code: IssueImportPeakAboveCapacity,
+                ConnectionID: &connection.ID,
+                At: &import_peak.Timestamp,
+                Value: measuredPower(import_peak.Power),
+                Limit: connection.ImportCapacity,
+            })
+        }
+        if export_peak != nil && measuredPower(-export_peak.Power).Watts() > connection.ExportCapacity.Watts() {
+            issues = append(issues, ComplianceIssue{
+                Level: ComplianceError,
+                Code: IssueExportPeakAboveCapacity,
+                ConnectionID: &connection.ID,
+                At: &export_peak.Timestamp,
+                Value: measuredPower(-export_peak.Power),
+                Limit: connection.ExportCapacity,
+            })
+        }
+    }
+    return issues
+}
+
+// GetPlantCompliance checks that a plant stays within its grid connection.
+// Exceeding the import capacity with the installed power of the plant or of
+// its assets is a warning, measuring more than the import or export
+// capacity over [from, to) is an error.
+func (s *Service) GetPlantCompliance(id uint, from time.Time, to time.Time) (*ConnectionCompliance, error) {
+    plant, err := s.DB.GetPlantById(id)
+    if err != nil {
+        return nil, err
+    }
+    connections, err := s.DB.GetConnectionsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    assets, err := s.DB.GetAssetsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+
+    res := ConnectionCompliance{
+        PlantID: id,
+        From: from,
+        To: to,
+        AssetPower: sumAssetPower(assets),
+        Issues: []ComplianceIssue{},
+    }
+    now := s.now()
+    for i := range connections {
+        if connectionValidAt(connections[i], now) {
+            res.Connection = &connections[i]
+        }
+    }
+    if res.Connection == nil {
+        res.Issues = append(res.Issues, ComplianceIssue{Level: ComplianceWarning, Code: IssueNoConnection})
+    } else {
+        limit := res.Connection.ImportCapacity
+        if plant.MaxPower.Watts() > limit.Watts() {
+            res.Issues = append(res.Issues, ComplianceIssue{
+                Level: ComplianceWarning,
+                Code: IssuePlantPowerAboveImport,
+                ConnectionID: &res.Connection.ID,
+                Value: plant.MaxPower,
+                Limit: limit,
+            })
+        }
+        if res.AssetPower.Watts() > limit.Watts() {
+            res.Issues = append(res.Issues, ComplianceIssue{
+                Level: ComplianceWarning,
+                Code: IssueAssetPowerAboveImport,
+                ConnectionID: &res.Connection.ID,
+                Value: res.AssetPower,
+                Limit: limit,
+            })
+        }
+    }
+
+    measurements, err := s.DB.GetMeasurementsByPlantId(id, from, to)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    res.Issues = append(res.Issues, connectionPeaks(connections, plantMeterMeasurements(measurements))...)
+
+    res.Compliant = true
+    for _, issue := range res.Issues {
+        if issue.Level == ComplianceError {
+            res.Compliant = false
+        }
+    }
+    return &res, nil
+}
@@ -0,0 +1,155 @@
+package plants
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// measurementMaxHold is how long a reading is assumed to last when the next
+// one is late: the load is unknown past it and costs nothing.
+const measurementMaxHold = time.Hour
+
+// slotEnergies integrates readings, sorted by time, over [from, to) by
+// holding each reading until the next one. It returns the energy in kWh of
+// each tariff slot, keyed by the start of the slot.
+func slotEnergies(measurements []models.Measurement, from time.Time, to time.Time) map[time.Time]float64 {
+    energies := map[time.Time]float64{}
+    for i, m := range measurements {
+        start := m.Timestamp
+        end := start.Add(measurementMaxHold)
+        if i+1 < len(measurements) && measurements[i+1].Timestamp.Before(end) {
+            end = measurements[i+1].Timestamp
+        }
+        if start.Before(from) {
+            start = from
+        }
+        if end.After(to) {
+            end = to
+        }
+        // split the reading along the slots it spans
+        for start.Before(end) {
+            slot := start.Truncate(tariffSlot)
+            piece := slot.Add(tariffSlot)
+            if piece.After(end) {
+                piece = end
+            }
+            energies[slot.UTC()] += m.Power * piece.Sub(start).Hours()
+            start = piece
+        }
+    }
+    return energies
+}
+
+// CostBreakdown is what some load cost. Energy is in kWh, costs in the
+// currency of the tariff.
+type CostBreakdown struct {
+    Energy     float64 `json:"energy"`
+    EnergyCost float64 `json:"energy_cost"`
+    DemandCost float64 `json:"demand_cost"`
+    TotalCost  float64 `json:"total_cost"`
+}
+
+func (c *CostBreakdown) total() {
+    c.TotalCost = c.EnergyCost + c.DemandCost
+}
+
+type PeriodCost struct {
+    PeriodID uint   `json:"period_id"`
+    Name     string `json:"name"`
+    CostBreakdown
+}
+
+type AssetCost struct {
+    AssetID uint `json:"asset_id"`
+    CostBreakdown
+}
+
+// PlantCosts is what a plant cost over [From, To). The plant totals come from
+// its main meter, each asset is charged its own energy and its share of the
+// plant's peak demand.
+type PlantCosts struct {
+    PlantID  uint         `json:"plant_id"`
+    TariffID uint         `json:"tariff_id"`
+    Currency string       `json:"currency"`
+    From     time.Time    `json:"from"`
+    To       time.Time    `json:"to"`
+    CostBreakdown
+    Periods  []PeriodCost `json:"periods"`
+    Assets   []AssetCost  `json:"assets"`
+}
+
+type demandKey struct {
+    month  time.Time
+    period int
+}
+
+// computeCosts prices the energy of the plant meter and of each asset slot
+// by slot. Demand charges apply per local month and period to the highest
+// quarter hour of the meter; they are prorated when [from, to) only covers
+// part of the month.
+func computeCosts(tariff compiledTariff, location *time.Location, meter map[time.Time]float64, assets map[uint]map[time.Time]float64, from time.Time, to time.Time) (CostBreakdown, []CostBreakdown, map[uint]*CostBreakdown) {
+    plant := CostBreakdown{}
+    periods := make([]CostBreakdown, len(tariff))
+    byAsset := map[uint]*CostBreakdown{}
+    for id := range assets {
+        byAsset[id] = &CostBreakdown{}
+    }
+
+    peaks := map[demandKey]time.Time{}
+    for slot := from.Truncate(tariffSlot).UTC(); slot.Before(to); slot = slot.Add(tariffSlot) {
+        period := tariff.at(slot, location)
+        if period < 0 {
+            continue
+        }
+        price := tariff[period].period.EnergyPrice
+
+        energy := meter[slot]
+        plant.Energy += energy
+        plant.EnergyCost += energy * price
+        periods[period].Energy += energy
+        periods[period].EnergyCost += energy * price
+        for id, energies := range assets {
+            byAsset[id].Energy += energies[slot]
+            byAsset[id].EnergyCost += energies[slot] * price
+        }
+
+        if tariff[period].period.DemandPrice == 0 {
+            continue
+        }
+        key := demandKey{bucketStart(slot, location, IntervalMonth), period}
+        if peak, ok := peaks[key]; !ok || energy > meter[peak] {
+            peaks[key] = slot
+        }
+    }
+
+    hours := tariffSlot.Hours()
+    for key, peak := range peaks {
+        monthEnd := bucketEnd(key.month, IntervalMonth)
+        start, end := key.month, monthEnd
+        if from.After(start) {
+            start = from
+        }
+        if to.Before(end) {
+            end = to
+        }
+        ratio := float64(end.Sub(start)) / float64(monthEnd.Sub(key.month))
+        price := tariff[key.period].period.DemandPrice * ratio
+
+        cost := meter[peak] / hours * price
+        plant.DemandCost += cost
+        periods[key.period].DemandCost += cost
+        for id, energies := range assets {
+            byAsset[id].DemandCost += energies[peak] / hours * price
+        }
+    }
+
+    plant.total()
+    for i := range periods {
+        periods[i].total()
+    }
+    for _, cost := range byAsset {
+        cost.total()
+    }
+    return plant, periods, byAsset
+}
@@ -0,0 +1,60 @@
+package plants
+
+import "github.com/jeandeducla/api-plant/internal/models"
+
+func (db *PlantsDB) GetCurtailmentsByPlantId(id uint) ([]models.CurtailmentEvent, error) {
+    var curtailments []models.CurtailmentEvent
+    result := db.gorm.Where("plant_id = ?", id).Order("starts_at").Find(&curtailments)
+    if result.Error != nil {
+        return curtailments, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return curtailments, ErrEmptyResult
+    }
+    return curtailments, nil
+}
+
+func (db *PlantsDB) CreateCurtailment(curtailment *models.CurtailmentEvent) error {
+    result := db.gorm.Create(curtailment)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetCurtailmentByPlantId(plant_id uint, curtailment_id uint) (*models.CurtailmentEvent, error) {
+    var curtailment models.CurtailmentEvent
+    result := db.gorm.Where("plant_id = ?", plant_id).Find(&curtailment, curtailment_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &curtailment, nil
+}
+
+func (db *PlantsDB) DeleteCurtailmentById(curtailment_id uint) error {
+    result := db.gorm.Delete(&models.CurtailmentEvent{}, curtailment_id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) UpdateCurtailment(curtailment *models.CurtailmentEvent) error {
+    result := db.gorm.Model(curtailment).Updates(curtailment)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
@@ -0,0 +1,234 @@
+package plants
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    CurtailmentPlanned   = "planned"
+    CurtailmentActive    = "active"
+    CurtailmentCompleted = "completed"
+    CurtailmentFailed    = "failed"
+)
+
+var (
+    ErrCurtailmentWindow = errors.New("Curtailment must end after it starts")
+    ErrCurtailmentPower = errors.New("Curtailment TargetPower is too big for the plant assets")
+    ErrCurtailmentStatus = errors.New("Curtailment Status must be one of 'planned', 'active', 'completed' or 'failed'")
+    ErrCurtailmentTransition = errors.New("Curtailment Status cannot change this way")
+    ErrNotEnoughMeasurements = errors.New("Not enough measurements to evaluate the curtailment")
+)
+
+// curtailmentTransitions lists, for each status, the statuses it can move to.
+// Completed and failed events are final.
+var curtailmentTransitions = map[string][]string{
+    CurtailmentPlanned: {CurtailmentActive, CurtailmentFailed},
+    CurtailmentActive:  {CurtailmentCompleted, CurtailmentFailed},
+}
+
+func isValidCurtailmentStatus(status string) bool {
+    return status == CurtailmentPlanned || status == CurtailmentActive || status == CurtailmentCompleted || status == CurtailmentFailed
+}
+
+func canTransitionCurtailment(from string, to string) bool {
+    for _, status := range curtailmentTransitions[from] {
+        if status == to {
+            return true
+        }
+    }
+    return false
+}
+
+func (s *Service) GetPlantCurtailments(id uint) ([]models.CurtailmentEvent, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+    curtailments, err := s.DB.GetCurtailmentsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return curtailments, nil
+}
+
+type CreateCurtailmentInput struct {
+    TargetPower models.Power `json:"target_power" binding:"required"`
+    StartsAt    time.Time    `json:"starts_at"    binding:"required"`
+    EndsAt      time.Time    `json:"ends_at"      binding:"required"`
+}
+
+func (s *Service) CreateCurtailment(id uint, input CreateCurtailmentInput) error {
+    if !input.EndsAt.After(input.StartsAt) {
+        return ErrCurtailmentWindow
+    }
+
+    plant, err := s.DB.GetPlantById(id)
+    if err != nil {
+        return err
+    }
+
+    // the plant cannot shed more than what its available assets draw at
+    // full power
+    assets, err := s.availablePlantAssets(id, input.StartsAt, input.EndsAt)
+    if err != nil {
+        return err
+    }
+    if input.TargetPower.Watts() > sumAssetPower(assets).Watts() {
+        return ErrCurtailmentPower
+    }
+
+    curtailment := models.CurtailmentEvent{
+        PlantID: id,
+        TargetPower: input.TargetPower,
+        StartsAt: input.StartsAt,
+        EndsAt: input.EndsAt,
+        Status: CurtailmentPlanned,
+    }
+    err = s.transaction(func(tx DB) error {
+        if err := tx.CreateCurtailment(&curtailment); err != nil {
+            return err
+        }
+        return s.emit(tx, EventCurtailmentCreated, curtailment.ID, &id, nil, curtailment)
+    })
+    if err != nil {
+        return err
+    }
+    s.notifyPlantManagers(id, curtailmentMessage(plant, &curtailment))
+    return nil
+}
+
+func (s *Service) GetPlantCurtailment(plant_id uint, curtailment_id uint) (*models.CurtailmentEvent, error) {
+    if _, err := s.DB.GetPlantById(plant_id); err != nil {
+        return nil, err
+    }
+    return s.DB.GetCurtailmentByPlantId(plant_id, curtailment_id)
+}
+
+func (s *Service) DeletePlantCurtailment(plant_id uint, curtailment_id uint) error {
+    if _, err := s.GetPlantCurtailment(plant_id, curtailment_id); err != nil {
+        return err
+    }
+    return s.transaction(func(tx DB) error {
+        if err := tx.DeleteCurtailmentById(curtailment_id); err != nil {
+            return err
+        }
+        return s.emit(tx, EventCurtailmentDeleted, curtailment_id, &plant_id, nil, deletedResource{ID: curtailment_id, PlantID: &plant_id})
+    })
+}
+
+type UpdateCurtailmentStatusInput struct {
+    Status string `json:"status" binding:"required"`
+}
+
+func (s *Service) UpdatePlantCurtailmentStatus(plant_id uint, curtailment_id uint, input UpdateCurtailmentStatusInput) error {
+    if !isValidCurtailmentStatus(input.Status) {
+        return ErrCurtailmentStatus
+    }
+
+    curtailment, err := s.GetPlantCurtailment(plant_id, curtailment_id)
+    if err != nil {
+        return err
+    }
+    if !canTransitionCurtailment(curtailment.Status, input.Status) {
+        return ErrCurtailmentTransition
+    }
+
+    from := curtailment.Status
+    curtailment.Status = input.Status
+    err = s.transaction(func(tx DB) error {
+        if err := tx.UpdateCurtailment(curtailment); err != nil {
+            return err
+        }
+        changed := curtailmentStatusChanged{Curtailment: curtailment, From: from}
+        if err := s.emit(tx, EventCurtailmentStatusChanged, curtailment.ID, &plant_id, nil, changed); err != nil {
+            return err
+        }
+        if curtailment.Status != CurtailmentFailed {
+            return nil
+        }
+        return s.openAlarm(tx, alarm{
+            Code: AlarmCurtailmentFailed,
+            Source: EntityCurtailment,
+            SourceID: curtailment.ID,
+            PlantID: plant_id,
+        })
+    })
+    if err != nil {
+        return err
+    }
+    plant, err := s.DB.GetPlantById(plant_id)
+    if err != nil {
+        return err
+    }
+    s.notifyPlantManagers(plant_id, curtailmentMessage(plant, curtailment))
+    return nil
+}
+
+// curtailmentStatusChanged is the data of the curtailment.status_changed
+// event.
+type curtailmentStatusChanged struct {
+    Curtailment *models.CurtailmentEvent `json:"curtailment"`
+    From        string                   `json:"from"`
+}
+
+func (c curtailmentStatusChanged) render(contract func(interface{}) interface{}) interface{} {
+    return struct {
+        Curtailment interface{} `json:"curtailment"`
+        From        string      `json:"from"`
+    }{contract(c.Curtailment), c.From}
+}
+
+// CurtailmentEvaluation compares the requested reduction to the one actually
+// delivered. The baseline is the plant's average load over a window of the
+// same length right before the event, as seen by the plant's main meter.
+type CurtailmentEvaluation struct {
+    CurtailmentID  uint         `json:"curtailment_id"`
+    RequestedPower models.Power `json:"requested_power"`
+    BaselinePower  float64      `json:"baseline_power"`
+    MeasuredPower  float64      `json:"measured_power"`
+    DeliveredPower float64      `json:"delivered_power"`
+    DeliveryRatio  float64      `json:"delivery_ratio"`
+    Met            bool         `json:"met"`
+}
+
+func (s *Service) EvaluatePlantCurtailment(plant_id uint, curtailment_id uint) (*CurtailmentEvaluation, error) {
+    curtailment, err := s.GetPlantCurtailment(plant_id, curtailment_id)
+    if err != nil {
+        return nil, err
+    }
+
+    duration := curtailment.EndsAt.Sub(curtailment.StartsAt)
+    baselineFrom := curtailment.StartsAt.Add(-duration)
+    measurements, err := s.DB.GetMeasurementsByPlantId(plant_id, baselineFrom, curtailment.EndsAt)
+    if err == ErrEmptyResult {
+        return nil, ErrNotEnoughMeasurements
+    } else if err != nil {
+        return nil, err
+    }
+    measurements = plantMeterMeasurements(measurements)
+
+    baseline, ok := averagePower(measurements, baselineFrom, curtailment.StartsAt)
+    if !ok {
+        return nil, ErrNotEnoughMeasurements
+    }
+    measured, ok := averagePower(measurements, curtailment.StartsAt, curtailment.EndsAt)
+    if !ok {
+        return nil, ErrNotEnoughMeasurements
+    }
+
+    // the measurements are in kW, compared to the target in watts
+    delivered := baseline - measured
+    delivered_watts := delivered * 1000
+    requested_watts := float64(curtailment.TargetPower.Watts())
+    return &CurtailmentEvaluation{
+        CurtailmentID: curtailment.ID,
+        RequestedPower: curtailment.TargetPower,
+        BaselinePower: baseline,
+        MeasuredPower: measured,
+        DeliveredPower: delivered,
+        DeliveryRatio: delivered_watts / requested_watts,
+        Met: delivered_watts >= requested_watts,
+    }, nil
+}
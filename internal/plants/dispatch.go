@@ -0,0 +1,117 @@
+package plants
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// dispatchCandidate is an asset that can take part in a curtailment along
+// with the reduction it can deliver over the window.
+type dispatchCandidate struct {
+    asset    models.Asset
+    downward models.Power
+}
+
+// sumLargest sums the n biggest reductions of candidates.
+func sumLargest(candidates []dispatchCandidate, n int) models.Power {
+    sorted := make([]dispatchCandidate, len(candidates))
+    copy(sorted, candidates)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        return sorted[i].downward.Watts() > sorted[j].downward.Watts()
+    })
+    var sum models.Power
+    for i := 0; i < n && i < len(sorted); i++ {
+        sum = sum.Add(sorted[i].downward)
+    }
+    return sum
+}
+
+// minimalAssetCount returns the smallest number of candidates whose
+// reductions reach target, or false if even all of them together cannot.
+func minimalAssetCount(candidates []dispatchCandidate, target models.Power) (int, bool) {
+    for n := 1; n <= len(candidates); n++ {
+        if sumLargest(candidates, n).Watts() >= target.Watts() {
+            return n, true
+        }
+    }
+    return 0, false
+}
+
+// allocateReduction spreads a target reduction over as few candidates as
+// possible. Among the sets of that size able to reach the target, the one
+// with the lowest priorities is picked: candidates are considered by
+// priority and kept whenever the remaining slots can still close the gap.
+// The chosen assets are then loaded in priority order, each up to its
+// available reduction.
+func allocateReduction(candidates []dispatchCandidate, target models.Power) ([]dispatchCandidate, error) {
+    if target.IsZero() {
+        return []dispatchCandidate{}, nil
+    }
+
+    byPriority := make([]dispatchCandidate, 0, len(candidates))
+    for _, candidate := range candidates {
+        if candidate.downward.Watts() > 0 {
+            byPriority = append(byPriority, candidate)
+        }
+    }
+    sort.SliceStable(byPriority, func(i, j int) bool {
+        if byPriority[i].asset.Priority != byPriority[j].asset.Priority {
+            return byPriority[i].asset.Priority < byPriority[j].asset.Priority
+        }
+        return byPriority[i].asset.ID < byPriority[j].asset.ID
+    })
+
+    count, ok := minimalAssetCount(byPriority, target)
+    if !ok {
+        return nil, ErrDispatchFlexibility
+    }
+
+    chosen := make([]dispatchCandidate, 0, count)
+    var chosenSum models.Power
+    for i, candidate := range byPriority {
+        if len(chosen) == count {
+            break
+        }
+        slotsLeft := count - len(chosen) - 1
+        if chosenSum.Add(candidate.downward).Add(sumLargest(byPriority[i+1:], slotsLeft)).Watts() >= target.Watts() {
+            chosen = append(chosen, candidate)
+            chosenSum = chosenSum.Add(candidate.downward)
+        }
+    }
+
+    remaining := target
+    allocation := make([]dispatchCandidate, 0, len(chosen))
+    for _, candidate := range chosen {
+        reduction := candidate.downward
+        if reduction.Watts() > remaining.Watts() {
+            reduction = remaining
+        }
+        allocation = append(allocation, dispatchCandidate{asset: candidate.asset, downward: reduction})
+        remaining = remaining.Sub(reduction)
+    }
+    return allocation, nil
+}
+
+// dispatchSetpoints turns an allocation into a setpoint schedule. Assets
+// are assumed to run at full power before the event.
+func dispatchSetpoints(allocation []dispatchCandidate, from time.Time, to time.Time) []models.DispatchSetpoint {
+    setpoints := make([]models.DispatchSetpoint, 0, len(allocation))
+    for _, a := range allocation {
+        rampStartsAt := from
+        if a.asset.RampDownRate > 0 {
+            rampMinutes := a.downward.Kilowatts() / float64(a.asset.RampDownRate)
+            rampStartsAt = from.Add(-time.Duration(rampMinutes * float64(time.Minute)))
+        }
+        setpoints = append(setpoints, models.DispatchSetpoint{
+            AssetID: a.asset.ID,
+            Reduction: a.downward,
+            Setpoint: a.asset.MaxPower.Sub(a.downward),
+            RampStartsAt: rampStartsAt,
+            StartsAt: from,
+            EndsAt: to,
+        })
+    }
+    return setpoints
+}
@@ -0,0 +1,56 @@
+package plants
+
+import (
+	"gorm.io/gorm/clause"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+func (db *PlantsDB) GetDispatchPlansByPlantId(id uint) ([]models.DispatchPlan, error) {
+    var plans []models.DispatchPlan
+    result := db.gorm.Preload("Setpoints").Where("plant_id = ?", id).Find(&plans)
+    if result.Error != nil {
+        return plans, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return plans, ErrEmptyResult
+    }
+    return plans, nil
+}
+
+func (db *PlantsDB) CreateDispatchPlan(plan *models.DispatchPlan) error {
+    result := db.gorm.Create(plan)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetDispatchPlanByPlantId(plant_id uint, plan_id uint) (*models.DispatchPlan, error) {
+    var plan models.DispatchPlan
+    result := db.gorm.Preload("Setpoints").Where("plant_id = ?", plant_id).Find(&plan, plan_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &plan, nil
+}
+
+// LockDispatchPlanById locks a dispatch plan until the end of the
+// transaction.
+func (db *PlantsDB) LockDispatchPlanById(id uint) error {
+    var plan models.DispatchPlan
+    result := db.gorm.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Find(&plan, id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
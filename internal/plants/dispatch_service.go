@@ -0,0 +1,98 @@
+package plants
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+var (
+    ErrDispatchTarget = errors.New("Dispatch plan needs either a curtailment or a target reduction with a window")
+    ErrDispatchFlexibility = errors.New("Plant assets are not flexible enough to reach the target reduction")
+)
+
+func (s *Service) GetPlantDispatchPlans(id uint) ([]models.DispatchPlan, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+    plans, err := s.DB.GetDispatchPlansByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return plans, nil
+}
+
+// CreateDispatchPlanInput either points to a curtailment of the plant, whose
+// target and window are then used, or gives them explicitly.
+type CreateDispatchPlanInput struct {
+    CurtailmentID   *uint        `json:"curtailment_id"`
+    TargetReduction models.Power `json:"target_reduction"`
+    StartsAt        time.Time    `json:"starts_at"`
+    EndsAt          time.Time    `json:"ends_at"`
+}
+
+// CreateDispatchPlan decides which assets to curtail and by how much. In dry
+// run mode the plan is computed but not saved.
+func (s *Service) CreateDispatchPlan(id uint, input CreateDispatchPlanInput, dryRun bool) (*models.DispatchPlan, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+
+    plan := models.DispatchPlan{
+        PlantID: id,
+        CurtailmentID: input.CurtailmentID,
+        TargetReduction: input.TargetReduction,
+        StartsAt: input.StartsAt,
+        EndsAt: input.EndsAt,
+    }
+    var curtailment_id uint
+    if input.CurtailmentID != nil {
+        curtailment, err := s.DB.GetCurtailmentByPlantId(id, *input.CurtailmentID)
+        if err != nil {
+            return nil, err
+        }
+        curtailment_id = curtailment.ID
+        plan.TargetReduction = curtailment.TargetPower
+        plan.StartsAt = curtailment.StartsAt
+        plan.EndsAt = curtailment.EndsAt
+    }
+    if plan.TargetReduction.IsZero() || plan.StartsAt.IsZero() || plan.EndsAt.IsZero() {
+        return nil, ErrDispatchTarget
+    }
+    if !plan.EndsAt.After(plan.StartsAt) {
+        return nil, ErrCurtailmentWindow
+    }
+
+    assets, flexibilities, err := s.plantAssetsFlexibility(id, plan.StartsAt, plan.EndsAt, curtailment_id)
+    if err != nil {
+        return nil, err
+    }
+    candidates := make([]dispatchCandidate, 0, len(assets))
+    for i, asset := range assets {
+        if flexibilities[i].Available {
+            candidates = append(candidates, dispatchCandidate{asset: asset, downward: flexibilities[i].Downward})
+        }
+    }
+
+    allocation, err := allocateReduction(candidates, plan.TargetReduction)
+    if err != nil {
+        return nil, err
+    }
+    plan.Setpoints = dispatchSetpoints(allocation, plan.StartsAt, plan.EndsAt)
+
+    if dryRun {
+        return &plan, nil
+    }
+    if err := s.DB.CreateDispatchPlan(&plan); err != nil {
+        return nil, err
+    }
+    return &plan, nil
+}
+
+func (s *Service) GetPlantDispatchPlan(plant_id uint, plan_id uint) (*models.DispatchPlan, error) {
+    if _, err := s.DB.GetPlantById(plant_id); err != nil {
+        return nil, err
+    }
+    return s.DB.GetDispatchPlanByPlantId(plant_id, plan_id)
+}
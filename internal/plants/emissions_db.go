@@ -0,0 +1,73 @@
+package plants
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"gorm.io/gorm"
+)
+
+func (db *PlantsDB) GetStaticEmissionFactors() ([]models.EmissionFactor, error) {
+    var factors []models.EmissionFactor
+    result := db.gorm.Where("starts_at IS NULL").Order("zone").Find(&factors)
+    if result.Error != nil {
+        return factors, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return factors, ErrEmptyResult
+    }
+    return factors, nil
+}
+
+// SetStaticEmissionFactor creates or replaces the static factor of a zone.
+func (db *PlantsDB) SetStaticEmissionFactor(factor *models.EmissionFactor) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        err := tx.Where("zone = ? AND starts_at IS NULL", factor.Zone).Delete(&models.EmissionFactor{}).Error
+        if err != nil {
+            return err
+        }
+        return tx.Create(factor).Error
+    })
+}
+
+func (db *PlantsDB) DeleteStaticEmissionFactor(zone string) error {
+    result := db.gorm.Where("zone = ? AND starts_at IS NULL", zone).Delete(&models.EmissionFactor{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+// GetEmissionFactors returns the static factors of zones along with their
+// hourly factors starting in [from, to).
+func (db *PlantsDB) GetEmissionFactors(zones []string, from time.Time, to time.Time) ([]models.EmissionFactor, error) {
+    var factors []models.EmissionFactor
+    result := db.gorm.
+        Where("zone IN ? AND (starts_at IS NULL OR (starts_at >= ? AND starts_at < ?))", zones, from, to).
+        Order("starts_at").
+        Find(&factors)
+    if result.Error != nil {
+        return factors, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return factors, ErrEmptyResult
+    }
+    return factors, nil
+}
+
+// ReplaceHourlyEmissionFactors replaces the hourly factors of a zone starting
+// in [from, to) by factors.
+func (db *PlantsDB) ReplaceHourlyEmissionFactors(zone string, from time.Time, to time.Time, factors []models.EmissionFactor) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        err := tx.
+            Where("zone = ? AND starts_at >= ? AND starts_at < ?", zone, from, to).
+            Delete(&models.EmissionFactor{}).Error
+        if err != nil {
+            return err
+        }
+        return tx.CreateInBatches(&factors, 1000).Error
+    })
+}
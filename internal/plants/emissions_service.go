@@ -0,0 +1,330 @@
+package plants
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+var (
+    ErrEmissionZone = errors.New("Emission factor Zone cannot be empty")
+    ErrEmissionFactor = errors.New("Emission factor Factor cannot be negative")
+    ErrEmissionFactorsCSV = errors.New("Emission factors must be 'timestamp,factor' CSV records starting on whole hours")
+)
+
+// EmissionsBreakdown is what some consumption emitted. Energy is in kWh and
+// Emissions in kgCO2e; UnknownEnergy is the part of Energy consumed when no
+// emission factor applied to the plant.
+type EmissionsBreakdown struct {
+    Energy        float64 `json:"energy"`
+    Emissions     float64 `json:"emissions"`
+    UnknownEnergy float64 `json:"unknown_energy"`
+}
+
+func (e *EmissionsBreakdown) add(other EmissionsBreakdown) {
+    e.Energy += other.Energy
+    e.Emissions += other.Emissions
+    e.UnknownEnergy += other.UnknownEnergy
+}
+
+type AssetEmissions struct {
+    AssetID uint `json:"asset_id"`
+    EmissionsBreakdown
+}
+
+// PlantEmissions is what a plant emitted over [From, To). The plant totals
+// come from its main meter.
+type PlantEmissions struct {
+    PlantID uint             `json:"plant_id"`
+    From    time.Time        `json:"from"`
+    To      time.Time        `json:"to"`
+    EmissionsBreakdown
+    Assets  []AssetEmissions `json:"assets"`
+}
+
+// EnergyManagerEmissions sums the emissions of the plants of an energy
+// manager.
+type EnergyManagerEmissions struct {
+    EnergyManagerID uint             `json:"energy_manager_id"`
+    From            time.Time        `json:"from"`
+    To              time.Time        `json:"to"`
+    EmissionsBreakdown
+    Plants          []PlantEmissions `json:"plants"`
+}
+
+func normalizeZone(zone string) (string, error) {
+    zone = strings.TrimSpace(zone)
+    if zone == "" {
+        return "", ErrEmissionZone
+    }
+    return zone, nil
+}
+
+// plantZones returns the zones whose factors apply to a plant, the most
+// precise first.
+func plantZones(plant *models.Plant) []string {
+    zones := []string{}
+    for _, zone := range []string{plant.GridZone, plant.CountryCode} {
+        if zone != "" && (len(zones) == 0 || zones[0] != zone) {
+            zones = append(zones, zone)
+        }
+    }
+    return zones
+}
+
+// emissionFactors finds the factor applying to a plant at any hour.
+type emissionFactors struct {
+    zones  []string
+    static map[string]float64
+    hourly map[string]map[time.Time]float64
+}
+
+func newEmissionFactors(zones []string, factors []models.EmissionFactor) emissionFactors {
+    res := emissionFactors{
+        zones: zones,
+        static: map[string]float64{},
+        hourly: map[string]map[time.Time]float64{},
+    }
+    for _, factor := range factors {
+        if factor.StartsAt == nil {
+            res.static[factor.Zone] = factor.Factor
+            continue
+        }
+        if res.hourly[factor.Zone] == nil {
+            res.hourly[factor.Zone] = map[time.Time]float64{}
+        }
+        res.hourly[factor.Zone][factor.StartsAt.UTC()] = factor.Factor
+    }
+    return res
+}
+
+// at returns the factor at t: an hourly factor of the most precise zone
+// having one, then a static one.
+func (f emissionFactors) at(t time.Time) (float64, bool) {
+    hour := t.Truncate(time.Hour).UTC()
+    for _, zone := range f.zones {
+        if factor, ok := f.hourly[zone][hour]; ok {
+            return factor, true
+        }
+    }
+    for _, zone := range f.zones {
+        if factor, ok := f.static[zone]; ok {
+            return factor, true
+        }
+    }
+    return 0, false
+}
+
+// emissions weighs slot energies, as returned by slotEnergies, by the
+// factors.
+func (f emissionFactors) emissions(energies map[time.Time]float64) EmissionsBreakdown {
+    res := EmissionsBreakdown{}
+    for slot, energy := range energies {
+        res.Energy += energy
+        factor, ok := f.at(slot)
+        if !ok {
+            res.UnknownEnergy += energy
+            continue
+        }
+        // factors are in g/kWh, emissions in kg
+        res.Emissions += energy * factor / 1000
+    }
+    return res
+}
+
+func (s *Service) GetStaticEmissionFactors() ([]models.EmissionFactor, error) {
+    factors, err := s.DB.GetStaticEmissionFactors()
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return factors, nil
+}
+
+type SetEmissionFactorInput struct {
+    Factor *float64 `json:"factor" binding:"required"`
+}
+
+// SetStaticEmissionFactor sets the factor of a zone, in gCO2e per kWh.
+func (s *Service) SetStaticEmissionFactor(zone string, input SetEmissionFactorInput) error {
+    zone, err := normalizeZone(zone)
+    if err != nil {
+        return err
+    }
+    if *input.Factor < 0 {
+        return ErrEmissionFactor
+    }
+    return s.DB.SetStaticEmissionFactor(&models.EmissionFactor{Zone: zone, Factor: *input.Factor})
+}
+
+func (s *Service) DeleteStaticEmissionFactor(zone string) error {
+    zone, err := normalizeZone(zone)
+    if err != nil {
+        return err
+    }
+    return s.DB.DeleteStaticEmissionFactor(zone)
+}
+
+// GetHourlyEmissionFactors returns the hourly factors of a zone starting in
+// [from, to).
+func (s *Service) GetHourlyEmissionFactors(zone string, from time.Time, to time.Time) ([]models.EmissionFactor, error) {
+    zone, err := normalizeZone(zone)
+    if err != nil {
+        return nil, err
+    }
+    factors, err := s.DB.GetEmissionFactors([]string{zone}, from, to)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    hourly := []models.EmissionFactor{}
+    for _, factor := range factors {
+        if factor.StartsAt != nil {
+            hourly = append(hourly, factor)
+        }
+    }
+    return hourly, nil
+}
+
+// parseEmissionFactors reads 'timestamp,factor' records, timestamps being
+// RFC 3339 whole hours. A header line is skipped.
+func parseEmissionFactors(zone string, r io.Reader) ([]models.EmissionFactor, error) {
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = 2
+    reader.TrimLeadingSpace = true
+    factors := []models.EmissionFactor{}
+    seen := map[time.Time]bool{}
+    for line := 1; ; line++ {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, fmt.Errorf("%w: %s", ErrEmissionFactorsCSV, err)
+        }
+        starts_at, err := time.Parse(time.RFC3339, record[0])
+        if err != nil && line == 1 {
+            continue
+        }
+        if err != nil || !starts_at.Truncate(time.Hour).Equal(starts_at) || seen[starts_at.UTC()] {
+            return nil, fmt.Errorf("%w: line %d", ErrEmissionFactorsCSV, line)
+        }
+        factor, err := strconv.ParseFloat(record[1], 64)
+        if err != nil {
+            return nil, fmt.Errorf("%w: line %d", ErrEmissionFactorsCSV, line)
+        }
+        if factor < 0 {
+            return nil, ErrEmissionFactor
+        }
+        starts_at = starts_at.UTC()
+        seen[starts_at] = true
+        factors = append(factors, models.EmissionFactor{Zone: zone, StartsAt: &starts_at, Factor: factor})
+    }
+    if len(factors) == 0 {
+        return nil, ErrEmissionFactorsCSV
+    }
+    return factors, nil
+}
+
+// ImportEmissionFactors loads an hourly time series of factors of a zone
+// from CSV. It replaces the factors the zone had between the first and the
+// last hour of the series.
+func (s *Service) ImportEmissionFactors(zone string, r io.Reader) error {
+    zone, err := normalizeZone(zone)
+    if err != nil {
+        return err
+    }
+    factors, err := parseEmissionFactors(zone, r)
+    if err != nil {
+        return err
+    }
+    sort.Slice(factors, func(i, j int) bool {
+        return factors[i].StartsAt.Before(*factors[j].StartsAt)
+    })
+    from := *factors[0].StartsAt
+    to := factors[len(factors)-1].StartsAt.Add(time.Hour)
+    return s.DB.ReplaceHourlyEmissionFactors(zone, from, to, factors)
+}
+
+func (s *Service) plantEmissions(plant *models.Plant, from time.Time, to time.Time) (*PlantEmissions, error) {
+    zones := plantZones(plant)
+    factors := []models.EmissionFactor{}
+    if len(zones) > 0 {
+        var err error
+        factors, err = s.DB.GetEmissionFactors(zones, from.Truncate(time.Hour), to)
+        if err != nil && err != ErrEmptyResult {
+            return nil, err
+        }
+    }
+    intensity := newEmissionFactors(zones, factors)
+
+    // the last reading before from still holds at from
+    measurements, err := s.DB.GetMeasurementsByPlantId(plant.ID, from.Add(-measurementMaxHold), to)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    byAsset := map[uint][]models.Measurement{}
+    for _, m := range measurements {
+        if m.AssetID != nil {
+            byAsset[*m.AssetID] = append(byAsset[*m.AssetID], m)
+        }
+    }
+
+    res := PlantEmissions{
+        PlantID: plant.ID,
+        From: from,
+        To: to,
+        EmissionsBreakdown: intensity.emissions(slotEnergies(plantMeterMeasurements(measurements), from, to)),
+        Assets: make([]AssetEmissions, 0, len(byAsset)),
+    }
+    for asset_id, readings := range byAsset {
+        res.Assets = append(res.Assets, AssetEmissions{
+            AssetID: asset_id,
+            EmissionsBreakdown: intensity.emissions(slotEnergies(readings, from, to)),
+        })
+    }
+    sort.Slice(res.Assets, func(i, j int) bool {
+        return res.Assets[i].AssetID < res.Assets[j].AssetID
+    })
+    return &res, nil
+}
+
+// GetPlantEmissions computes the emissions of a plant and of its assets over
+// [from, to) from their measured consumption and the factors of the plant's
+// grid zone or country.
+func (s *Service) GetPlantEmissions(id uint, from time.Time, to time.Time) (*PlantEmissions, error) {
+    plant, err := s.DB.GetPlantById(id)
+    if err != nil {
+        return nil, err
+    }
+    return s.plantEmissions(plant, from, to)
+}
+
+// GetEnergyManagerEmissions sums the emissions of the plants an energy
+// manager is currently assigned to with role, any role when empty.
+func (s *Service) GetEnergyManagerEmissions(id uint, role string, from time.Time, to time.Time) (*EnergyManagerEmissions, error) {
+    plants, err := s.GetEnergyManagerPlants(id, role)
+    if err != nil {
+        return nil, err
+    }
+    res := EnergyManagerEmissions{
+        EnergyManagerID: id,
+        From: from,
+        To: to,
+        Plants: make([]PlantEmissions, 0, len(plants)),
+    }
+    for i := range plants {
+        emissions, err := s.plantEmissions(&plants[i], from, to)
+        if err != nil {
+            return nil, err
+        }
+        res.add(emissions.EmissionsBreakdown)
+        res.Plants = append(res.Plants, *emissions)
+    }
+    return &res, nil
+}
@@ -0,0 +1,77 @@
+package plants
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// eventLogLock is the key of the advisory lock taken by the transactions
+// that log events.
+const eventLogLock = 0x6576656e74 // "event"
+
+// CreateEvent logs an event. The transaction it is part of holds the lock
+// of the log until it ends, so that the transactions logging events commit
+// one after the other and the ids of the events are in commit order: once
+// an event is visible, every event with a smaller id is too, and a reader
+// resuming after the last id it saw misses none.
+func (db *PlantsDB) CreateEvent(event *models.Event) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", eventLogLock).Error; err != nil {
+            return err
+        }
+        result := tx.Create(event)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+        return nil
+    })
+}
+
+func (db *PlantsDB) GetLastEventId() (uint, error) {
+    var event models.Event
+    result := db.gorm.Select("id").Order("id DESC").Limit(1).Find(&event)
+    if result.Error != nil {
+        return 0, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return 0, ErrEmptyResult
+    }
+    return event.ID, nil
+}
+
+// GetEventsAfter returns at most limit events logged after the event
+// `after` that match filter, oldest first. Event ids being in commit order,
+// see CreateEvent, no event committed later can come before those. The
+// plants of the energy manager of the filter are those it is assigned to at
+// `at`.
+func (db *PlantsDB) GetEventsAfter(after uint, filter EventFilter, at time.Time, limit int) ([]models.Event, error) {
+    var events []models.Event
+    query := db.gorm.Where("id > ?", after)
+    if filter.PlantID != nil {
+        query = query.Where("plant_id = ?", *filter.PlantID)
+    }
+    if filter.EnergyManagerID != nil {
+        plants := db.gorm.Model(&models.PlantAssignment{}).
+            Select("plant_id").
+            Where("energy_manager_id = ? AND valid_from <= ?", *filter.EnergyManagerID, at).
+            Where("valid_to IS NULL OR valid_to > ?", at)
+        query = query.Where("energy_manager_id = ? OR plant_id IN (?)", *filter.EnergyManagerID, plants)
+    }
+    if len(filter.EntityTypes) > 0 {
+        query = query.Where("entity_type IN ?", filter.EntityTypes)
+    }
+    result := query.Order("id").Limit(limit).Find(&events)
+    if result.Error != nil {
+        return events, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return events, ErrEmptyResult
+    }
+    return events, nil
+}
@@ -0,0 +1,238 @@
+package plants
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/dto"
+	"github.com/jeandeducla/api-plant/internal/dto/v1"
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// event types
+const (
+    EventEmCreated    = "em.created"
+    EventEmUpdated    = "em.updated"
+    EventEmDeleted    = "em.deleted"
+    EventPlantCreated = "plant.created"
+    EventPlantUpdated = "plant.updated"
+    EventPlantDeleted = "plant.deleted"
+    EventAssetCreated = "asset.created"
+    EventAssetUpdated = "asset.updated"
+    EventAssetDeleted = "asset.deleted"
+    EventAssetMoved   = "asset.moved"
+
+    EventCurtailmentCreated       = "curtailment.created"
+    EventCurtailmentStatusChanged = "curtailment.status_changed"
+    EventCurtailmentDeleted       = "curtailment.deleted"
+    EventAlarmOpened              = "alarm.opened"
+)
+
+var eventTypes = []string{
+    EventEmCreated, EventEmUpdated, EventEmDeleted,
+    EventPlantCreated, EventPlantUpdated, EventPlantDeleted,
+    EventAssetCreated, EventAssetUpdated, EventAssetDeleted, EventAssetMoved,
+    EventCurtailmentCreated, EventCurtailmentStatusChanged, EventCurtailmentDeleted,
+    EventAlarmOpened,
+}
+
+// entity types, the prefix of the event types
+const (
+    EntityEm          = "em"
+    EntityPlant       = "plant"
+    EntityAsset       = "asset"
+    EntityCurtailment = "curtailment"
+    EntityAlarm       = "alarm"
+)
+
+// alarm codes
+const (
+    AlarmCommandRejected   = "command_rejected"
+    AlarmCommandExpired    = "command_expired"
+    AlarmCurtailmentFailed = "curtailment_failed"
+)
+
+const (
+    // eventsBatchSize bounds how many events are read from the log at once.
+    eventsBatchSize = 100
+    // eventPollInterval bounds how long a stream can miss an event logged by
+    // another instance of the service.
+    eventPollInterval = time.Second
+)
+
+var (
+    ErrEventEntityType = errors.New("Event entity type must be one of 'em', 'plant', 'asset', 'curtailment' or 'alarm'")
+)
+
+// deletedResource is the data of the events of deleted resources.
+type deletedResource struct {
+    ID      uint  `json:"id"`
+    PlantID *uint `json:"plant_id,omitempty"`
+}
+
+// alarm is the data of the alarm.opened event: something went wrong on a
+// plant that someone has to look at. Alarms are not stored on their own, an
+// alarm is identified by the resource that raised it, Source being its
+// entity type and SourceID its id, which is also the entity id of the event.
+type alarm struct {
+    Code     string `json:"code"`
+    Source   string `json:"source"`
+    SourceID uint   `json:"source_id"`
+    PlantID  uint   `json:"plant_id"`
+    AssetID  *uint  `json:"asset_id,omitempty"`
+    Reason   string `json:"reason,omitempty"`
+}
+
+func isEventType(event_type string) bool {
+    for _, t := range eventTypes {
+        if t == event_type {
+            return true
+        }
+    }
+    return false
+}
+
+func isEntityType(entity_type string) bool {
+    return entity_type == EntityEm || entity_type == EntityPlant || entity_type == EntityAsset ||
+        entity_type == EntityCurtailment || entity_type == EntityAlarm
+}
+
+// transaction runs change in a transaction, so that the events it emits are
+// only logged if it is committed, then wakes up the streams and the relay of
+// the outbox.
+func (s *Service) transaction(change func(tx DB) error) error {
+    if err := s.DB.Transaction(change); err != nil {
+        return err
+    }
+    s.eventSignals.notify(0)
+    s.wakeOutbox()
+    return nil
+}
+
+// emit appends an event to the log and writes it to the outbox of the sinks,
+// in the transaction tx of the change it comes from. entity_id is the
+// changed resource, plant_id the plant it belongs to and em_id the energy
+// manager it is, if any. data is kept as both contracts of the API render
+// it, the transports sending the version they speak.
+func (s *Service) emit(tx DB, event_type string, entity_id uint, plant_id *uint, em_id *uint, data interface{}) error {
+    payload, err := renderEventData(data, dto.New)
+    if err != nil {
+        return err
+    }
+    payload_v1, err := renderEventData(data, v1.New)
+    if err != nil {
+        return err
+    }
+    event := models.Event{
+        Type: event_type,
+        EntityType: strings.SplitN(event_type, ".", 2)[0],
+        EntityID: entity_id,
+        PlantID: plant_id,
+        EnergyManagerID: em_id,
+        Data: payload,
+        DataV1: payload_v1,
+    }
+    event.CreatedAt = s.now().UTC()
+    if err := tx.CreateEvent(&event); err != nil {
+        return err
+    }
+    return s.writeOutbox(tx, &event)
+}
+
+// eventBody is the data of an event that wraps resources, it renders them
+// through the contract given.
+type eventBody interface {
+    render(contract func(interface{}) interface{}) interface{}
+}
+
+// renderEventData marshals the data of an event as the contract renders it,
+// so that the models never leave the service as they are stored.
+func renderEventData(data interface{}, contract func(interface{}) interface{}) (json.RawMessage, error) {
+    if body, ok := data.(eventBody); ok {
+        return json.Marshal(body.render(contract))
+    }
+    return json.Marshal(contract(data))
+}
+
+// openAlarm emits the alarm.opened event of an alarm, in the transaction tx
+// of the change that raised it.
+func (s *Service) openAlarm(tx DB, a alarm) error {
+    return s.emit(tx, EventAlarmOpened, a.SourceID, &a.PlantID, nil, a)
+}
+
+// EventFilter selects events of the log, its zero value selects them all.
+// EnergyManagerID selects the events of an energy manager and those of the
+// plants it is assigned to when they are read.
+type EventFilter struct {
+    PlantID         *uint
+    EnergyManagerID *uint
+    EntityTypes     []string
+}
+
+// CheckEventFilter makes sure the plant and energy manager of a filter
+// exist.
+func (s *Service) CheckEventFilter(filter EventFilter) error {
+    for _, entity_type := range filter.EntityTypes {
+        if !isEntityType(entity_type) {
+            return ErrEventEntityType
+        }
+    }
+    if filter.PlantID != nil {
+        if _, err := s.DB.GetPlantById(*filter.PlantID); err != nil {
+            return err
+        }
+    }
+    if filter.EnergyManagerID != nil {
+        if _, err := s.DB.GetEnergyManagerById(*filter.EnergyManagerID); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// LastEventID is the id of the latest event of the log, 0 when it is empty.
+func (s *Service) LastEventID() (uint, error) {
+    id, err := s.DB.GetLastEventId()
+    if err == ErrEmptyResult {
+        return 0, nil
+    }
+    return id, err
+}
+
+// WaitEvents returns the events of the log after the event `after` that
+// match filter, oldest first. When there is none it waits up to `wait` for
+// one to be logged. Events are numbered in the order they are committed, so
+// waiting again after the last event returned misses none.
+func (s *Service) WaitEvents(ctx context.Context, filter EventFilter, after uint, wait time.Duration) ([]models.Event, error) {
+    timeout := time.NewTimer(wait)
+    defer timeout.Stop()
+    for {
+        signal := s.eventSignals.wait(0)
+        events, err := s.DB.GetEventsAfter(after, filter, s.now(), eventsBatchSize)
+        if err != nil && err != ErrEmptyResult {
+            s.eventSignals.forget(0, signal)
+            return nil, err
+        }
+        if len(events) > 0 {
+            s.eventSignals.forget(0, signal)
+            return events, nil
+        }
+
+        done := false
+        select {
+        case <-signal:
+        case <-time.After(eventPollInterval):
+        case <-timeout.C:
+            done = true
+        case <-ctx.Done():
+            done = true
+        }
+        s.eventSignals.forget(0, signal)
+        if done {
+            return []models.Event{}, nil
+        }
+    }
+}
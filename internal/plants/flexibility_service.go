@@ -0,0 +1,155 @@
+package plants
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    UnavailableNoticePeriod    = "notice_period"
+    UnavailableMaxEventsPerDay = "max_events_per_day"
+)
+
+// AssetFlexibility is how much an asset can move its consumption over a
+// time window. Downward is a reduction from full power, upward an increase
+// from a standstill.
+type AssetFlexibility struct {
+    AssetID   uint         `json:"asset_id"`
+    Upward    models.Power `json:"upward"`
+    Downward  models.Power `json:"downward"`
+    Available bool         `json:"available"`
+    Reason    string       `json:"reason,omitempty"`
+}
+
+type PlantFlexibility struct {
+    PlantID  uint               `json:"plant_id"`
+    From     time.Time          `json:"from"`
+    To       time.Time          `json:"to"`
+    Upward   models.Power       `json:"upward"`
+    Downward models.Power       `json:"downward"`
+    Assets   []AssetFlexibility `json:"assets"`
+}
+
+// rampLimit caps power to what a ramp of rate kW/min reaches over the window.
+func rampLimit(power models.Power, rate uint, window time.Duration) models.Power {
+    if rate == 0 {
+        return power
+    }
+    reachable := models.Watts(int64(window.Minutes() * float64(rate) * 1000))
+    if reachable.Watts() < power.Watts() {
+        return reachable
+    }
+    return power
+}
+
+// assetFlexibility computes the flexibility of an asset over [from, to).
+// Downward, the asset can be switched off only if the window is long enough
+// to respect its minimum off time, otherwise it can only modulate between its
+// MaxPower and MinPower. Upward, the asset starts from a standstill: it
+// cannot be switched on for less than its minimum run time, and then offers
+// no flexibility. eventsThatDay is the number of curtailments already planned
+// on the plant on the day the window starts.
+func assetFlexibility(asset models.Asset, from time.Time, to time.Time, now time.Time, eventsThatDay int) AssetFlexibility {
+    res := AssetFlexibility{AssetID: asset.ID}
+
+    if from.Before(now.Add(time.Duration(asset.NoticePeriod) * time.Minute)) {
+        res.Reason = UnavailableNoticePeriod
+        return res
+    }
+    if asset.MaxEventsPerDay > 0 && eventsThatDay >= int(asset.MaxEventsPerDay) {
+        res.Reason = UnavailableMaxEventsPerDay
+        return res
+    }
+
+    window := to.Sub(from)
+    downward := asset.MaxPower.Sub(asset.MinPower)
+    if window >= time.Duration(asset.MinOffTime) * time.Minute {
+        downward = asset.MaxPower
+    }
+    upward := models.Watts(0)
+    if window >= time.Duration(asset.MinRunTime) * time.Minute {
+        upward = asset.MaxPower
+    }
+
+    res.Available = true
+    res.Downward = rampLimit(downward, asset.RampDownRate, window)
+    res.Upward = rampLimit(upward, asset.RampUpRate, window)
+    return res
+}
+
+// countEventsOnDay counts the curtailments starting on the same day as t,
+// leaving out the one with the ID ignored.
+func countEventsOnDay(curtailments []models.CurtailmentEvent, t time.Time, ignored uint) int {
+    year, month, day := t.Date()
+    var count int
+    for _, curtailment := range curtailments {
+        if curtailment.ID == ignored {
+            continue
+        }
+        y, m, d := curtailment.StartsAt.In(t.Location()).Date()
+        if y == year && m == month && d == day {
+            count++
+        }
+    }
+    return count
+}
+
+// plantAssetsFlexibility returns the plant's assets along with their
+// flexibility over [from, to). curtailment_id is the curtailment being
+// served, if any, so that it does not count against the daily event limit.
+func (s *Service) plantAssetsFlexibility(id uint, from time.Time, to time.Time, curtailment_id uint) ([]models.Asset, []AssetFlexibility, error) {
+    plant, err := s.DB.GetPlantById(id)
+    if err != nil {
+        return nil, nil, err
+    }
+    location := plantLocation(plant)
+    assets, err := s.DB.GetAssetsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, nil, err
+    }
+    curtailments, err := s.DB.GetCurtailmentsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, nil, err
+    }
+    // the daily event limit applies to the local day of the plant
+    eventsThatDay := countEventsOnDay(curtailments, from.In(location), curtailment_id)
+    windows, err := s.DB.GetMaintenanceWindowsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, nil, err
+    }
+
+    now := s.now()
+    flexibilities := make([]AssetFlexibility, 0, len(assets))
+    for _, asset := range assets {
+        if available, reason := assetAvailability(asset, windows, location, from, to); !available {
+            flexibilities = append(flexibilities, AssetFlexibility{AssetID: asset.ID, Reason: reason})
+            continue
+        }
+        flexibilities = append(flexibilities, assetFlexibility(asset, from, to, now, eventsThatDay))
+    }
+    return assets, flexibilities, nil
+}
+
+func (s *Service) GetPlantFlexibility(id uint, from time.Time, to time.Time) (*PlantFlexibility, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+
+    _, flexibilities, err := s.plantAssetsFlexibility(id, from, to, 0)
+    if err != nil {
+        return nil, err
+    }
+
+    res := PlantFlexibility{
+        PlantID: id,
+        From: from,
+        To: to,
+        Assets: flexibilities,
+    }
+    for _, flexibility := range flexibilities {
+        res.Upward = res.Upward.Add(flexibility.Upward)
+        res.Downward = res.Downward.Add(flexibility.Downward)
+    }
+    return &res, nil
+}
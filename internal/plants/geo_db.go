@@ -0,0 +1,110 @@
+package plants
+
+import (
+	"sort"
+
+	"github.com/jeandeducla/api-plant/internal/geo"
+	"github.com/jeandeducla/api-plant/internal/models"
+	"gorm.io/gorm"
+)
+
+// PlantQuery filters plants by location. Zero fields do not filter.
+type PlantQuery struct {
+    // Near and RadiusKm select the plants within RadiusKm of Near, closest
+    // first.
+    Near        *geo.Point
+    RadiusKm    float64
+    BBox        *geo.BBox
+    GridZone    string
+    CountryCode string
+}
+
+// hasPostGIS tells whether the PostGIS extension is installed, it is only
+// checked once.
+func (db *PlantsDB) hasPostGIS() bool {
+    db.postgisOnce.Do(func() {
+        var count int64
+        db.gorm.Raw("SELECT count(*) FROM pg_extension WHERE extname = 'postgis'").Scan(&count)
+        db.postgis = count > 0
+    })
+    return db.postgis
+}
+
+func (db *PlantsDB) SearchPlants(query PlantQuery) ([]models.Plant, error) {
+    tx := selectPlants(db.gorm.Model(&models.Plant{}))
+    if query.GridZone != "" {
+        tx = tx.Where("grid_zone = ?", query.GridZone)
+    }
+    if query.CountryCode != "" {
+        tx = tx.Where("country_code = ?", query.CountryCode)
+    }
+    if query.BBox != nil {
+        tx = whereInBBox(tx, *query.BBox)
+    }
+    if query.Near == nil {
+        var plants []models.Plant
+        if err := tx.Order("id").Find(&plants).Error; err != nil {
+            return nil, err
+        }
+        return plants, nil
+    }
+
+    near := *query.Near
+    if db.hasPostGIS() {
+        point := "geography(ST_SetSRID(ST_MakePoint(longitude, latitude), 4326))"
+        center := "geography(ST_SetSRID(ST_MakePoint(?, ?), 4326))"
+        var plants []models.Plant
+        err := tx.
+            Where("latitude IS NOT NULL AND longitude IS NOT NULL").
+            Where("ST_DWithin("+point+", "+center+", ?)", near.Longitude, near.Latitude, query.RadiusKm*1000).
+            Order(gorm.Expr("ST_Distance("+point+", "+center+")", near.Longitude, near.Latitude)).
+            Find(&plants).Error
+        if err != nil {
+            return nil, err
+        }
+        return plants, nil
+    }
+
+    // without PostGIS the database only narrows down the latitudes, the
+    // distances are computed here
+    span := geo.LatitudeSpan(query.RadiusKm)
+    var candidates []models.Plant
+    err := tx.
+        Where("latitude BETWEEN ? AND ?", near.Latitude-span, near.Latitude+span).
+        Where("longitude IS NOT NULL").
+        Find(&candidates).Error
+    if err != nil {
+        return nil, err
+    }
+    return plantsWithin(candidates, near, query.RadiusKm), nil
+}
+
+func whereInBBox(tx *gorm.DB, bbox geo.BBox) *gorm.DB {
+    tx = tx.Where("latitude BETWEEN ? AND ?", bbox.MinLatitude, bbox.MaxLatitude)
+    if bbox.CrossesAntimeridian() {
+        return tx.Where("(longitude >= ? OR longitude <= ?)", bbox.MinLongitude, bbox.MaxLongitude)
+    }
+    return tx.Where("longitude BETWEEN ? AND ?", bbox.MinLongitude, bbox.MaxLongitude)
+}
+
+func plantPoint(plant models.Plant) geo.Point {
+    return geo.Point{Latitude: *plant.Latitude, Longitude: *plant.Longitude}
+}
+
+// plantsWithin keeps the located plants within radius km of near, closest
+// first.
+func plantsWithin(plants []models.Plant, near geo.Point, radius float64) []models.Plant {
+    res := []models.Plant{}
+    for _, plant := range plants {
+        if plant.Latitude == nil || plant.Longitude == nil {
+            continue
+        }
+        if geo.Distance(near, plantPoint(plant)) <= radius {
+            res = append(res, plant)
+        }
+    }
+    sort.SliceStable(res, func(i, j int) bool {
+        return geo.Distance(near, plantPoint(res[i])) < geo.Distance(near, plantPoint(res[j]))
+    })
+    return res
+}
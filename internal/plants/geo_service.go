@@ -0,0 +1,97 @@
+package plants
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/jeandeducla/api-plant/internal/geo"
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// geocodeTimeout bounds the time spent locating an address.
+const geocodeTimeout = 5 * time.Second
+
+var (
+    ErrPlantLocation = errors.New("Plant Latitude and Longitude must be given together, within [-90, 90] and [-180, 180]")
+    ErrPlantCountry = errors.New("Plant CountryCode must be an ISO 3166-1 alpha-2 code, like FR")
+    ErrPlantQuery = errors.New("Plants can be searched either near a point, with a positive radius, or within a bbox")
+)
+
+func normalizeCountryCode(code string) (string, error) {
+    if code == "" {
+        return "", nil
+    }
+    region, err := language.ParseRegion(code)
+    if err != nil || len(code) != 2 || !region.IsCountry() {
+        return "", ErrPlantCountry
+    }
+    return region.String(), nil
+}
+
+// locatePlant sets the location of a plant. Coordinates given as input win,
+// otherwise the address is geocoded when it is new. A plant whose address
+// cannot be located has no coordinates.
+func (s *Service) locatePlant(plant *models.Plant, latitude *float64, longitude *float64, grid_zone string, country_code string, address_changed bool) error {
+    country_code, err := normalizeCountryCode(country_code)
+    if err != nil {
+        return err
+    }
+    plant.GridZone = grid_zone
+    plant.CountryCode = country_code
+
+    if latitude != nil || longitude != nil {
+        if latitude == nil || longitude == nil {
+            return ErrPlantLocation
+        }
+        if !(geo.Point{Latitude: *latitude, Longitude: *longitude}).Valid() {
+            return ErrPlantLocation
+        }
+        plant.Latitude = latitude
+        plant.Longitude = longitude
+        return nil
+    }
+    if !address_changed {
+        return nil
+    }
+
+    plant.Latitude = nil
+    plant.Longitude = nil
+    if s.Geocoder == nil {
+        return nil
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), geocodeTimeout)
+    defer cancel()
+    location, err := s.Geocoder.Geocode(ctx, plant.Address)
+    if errors.Is(err, geo.ErrNotFound) {
+        return nil
+    } else if err != nil {
+        return err
+    }
+    plant.Latitude = &location.Latitude
+    plant.Longitude = &location.Longitude
+    if plant.CountryCode == "" {
+        plant.CountryCode, _ = normalizeCountryCode(location.CountryCode)
+    }
+    return nil
+}
+
+// SearchPlants returns the plants matching a location query.
+func (s *Service) SearchPlants(query PlantQuery) ([]models.Plant, error) {
+    if query.Near != nil && (query.BBox != nil || query.RadiusKm <= 0) {
+        return nil, ErrPlantQuery
+    }
+    if query.Near == nil && query.RadiusKm != 0 {
+        return nil, ErrPlantQuery
+    }
+    country_code, err := normalizeCountryCode(query.CountryCode)
+    if err != nil {
+        return nil, err
+    }
+    query.CountryCode = country_code
+    query.GridZone = strings.TrimSpace(query.GridZone)
+    return s.DB.SearchPlants(query)
+}
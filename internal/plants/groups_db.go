@@ -0,0 +1,61 @@
+package plants
+
+import "github.com/jeandeducla/api-plant/internal/models"
+
+func (db *PlantsDB) GetGroupsByPlantId(id uint) ([]models.AssetGroup, error) {
+    var groups []models.AssetGroup
+    result := db.gorm.Where("plant_id = ?", id).Order("id").Find(&groups)
+    if result.Error != nil {
+        return groups, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return groups, ErrEmptyResult
+    }
+    return groups, nil
+}
+
+func (db *PlantsDB) CreateGroup(group *models.AssetGroup) error {
+    result := db.gorm.Create(group)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetGroupByPlantId(plant_id uint, group_id uint) (*models.AssetGroup, error) {
+    var group models.AssetGroup
+    result := db.gorm.Where("plant_id = ?", plant_id).Find(&group, group_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &group, nil
+}
+
+func (db *PlantsDB) DeleteGroupById(group_id uint) error {
+    result := db.gorm.Delete(&models.AssetGroup{}, group_id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) UpdateGroup(group *models.AssetGroup) error {
+    // a group can be moved back to the root or have its power limit removed
+    result := db.gorm.Model(group).Select("*").Updates(group)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
@@ -0,0 +1,303 @@
+package plants
+
+import (
+	"errors"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+var (
+    ErrGroupKind = errors.New("Group Kind must be one of 'building', 'line' or 'zone'")
+    ErrGroupParent = errors.New("Group parent must be another group of the same plant, outside of its subtree")
+    ErrGroupPower = errors.New("Group MaxPower is too small for the assets it contains")
+    ErrAssetGroup = errors.New("Asset group must belong to the same plant")
+    ErrAssetGroupPower = errors.New("Asset MaxPower is too big for one of its groups")
+)
+
+// GroupNode is a group with its sub groups and the assets directly attached
+// to it.
+type GroupNode struct {
+    models.AssetGroup
+    Assets   []models.Asset `json:"assets"`
+    Children []GroupNode    `json:"children"`
+}
+
+// PlantTree is the whole structure of a plant: the assets attached to the
+// plant itself and the top level groups.
+type PlantTree struct {
+    PlantID uint           `json:"plant_id"`
+    Assets  []models.Asset `json:"assets"`
+    Groups  []GroupNode    `json:"groups"`
+}
+
+func isGroupKind(kind string) bool {
+    return kind == "building" || kind == "line" || kind == "zone"
+}
+
+// groupSubtree returns the ids of a group and of all its descendants.
+func groupSubtree(groups []models.AssetGroup, group_id uint) map[uint]bool {
+    subtree := map[uint]bool{group_id: true}
+    // groups are few per plant, iterating until nothing changes is enough
+    for changed := true; changed; {
+        changed = false
+        for _, group := range groups {
+            if group.ParentID != nil && subtree[*group.ParentID] && !subtree[group.ID] {
+                subtree[group.ID] = true
+                changed = true
+            }
+        }
+    }
+    return subtree
+}
+
+// groupAncestors returns a group followed by its parent, grand parent, ...
+// up to the root of the tree.
+func groupAncestors(groups []models.AssetGroup, group_id uint) []models.AssetGroup {
+    byId := map[uint]models.AssetGroup{}
+    for _, group := range groups {
+        byId[group.ID] = group
+    }
+    ancestors := []models.AssetGroup{}
+    seen := map[uint]bool{}
+    for id := &group_id; id != nil && !seen[*id]; {
+        group, ok := byId[*id]
+        if !ok {
+            break
+        }
+        seen[*id] = true
+        ancestors = append(ancestors, group)
+        id = group.ParentID
+    }
+    return ancestors
+}
+
+// subtreeAssets returns the assets attached to one of the groups of subtree.
+func subtreeAssets(assets []models.Asset, subtree map[uint]bool) []models.Asset {
+    res := []models.Asset{}
+    for _, asset := range assets {
+        if asset.GroupID != nil && subtree[*asset.GroupID] {
+            res = append(res, asset)
+        }
+    }
+    return res
+}
+
+// checkAssetGroup makes sure group_id, if any, belongs to plant_id and that
+// none of its power limits is exceeded once asset_id draws max_power. Pass
+// a zero asset_id for an asset that does not exist yet. The groups and
+// assets are read from db, which can be a transaction.
+func (s *Service) checkAssetGroup(db DB, plant_id uint, group_id *uint, asset_id uint, max_power models.Power) error {
+    if group_id == nil {
+        return nil
+    }
+    groups, err := db.GetGroupsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    ancestors := groupAncestors(groups, *group_id)
+    if len(ancestors) == 0 {
+        return ErrAssetGroup
+    }
+
+    assets, err := db.GetAssetsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    for _, group := range ancestors {
+        if group.MaxPower.IsZero() {
+            continue
+        }
+        power := max_power
+        for _, asset := range subtreeAssets(assets, groupSubtree(groups, group.ID)) {
+            if asset.ID != asset_id {
+                power = power.Add(asset.MaxPower)
+            }
+        }
+        if power.Watts() > group.MaxPower.Watts() {
+            return ErrAssetGroupPower
+        }
+    }
+    return nil
+}
+
+func (s *Service) GetPlantGroups(id uint) ([]models.AssetGroup, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+    groups, err := s.DB.GetGroupsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return groups, nil
+}
+
+func buildGroupNodes(groups []models.AssetGroup, assets []models.Asset, parent_id *uint) []GroupNode {
+    nodes := []GroupNode{}
+    for _, group := range groups {
+        if (parent_id == nil) != (group.ParentID == nil) {
+            continue
+        }
+        if parent_id != nil && *parent_id != *group.ParentID {
+            continue
+        }
+        id := group.ID
+        nodes = append(nodes, GroupNode{
+            AssetGroup: group,
+            Assets: subtreeAssets(assets, map[uint]bool{id: true}),
+            Children: buildGroupNodes(groups, assets, &id),
+        })
+    }
+    return nodes
+}
+
+// GetPlantTree returns the groups of a plant as a tree, with the assets
+// attached at each level.
+func (s *Service) GetPlantTree(id uint) (*PlantTree, error) {
+    groups, err := s.GetPlantGroups(id)
+    if err != nil {
+        return nil, err
+    }
+    assets, err := s.DB.GetAssetsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+
+    tree := PlantTree{PlantID: id, Assets: []models.Asset{}}
+    for _, asset := range assets {
+        if asset.GroupID == nil {
+            tree.Assets = append(tree.Assets, asset)
+        }
+    }
+    tree.Groups = buildGroupNodes(groups, assets, nil)
+    return &tree, nil
+}
+
+type CreateGroupInput struct {
+    Name     string       `json:"name"      binding:"required"`
+    Kind     string       `json:"kind"      binding:"required"`
+    ParentID *uint        `json:"parent_id"`
+    MaxPower models.Power `json:"max_power"`
+}
+
+func (s *Service) CreateGroup(id uint, input CreateGroupInput) error {
+    if !isGroupKind(input.Kind) {
+        return ErrGroupKind
+    }
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return err
+    }
+    if input.ParentID != nil {
+        if _, err := s.DB.GetGroupByPlantId(id, *input.ParentID); err == ErrEmptyResult {
+            return ErrGroupParent
+        } else if err != nil {
+            return err
+        }
+    }
+    return s.DB.CreateGroup(&models.AssetGroup{
+        PlantID: id,
+        ParentID: input.ParentID,
+        Name: input.Name,
+        Kind: input.Kind,
+        MaxPower: input.MaxPower,
+    })
+}
+
+func (s *Service) GetPlantGroup(plant_id uint, group_id uint) (*models.AssetGroup, error) {
+    if _, err := s.DB.GetPlantById(plant_id); err != nil {
+        return nil, err
+    }
+    return s.DB.GetGroupByPlantId(plant_id, group_id)
+}
+
+// GetPlantGroupAssets returns the assets attached to a group, or to the group
+// and all its descendants when recursive is set.
+func (s *Service) GetPlantGroupAssets(plant_id uint, group_id uint, recursive bool) ([]models.Asset, error) {
+    if _, err := s.GetPlantGroup(plant_id, group_id); err != nil {
+        return nil, err
+    }
+    groups, err := s.DB.GetGroupsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    assets, err := s.DB.GetAssetsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+
+    subtree := map[uint]bool{group_id: true}
+    if recursive {
+        subtree = groupSubtree(groups, group_id)
+    }
+    return subtreeAssets(assets, subtree), nil
+}
+
+// DeletePlantGroup deletes a group and its sub groups. Their assets are
+// attached back to the plant.
+func (s *Service) DeletePlantGroup(plant_id uint, group_id uint) error {
+    if _, err := s.GetPlantGroup(plant_id, group_id); err != nil {
+        return err
+    }
+    return s.DB.DeleteGroupById(group_id)
+}
+
+type UpdateGroupInput struct {
+    Name     string       `json:"name"      binding:"required"`
+    Kind     string       `json:"kind"      binding:"required"`
+    ParentID *uint        `json:"parent_id"`
+    MaxPower models.Power `json:"max_power"`
+}
+
+func (s *Service) UpdatePlantGroup(plant_id uint, group_id uint, input UpdateGroupInput) error {
+    if !isGroupKind(input.Kind) {
+        return ErrGroupKind
+    }
+    group, err := s.GetPlantGroup(plant_id, group_id)
+    if err != nil {
+        return err
+    }
+    groups, err := s.DB.GetGroupsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+
+    subtree := groupSubtree(groups, group_id)
+    if input.ParentID != nil {
+        // a group cannot be moved under itself or one of its descendants
+        if subtree[*input.ParentID] || len(groupAncestors(groups, *input.ParentID)) == 0 {
+            return ErrGroupParent
+        }
+    }
+
+    // business rule enforcement
+    assets, err := s.DB.GetAssetsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    contained := sumAssetPower(subtreeAssets(assets, subtree))
+    if !input.MaxPower.IsZero() && contained.Watts() > input.MaxPower.Watts() {
+        return ErrGroupPower
+    }
+    if input.ParentID != nil {
+        // the assets moved along with the group count against the new ancestors
+        for _, ancestor := range groupAncestors(groups, *input.ParentID) {
+            if ancestor.MaxPower.IsZero() {
+                continue
+            }
+            power := contained
+            for _, asset := range subtreeAssets(assets, groupSubtree(groups, ancestor.ID)) {
+                if !subtree[*asset.GroupID] {
+                    power = power.Add(asset.MaxPower)
+                }
+            }
+            if power.Watts() > ancestor.MaxPower.Watts() {
+                return ErrGroupPower
+            }
+        }
+    }
+
+    group.Name = input.Name
+    group.Kind = input.Kind
+    group.ParentID = input.ParentID
+    group.MaxPower = input.MaxPower
+    return s.DB.UpdateGroup(group)
+}
@@ -0,0 +1,70 @@
+package plants
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// CreateIdempotencyKey records a request under its key, unless the key is
+// already taken: it then returns ErrEmptyResult.
+func (db *PlantsDB) CreateIdempotencyKey(key *models.IdempotencyKey) error {
+    result := db.gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetIdempotencyKey(key string) (*models.IdempotencyKey, error) {
+    var res models.IdempotencyKey
+    result := db.gorm.Where("key = ?", key).Find(&res)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &res, nil
+}
+
+// UpdateIdempotencyKey stores the response of a key still in progress. It
+// returns ErrEmptyResult when the key is no longer in progress, or was deleted.
+func (db *PlantsDB) UpdateIdempotencyKey(key *models.IdempotencyKey) error {
+    result := db.gorm.Model(key).Where("status = 0").Select("*").Updates(key)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) DeleteIdempotencyKey(id uint) error {
+    result := db.gorm.Delete(&models.IdempotencyKey{}, id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+// DeleteIdempotencyKeysBefore deletes the keys created before `before`.
+func (db *PlantsDB) DeleteIdempotencyKeysBefore(before time.Time) error {
+    result := db.gorm.Where("created_at < ?", before).Delete(&models.IdempotencyKey{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
@@ -0,0 +1,112 @@
+package plants
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// idempotencyKeyTTL is how long a request made with an idempotency key is
+// replayed for. The key can be used anew afterwards.
+const idempotencyKeyTTL = 24 * time.Hour
+
+// idempotencyKeyLease is how long a request holds its key while it is being
+// handled. A key still held after that was abandoned by a request that never
+// finished, like one of an instance that stopped, and is given to the next
+// request made with it.
+const idempotencyKeyLease = time.Minute
+
+// idempotencyPurgeInterval is how often the expired keys are deleted.
+const idempotencyPurgeInterval = time.Hour
+
+var (
+    ErrIdempotencyKeyReuse = errors.New("Idempotency-Key was already used for another request")
+    ErrIdempotencyKeyInProgress = errors.New("Idempotency-Key is used by a request still being handled, retry later")
+)
+
+// StartIdempotentRequest records that the request hashed to hash is being
+// handled under key. It returns the record of the key: a completed one holds
+// the response of the request previously made with the key, to be replayed,
+// one still in progress (Status 0) was created for the request to be handled.
+// A key used for another request gives ErrIdempotencyKeyReuse, one used by a
+// request still being handled ErrIdempotencyKeyInProgress.
+func (s *Service) StartIdempotentRequest(key string, hash string) (*models.IdempotencyKey, error) {
+    record := &models.IdempotencyKey{Key: key, RequestHash: hash}
+    err := s.DB.CreateIdempotencyKey(record)
+    if err == nil {
+        return record, nil
+    }
+    if err != ErrEmptyResult {
+        return nil, err
+    }
+
+    previous, err := s.DB.GetIdempotencyKey(key)
+    if err == ErrEmptyResult {
+        // released in the meantime
+        return s.StartIdempotentRequest(key, hash)
+    }
+    if err != nil {
+        return nil, err
+    }
+    age := s.now().Sub(previous.CreatedAt)
+    if age > idempotencyKeyTTL || (previous.Status == 0 && age > idempotencyKeyLease) {
+        // deleted by id, for a request taking the key over at the same time
+        // to keep it
+        if err := s.DB.DeleteIdempotencyKey(previous.ID); err != nil && err != ErrEmptyResult {
+            return nil, err
+        }
+        return s.StartIdempotentRequest(key, hash)
+    }
+    if previous.RequestHash != hash {
+        return nil, ErrIdempotencyKeyReuse
+    }
+    if previous.Status == 0 {
+        return nil, ErrIdempotencyKeyInProgress
+    }
+    return previous, nil
+}
+
+// CompleteIdempotentRequest stores the response of the request handled under
+// the record StartIdempotentRequest gave, for it to be replayed. It returns
+// ErrEmptyResult when the key was taken over meanwhile.
+func (s *Service) CompleteIdempotentRequest(record *models.IdempotencyKey, status int, content_type string, body []byte) error {
+    record.Status = status
+    record.ContentType = content_type
+    record.Body = body
+    return s.DB.UpdateIdempotencyKey(record)
+}
+
+// ReleaseIdempotencyKey forgets the request handled under the record
+// StartIdempotentRequest gave, so that it is handled again when retried.
+func (s *Service) ReleaseIdempotencyKey(record *models.IdempotencyKey) error {
+    err := s.DB.DeleteIdempotencyKey(record.ID)
+    if err == ErrEmptyResult {
+        return nil
+    }
+    return err
+}
+
+// PurgeIdempotencyKeys deletes the expired idempotency keys until ctx is done.
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context) {
+    for {
+        if err := s.purgeIdempotencyKeys(); err != nil {
+            log.Printf("idempotency keys: %v", err)
+        }
+        select {
+        case <-ctx.Done():
+            return
+        case <-time.After(idempotencyPurgeInterval):
+        }
+    }
+}
+
+func (s *Service) purgeIdempotencyKeys() error {
+    err := s.DB.DeleteIdempotencyKeysBefore(s.now().Add(-idempotencyKeyTTL))
+    if err == ErrEmptyResult {
+        return nil
+    }
+    return err
+}
@@ -0,0 +1,146 @@
+package plants
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    // maxIncludeDepth bounds how many relationships an include follows, like
+    // plants.assets.
+    maxIncludeDepth = 2
+    // maxIncluded bounds how many resources a read includes, all
+    // relationships together.
+    maxIncluded = 500
+)
+
+var (
+    ErrInclude = errors.New("Include must be a comma separated list of relationships, like 'plants.assets'")
+    ErrIncludeDepth = errors.New("Include cannot follow more than 2 relationships")
+    ErrIncludeSize = errors.New("Include cannot embed more than 500 resources, read the relationship on its own")
+)
+
+// the relationships an energy manager and a plant can be read with
+var (
+    energyManagerIncludes = []string{"plants", "plants.assets"}
+    plantIncludes = []string{"assets", "energy_manager", "energy_manager.plants"}
+)
+
+// Include is the set of relationships a resource is read with, each along
+// with the relationships it goes through: including plants.assets includes
+// plants too.
+type Include map[string]bool
+
+// ParseInclude reads a comma separated list of relationships, like
+// 'assets,energy_manager'. An empty list includes nothing.
+func ParseInclude(param string) (Include, error) {
+    include := Include{}
+    if strings.TrimSpace(param) == "" {
+        return include, nil
+    }
+    for _, path := range strings.Split(param, ",") {
+        names := strings.Split(strings.TrimSpace(path), ".")
+        if len(names) > maxIncludeDepth {
+            return nil, ErrIncludeDepth
+        }
+        for i, name := range names {
+            if name == "" {
+                return nil, ErrInclude
+            }
+            include[strings.Join(names[:i+1], ".")] = true
+        }
+    }
+    return include, nil
+}
+
+// check makes sure every relationship of the include is among allowed.
+func (include Include) check(allowed []string) error {
+    for path := range include {
+        found := false
+        for _, name := range allowed {
+            found = found || name == path
+        }
+        if !found {
+            return ErrInclude
+        }
+    }
+    return nil
+}
+
+// GetEnergyManagerIncluding returns an energy manager along with the plants
+// it is currently assigned to, and their assets, as include tells.
+func (s *Service) GetEnergyManagerIncluding(id uint, include Include) (*models.EnergyManager, error) {
+    if err := include.check(energyManagerIncludes); err != nil {
+        return nil, err
+    }
+    em, err := s.DB.GetEnergyManagerById(id)
+    if err != nil {
+        return nil, err
+    }
+    if !include["plants"] {
+        return em, nil
+    }
+
+    assets_limit := 0
+    if include["plants.assets"] {
+        assets_limit = maxIncluded + 1
+    }
+    plants, err := s.DB.GetCurrentPlantsByEnergyManagerId(id, s.assignmentNow(), maxIncluded + 1, assets_limit)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    included := len(plants)
+    for _, plant := range plants {
+        included += len(plant.Assets)
+    }
+    if included > maxIncluded {
+        return nil, ErrIncludeSize
+    }
+    em.Plants = plants
+    return em, nil
+}
+
+// GetPlantIncluding returns a plant along with its assets, its current
+// primary energy manager and the plants of the latter, as include tells. A
+// plant without a primary energy manager is read without one.
+func (s *Service) GetPlantIncluding(id uint, include Include) (*models.Plant, error) {
+    if err := include.check(plantIncludes); err != nil {
+        return nil, err
+    }
+    var plant *models.Plant
+    var err error
+    if include["assets"] {
+        plant, err = s.DB.GetPlantByIdWithAssets(id, maxIncluded + 1)
+    } else {
+        plant, err = s.DB.GetPlantById(id)
+    }
+    if err != nil {
+        return nil, err
+    }
+    included := len(plant.Assets)
+
+    if include["energy_manager"] {
+        now := s.assignmentNow()
+        em, err := s.DB.GetPrimaryEnergyManagerByPlantId(id, now)
+        if err != nil && err != ErrEmptyResult {
+            return nil, err
+        }
+        if em != nil && include["energy_manager.plants"] {
+            em.Plants, err = s.DB.GetCurrentPlantsByEnergyManagerId(em.ID, now, maxIncluded + 1, 0)
+            if err != nil && err != ErrEmptyResult {
+                return nil, err
+            }
+            included += len(em.Plants)
+        }
+        if em != nil {
+            included++
+        }
+        plant.EnergyManager = em
+    }
+    if included > maxIncluded {
+        return nil, ErrIncludeSize
+    }
+    return plant, nil
+}
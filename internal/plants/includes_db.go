@@ -0,0 +1,79 @@
+package plants
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// preloadAssets preloads the assets of the plants query reads, at most limit
+// of them all plants together.
+func preloadAssets(query *gorm.DB, limit int) *gorm.DB {
+    return query.Preload("Assets", func(db *gorm.DB) *gorm.DB {
+        return db.Order("assets.id").Limit(limit)
+    })
+}
+
+// GetPlantByIdWithAssets returns a plant along with at most limit of its
+// assets.
+func (db *PlantsDB) GetPlantByIdWithAssets(id uint, limit int) (*models.Plant, error) {
+    var plant models.Plant
+    result := selectPlants(preloadAssets(db.gorm, limit)).Find(&plant, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &plant, nil
+}
+
+// GetCurrentPlantsByEnergyManagerId returns at most limit of the plants an
+// energy manager is assigned to at `at`, with any role, along with at most
+// assets_limit of their assets all together. Their assets are not read when
+// assets_limit is 0.
+func (db *PlantsDB) GetCurrentPlantsByEnergyManagerId(id uint, at time.Time, limit int, assets_limit int) ([]models.Plant, error) {
+    var plants []models.Plant
+    query := db.gorm
+    if assets_limit > 0 {
+        query = preloadAssets(query, assets_limit)
+    }
+    result := query.
+        Joins("JOIN plant_assignments ON plant_assignments.plant_id = plants.id").
+        Where("plant_assignments.energy_manager_id = ?", id).
+        Where("plant_assignments.valid_from <= ?", at).
+        Where("plant_assignments.valid_to IS NULL OR plant_assignments.valid_to > ?", at).
+        Distinct("plants.*", primaryEnergyManagerColumn).Order("plants.id").Limit(limit).
+        Find(&plants)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return plants, ErrEmptyResult
+    }
+    return plants, nil
+}
+
+// GetPrimaryEnergyManagerByPlantId returns the primary energy manager of a
+// plant at `at`.
+func (db *PlantsDB) GetPrimaryEnergyManagerByPlantId(id uint, at time.Time) (*models.EnergyManager, error) {
+    var em models.EnergyManager
+    result := db.gorm.
+        Joins("JOIN plant_assignments ON plant_assignments.energy_manager_id = energy_managers.id").
+        Where("plant_assignments.plant_id = ?", id).
+        Where("plant_assignments.role = ?", AssignmentPrimary).
+        Where("plant_assignments.valid_from <= ?", at).
+        Where("plant_assignments.valid_to IS NULL OR plant_assignments.valid_to > ?", at).
+        Order("plant_assignments.valid_from DESC").
+        Limit(1).
+        Find(&em)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &em, nil
+}
@@ -0,0 +1,72 @@
+package plants
+
+import (
+	"time"
+)
+
+// LoadBucket is the load of a plant over a local hour, day, week or month.
+// AveragePower (kW) and Energy (kWh) are nil when there is no reading in the
+// bucket.
+type LoadBucket struct {
+    StartsAt     time.Time `json:"starts_at"`
+    EndsAt       time.Time `json:"ends_at"`
+    Readings     int       `json:"readings"`
+    AveragePower *float64  `json:"average_power"`
+    Energy       *float64  `json:"energy"`
+}
+
+type LoadCurve struct {
+    PlantID  uint         `json:"plant_id"`
+    Timezone string       `json:"timezone"`
+    Interval string       `json:"interval"`
+    Buckets  []LoadBucket `json:"buckets"`
+}
+
+// GetPlantLoadCurve buckets the readings of the plant's main meter over
+// [from, to) along the plant's local calendar. The buckets are whole: the
+// first one starts at the beginning of the local day (week, ...) holding
+// from, the last one ends after to.
+func (s *Service) GetPlantLoadCurve(id uint, from time.Time, to time.Time, interval string) (*LoadCurve, error) {
+    if !isInterval(interval) {
+        return nil, ErrInterval
+    }
+    plant, err := s.DB.GetPlantById(id)
+    if err != nil {
+        return nil, err
+    }
+    location := plantLocation(plant)
+
+    buckets := localBuckets(from, to, location, interval)
+    curve := LoadCurve{
+        PlantID: id,
+        Timezone: location.String(),
+        Interval: interval,
+        Buckets: make([]LoadBucket, 0, len(buckets)),
+    }
+    if len(buckets) == 0 {
+        return &curve, nil
+    }
+
+    measurements, err := s.DB.GetMeasurementsByPlantId(id, buckets[0][0], buckets[len(buckets)-1][1])
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    measurements = plantMeterMeasurements(measurements)
+
+    for _, bucket := range buckets {
+        res := LoadBucket{StartsAt: bucket[0], EndsAt: bucket[1]}
+        for _, m := range measurements {
+            if !m.Timestamp.Before(bucket[0]) && m.Timestamp.Before(bucket[1]) {
+                res.Readings++
+            }
+        }
+        if avg, ok := averagePower(measurements, bucket[0], bucket[1]); ok {
+            // a local day lasts 23 or 25 hours when clocks change
+            energy := avg * bucket[1].Sub(bucket[0]).Hours()
+            res.AveragePower = &avg
+            res.Energy = &energy
+        }
+        curve.Buckets = append(curve.Buckets, res)
+    }
+    return &curve, nil
+}
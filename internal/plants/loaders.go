@@ -0,0 +1,240 @@
+package plants
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// idSet is the ids of the resources of a kind seen during a request.
+type idSet struct {
+    mu  sync.Mutex
+    ids map[uint]bool
+}
+
+func (s *idSet) add(ids ...uint) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for _, id := range ids {
+        s.ids[id] = true
+    }
+}
+
+// missing returns the ids of the set not in loaded, in order.
+func (s *idSet) missing(loaded func(id uint) bool) []uint {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    ids := []uint{}
+    for id := range s.ids {
+        if !loaded(id) {
+            ids = append(ids, id)
+        }
+    }
+    sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+    return ids
+}
+
+// list returns the ids of the set, in order.
+func (s *idSet) list() []uint {
+    return s.missing(func(uint) bool { return false })
+}
+
+// loader reads a relationship of the resources of a set. The first read it
+// cannot answer fetches the relationship of every id of the set it has not
+// fetched yet, in a single batch.
+type loader[V any] struct {
+    mu     sync.Mutex
+    ids    *idSet
+    fetch  func(ids []uint) (map[uint]V, error)
+    loaded map[uint]V
+}
+
+func newLoader[V any](ids *idSet, fetch func(ids []uint) (map[uint]V, error)) *loader[V] {
+    return &loader[V]{ids: ids, fetch: fetch, loaded: map[uint]V{}}
+}
+
+func (l *loader[V]) load(id uint) (V, error) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    if value, ok := l.loaded[id]; ok {
+        return value, nil
+    }
+    l.ids.add(id)
+    batch := l.ids.missing(func(id uint) bool {
+        _, ok := l.loaded[id]
+        return ok
+    })
+    values, err := l.fetch(batch)
+    if err != nil {
+        var none V
+        return none, err
+    }
+    for _, id := range batch {
+        l.loaded[id] = values[id]
+    }
+    return l.loaded[id], nil
+}
+
+// Loaders read the relationships between energy managers, plants and
+// assets for a single request, like a GraphQL query, in as few queries as
+// possible. The energy managers and plants of a page are added as soon as
+// it is read: the plants of the first energy manager are then read along
+// with those of all the others, and the assets of their plants likewise.
+// Assignments are those valid when the loaders are made.
+type Loaders struct {
+    s  *Service
+    at time.Time
+
+    mu          sync.Mutex
+    ems         *idSet
+    plants      *idSet
+    emPlants    map[string]*loader[[]models.Plant]
+    plantEms    map[string]*loader[[]models.EnergyManager]
+    plantAssets *loader[[]models.Asset]
+}
+
+func (s *Service) NewLoaders() *Loaders {
+    l := &Loaders{
+        s: s,
+        at: s.now(),
+        ems: &idSet{ids: map[uint]bool{}},
+        plants: &idSet{ids: map[uint]bool{}},
+        emPlants: map[string]*loader[[]models.Plant]{},
+        plantEms: map[string]*loader[[]models.EnergyManager]{},
+    }
+    l.plantAssets = newLoader(l.plants, l.fetchPlantAssets)
+    return l
+}
+
+// AddEnergyManagers registers energy managers whose relationships are about
+// to be read.
+func (l *Loaders) AddEnergyManagers(ems []models.EnergyManager) {
+    for _, em := range ems {
+        l.ems.add(em.ID)
+    }
+}
+
+// AddPlants registers plants whose relationships are about to be read.
+func (l *Loaders) AddPlants(plants []models.Plant) {
+    for _, plant := range plants {
+        l.plants.add(plant.ID)
+    }
+}
+
+// EnergyManagerPlants returns the plants an energy manager is assigned to
+// with a role, or with any role when it is empty.
+func (l *Loaders) EnergyManagerPlants(id uint, role string) ([]models.Plant, error) {
+    if role != "" && !isAssignmentRole(role) {
+        return nil, ErrAssignmentRole
+    }
+    l.mu.Lock()
+    emPlants, ok := l.emPlants[role]
+    if !ok {
+        emPlants = newLoader(l.ems, func(ids []uint) (map[uint][]models.Plant, error) {
+            return l.fetchEnergyManagerPlants(ids, role)
+        })
+        l.emPlants[role] = emPlants
+    }
+    l.mu.Unlock()
+    return emPlants.load(id)
+}
+
+// PlantEnergyManagers returns the energy managers assigned to a plant with a
+// role, or with any role when it is empty.
+func (l *Loaders) PlantEnergyManagers(id uint, role string) ([]models.EnergyManager, error) {
+    if role != "" && !isAssignmentRole(role) {
+        return nil, ErrAssignmentRole
+    }
+    l.mu.Lock()
+    plantEms, ok := l.plantEms[role]
+    if !ok {
+        plantEms = newLoader(l.plants, func(ids []uint) (map[uint][]models.EnergyManager, error) {
+            return l.fetchPlantEnergyManagers(ids, role)
+        })
+        l.plantEms[role] = plantEms
+    }
+    l.mu.Unlock()
+    return plantEms.load(id)
+}
+
+func (l *Loaders) PlantAssets(id uint) ([]models.Asset, error) {
+    return l.plantAssets.load(id)
+}
+
+func (l *Loaders) fetchEnergyManagerPlants(ids []uint, role string) (map[uint][]models.Plant, error) {
+    assignments, err := l.s.DB.GetCurrentAssignmentsByEnergyManagerIds(ids, role, l.at)
+    if err == ErrEmptyResult {
+        return map[uint][]models.Plant{}, nil
+    } else if err != nil {
+        return nil, err
+    }
+    plant_ids := &idSet{ids: map[uint]bool{}}
+    for _, assignment := range assignments {
+        plant_ids.add(assignment.PlantID)
+    }
+    plants, err := l.s.DB.GetPlantsByIds(plant_ids.list())
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+
+    // an energy manager with several roles on a plant gets it once
+    res := map[uint][]models.Plant{}
+    seen := map[[2]uint]bool{}
+    for _, plant := range plants {
+        for _, assignment := range assignments {
+            key := [2]uint{assignment.EnergyManagerID, plant.ID}
+            if assignment.PlantID != plant.ID || seen[key] {
+                continue
+            }
+            seen[key] = true
+            res[assignment.EnergyManagerID] = append(res[assignment.EnergyManagerID], plant)
+        }
+    }
+    return res, nil
+}
+
+func (l *Loaders) fetchPlantEnergyManagers(ids []uint, role string) (map[uint][]models.EnergyManager, error) {
+    assignments, err := l.s.DB.GetCurrentAssignmentsByPlantIds(ids, role, l.at)
+    if err == ErrEmptyResult {
+        return map[uint][]models.EnergyManager{}, nil
+    } else if err != nil {
+        return nil, err
+    }
+    em_ids := &idSet{ids: map[uint]bool{}}
+    for _, assignment := range assignments {
+        em_ids.add(assignment.EnergyManagerID)
+    }
+    ems, err := l.s.DB.GetEnergyManagersByIds(em_ids.list())
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+
+    // a plant with several assignments of an energy manager lists it once
+    res := map[uint][]models.EnergyManager{}
+    seen := map[[2]uint]bool{}
+    for _, em := range ems {
+        for _, assignment := range assignments {
+            key := [2]uint{assignment.PlantID, em.ID}
+            if assignment.EnergyManagerID != em.ID || seen[key] {
+                continue
+            }
+            seen[key] = true
+            res[assignment.PlantID] = append(res[assignment.PlantID], em)
+        }
+    }
+    return res, nil
+}
+
+func (l *Loaders) fetchPlantAssets(ids []uint) (map[uint][]models.Asset, error) {
+    assets, err := l.s.DB.GetAssetsByPlantIds(ids)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    res := map[uint][]models.Asset{}
+    for _, asset := range assets {
+        res[asset.PlantID] = append(res[asset.PlantID], asset)
+    }
+    return res, nil
+}
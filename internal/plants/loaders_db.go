@@ -0,0 +1,74 @@
+package plants
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+func (db *PlantsDB) GetEnergyManagersByIds(ids []uint) ([]models.EnergyManager, error) {
+    var ems []models.EnergyManager
+    result := db.gorm.Where("id IN ?", ids).Order("id").Find(&ems)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ems, ErrEmptyResult
+    }
+    return ems, nil
+}
+
+func (db *PlantsDB) GetPlantsByIds(ids []uint) ([]models.Plant, error) {
+    var plants []models.Plant
+    result := selectPlants(db.gorm).Where("id IN ?", ids).Order("id").Find(&plants)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return plants, ErrEmptyResult
+    }
+    return plants, nil
+}
+
+func (db *PlantsDB) GetAssetsByPlantIds(ids []uint) ([]models.Asset, error) {
+    var assets []models.Asset
+    result := db.gorm.Where("plant_id IN ?", ids).Order("id").Find(&assets)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return assets, ErrEmptyResult
+    }
+    return assets, nil
+}
+
+// GetCurrentAssignmentsByEnergyManagerIds returns the assignments of some
+// energy managers valid at `at`. An empty role matches every role.
+func (db *PlantsDB) GetCurrentAssignmentsByEnergyManagerIds(ids []uint, role string, at time.Time) ([]models.PlantAssignment, error) {
+    return db.getCurrentAssignments("energy_manager_id IN ?", ids, role, at)
+}
+
+// GetCurrentAssignmentsByPlantIds returns the assignments of some plants
+// valid at `at`. An empty role matches every role.
+func (db *PlantsDB) GetCurrentAssignmentsByPlantIds(ids []uint, role string, at time.Time) ([]models.PlantAssignment, error) {
+    return db.getCurrentAssignments("plant_id IN ?", ids, role, at)
+}
+
+func (db *PlantsDB) getCurrentAssignments(condition string, ids []uint, role string, at time.Time) ([]models.PlantAssignment, error) {
+    var assignments []models.PlantAssignment
+    query := db.gorm.
+        Where(condition, ids).
+        Where("valid_from <= ?", at).
+        Where("valid_to IS NULL OR valid_to > ?", at)
+    if role != "" {
+        query = query.Where("role = ?", role)
+    }
+    result := query.Order("id").Find(&assignments)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return assignments, ErrEmptyResult
+    }
+    return assignments, nil
+}
@@ -0,0 +1,65 @@
+package plants
+
+import "github.com/jeandeducla/api-plant/internal/models"
+
+func (db *PlantsDB) GetMaintenanceWindowsByAssetId(asset_id uint) ([]models.MaintenanceWindow, error) {
+    var windows []models.MaintenanceWindow
+    result := db.gorm.Where("asset_id = ?", asset_id).Order("starts_at").Find(&windows)
+    if result.Error != nil {
+        return windows, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return windows, ErrEmptyResult
+    }
+    return windows, nil
+}
+
+func (db *PlantsDB) GetMaintenanceWindowsByPlantId(plant_id uint) ([]models.MaintenanceWindow, error) {
+    var windows []models.MaintenanceWindow
+    result := db.gorm.
+        Joins("JOIN assets ON assets.id = maintenance_windows.asset_id").
+        Where("assets.plant_id = ?", plant_id).
+        Order("maintenance_windows.starts_at").
+        Find(&windows)
+    if result.Error != nil {
+        return windows, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return windows, ErrEmptyResult
+    }
+    return windows, nil
+}
+
+func (db *PlantsDB) CreateMaintenanceWindow(window *models.MaintenanceWindow) error {
+    result := db.gorm.Create(window)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetMaintenanceWindowByAssetId(asset_id uint, window_id uint) (*models.MaintenanceWindow, error) {
+    var window models.MaintenanceWindow
+    result := db.gorm.Where("asset_id = ?", asset_id).Find(&window, window_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &window, nil
+}
+
+func (db *PlantsDB) DeleteMaintenanceWindowById(window_id uint) error {
+    result := db.gorm.Delete(&models.MaintenanceWindow{}, window_id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
@@ -0,0 +1,309 @@
+package plants
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+const (
+    AssetAvailable   = "available"
+    AssetUnavailable = "unavailable"
+)
+
+const (
+    UnavailableOutOfService = "out_of_service"
+    UnavailableMaintenance  = "maintenance"
+)
+
+var (
+    ErrAssetAvailability = errors.New("Asset Availability must be one of 'available' or 'unavailable'")
+    ErrAssetUnavailable = errors.New("Asset is not available")
+    ErrMaintenanceWindow = errors.New("Maintenance window must end after it starts")
+    ErrMaintenanceRRule = errors.New("Maintenance RRule is not a valid recurrence rule")
+    ErrMaintenanceOccurrences = errors.New("Maintenance schedule has too many occurrences, ask for a shorter period")
+)
+
+// maxMaintenanceOccurrences is how many occurrences a maintenance schedule
+// lists at most, and maxMaintenanceExpansion how many occurrences of a
+// recurrence are expanded at most to find those of a period.
+const (
+    maxMaintenanceOccurrences = 1000
+    maxMaintenanceExpansion   = 100000
+)
+
+// assetAvailabilityOrDefault validates an availability input, empty meaning
+// available.
+func assetAvailabilityOrDefault(availability string) (string, error) {
+    if availability == "" {
+        return AssetAvailable, nil
+    }
+    if availability != AssetAvailable && availability != AssetUnavailable {
+        return "", ErrAssetAvailability
+    }
+    return availability, nil
+}
+
+// maintenanceRRule expands the recurrence of a window in the local time of
+// its plant, so that a daily window at 08:00 stays at 08:00 when clocks
+// change.
+func maintenanceRRule(window models.MaintenanceWindow, location *time.Location) (*rrule.RRule, error) {
+    option, err := rrule.StrToROption(strings.TrimPrefix(window.RRule, "RRULE:"))
+    if err != nil {
+        return nil, err
+    }
+    option.Dtstart = window.StartsAt.In(location)
+    return rrule.NewRRule(*option)
+}
+
+// MaintenanceOccurrence is one occurrence of a maintenance window.
+type MaintenanceOccurrence struct {
+    MaintenanceID uint      `json:"maintenance_id"`
+    StartsAt      time.Time `json:"starts_at"`
+    EndsAt        time.Time `json:"ends_at"`
+}
+
+// maintenanceOccurrences returns the occurrences of a maintenance window
+// overlapping [from, to). It fails with ErrMaintenanceOccurrences when there
+// are more than maxMaintenanceOccurrences of them.
+func maintenanceOccurrences(window models.MaintenanceWindow, location *time.Location, from time.Time, to time.Time) ([]MaintenanceOccurrence, error) {
+    res := []MaintenanceOccurrence{}
+    err := eachMaintenanceOccurrence(window, location, from, to, func(occurrence MaintenanceOccurrence) bool {
+        res = append(res, occurrence)
+        return len(res) <= maxMaintenanceOccurrences
+    })
+    if err != nil {
+        return nil, err
+    }
+    if len(res) > maxMaintenanceOccurrences {
+        return nil, ErrMaintenanceOccurrences
+    }
+    return res, nil
+}
+
+// eachMaintenanceOccurrence calls fn with the occurrences of a maintenance
+// window overlapping [from, to), in order, until it returns false. The
+// recurrence is expanded from the start of the window: it fails with
+// ErrMaintenanceOccurrences past maxMaintenanceExpansion occurrences, rather
+// than expanding a rule like FREQ=SECONDLY for years.
+func eachMaintenanceOccurrence(window models.MaintenanceWindow, location *time.Location, from time.Time, to time.Time, fn func(MaintenanceOccurrence) bool) error {
+    if window.RRule == "" {
+        if window.StartsAt.Before(to) && window.EndsAt.After(from) {
+            fn(MaintenanceOccurrence{
+                MaintenanceID: window.ID,
+                StartsAt: window.StartsAt.In(location),
+                EndsAt: window.EndsAt.In(location),
+            })
+        }
+        return nil
+    }
+
+    rule, err := maintenanceRRule(window, location)
+    if err != nil {
+        return nil
+    }
+    duration := window.EndsAt.Sub(window.StartsAt)
+    next := rule.Iterator()
+    for expanded := 1; ; expanded++ {
+        occurrence, ok := next()
+        if !ok || !occurrence.Before(to) {
+            return nil
+        }
+        if expanded > maxMaintenanceExpansion {
+            return ErrMaintenanceOccurrences
+        }
+        if !occurrence.Add(duration).After(from) {
+            continue
+        }
+        if !fn(MaintenanceOccurrence{MaintenanceID: window.ID, StartsAt: occurrence, EndsAt: occurrence.Add(duration)}) {
+            return nil
+        }
+    }
+}
+
+// maintenanceOverlaps tells if any occurrence of a maintenance window
+// overlaps [from, to). A recurrence too long to expand is taken as
+// overlapping, so that the asset is kept out of use rather than dispatched
+// during a maintenance.
+func maintenanceOverlaps(window models.MaintenanceWindow, location *time.Location, from time.Time, to time.Time) bool {
+    overlaps := false
+    err := eachMaintenanceOccurrence(window, location, from, to, func(MaintenanceOccurrence) bool {
+        overlaps = true
+        return false
+    })
+    return overlaps || err != nil
+}
+
+// assetAvailability tells if an asset can be used over [from, to) and why
+// not when it cannot. windows may hold the maintenance windows of other
+// assets, they are ignored. location is the local time of the plant.
+func assetAvailability(asset models.Asset, windows []models.MaintenanceWindow, location *time.Location, from time.Time, to time.Time) (bool, string) {
+    if asset.Availability == AssetUnavailable {
+        return false, UnavailableOutOfService
+    }
+    for _, window := range windows {
+        if window.AssetID == asset.ID && maintenanceOverlaps(window, location, from, to) {
+            return false, UnavailableMaintenance
+        }
+    }
+    return true, ""
+}
+
+// availablePlantAssets returns the assets of a plant that can be used over
+// [from, to).
+func (s *Service) availablePlantAssets(id uint, from time.Time, to time.Time) ([]models.Asset, error) {
+    plant, err := s.DB.GetPlantById(id)
+    if err != nil {
+        return nil, err
+    }
+    assets, err := s.DB.GetAssetsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    windows, err := s.DB.GetMaintenanceWindowsByPlantId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+
+    available := []models.Asset{}
+    for _, asset := range assets {
+        if ok, _ := assetAvailability(asset, windows, plantLocation(plant), from, to); ok {
+            available = append(available, asset)
+        }
+    }
+    return available, nil
+}
+
+// isAssetAvailableNow is used before sending a command to an asset.
+func (s *Service) isAssetAvailableNow(asset *models.Asset) (bool, error) {
+    plant, err := s.DB.GetPlantById(asset.PlantID)
+    if err != nil {
+        return false, err
+    }
+    windows, err := s.DB.GetMaintenanceWindowsByAssetId(asset.ID)
+    if err != nil && err != ErrEmptyResult {
+        return false, err
+    }
+    now := s.now()
+    ok, _ := assetAvailability(*asset, windows, plantLocation(plant), now, now.Add(time.Second))
+    return ok, nil
+}
+
+type AssetAvailability struct {
+    AssetID   uint      `json:"asset_id"`
+    From      time.Time `json:"from"`
+    To        time.Time `json:"to"`
+    Available bool      `json:"available"`
+    Reason    string    `json:"reason,omitempty"`
+}
+
+func (s *Service) GetAssetAvailability(plant_id uint, asset_id uint, from time.Time, to time.Time) (*AssetAvailability, error) {
+    asset, err := s.GetPlantAsset(plant_id, asset_id)
+    if err != nil {
+        return nil, err
+    }
+    windows, err := s.DB.GetMaintenanceWindowsByAssetId(asset_id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    plant, err := s.DB.GetPlantById(plant_id)
+    if err != nil {
+        return nil, err
+    }
+    available, reason := assetAvailability(*asset, windows, plantLocation(plant), from, to)
+    return &AssetAvailability{
+        AssetID: asset_id,
+        From: from,
+        To: to,
+        Available: available,
+        Reason: reason,
+    }, nil
+}
+
+func (s *Service) GetAssetMaintenanceWindows(plant_id uint, asset_id uint) ([]models.MaintenanceWindow, error) {
+    if _, err := s.GetPlantAsset(plant_id, asset_id); err != nil {
+        return nil, err
+    }
+    windows, err := s.DB.GetMaintenanceWindowsByAssetId(asset_id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return windows, nil
+}
+
+// GetAssetMaintenanceSchedule lists the occurrences of the maintenance
+// windows of an asset overlapping [from, to), in the local time of its
+// plant.
+func (s *Service) GetAssetMaintenanceSchedule(plant_id uint, asset_id uint, from time.Time, to time.Time) ([]MaintenanceOccurrence, error) {
+    windows, err := s.GetAssetMaintenanceWindows(plant_id, asset_id)
+    if err != nil {
+        return nil, err
+    }
+    plant, err := s.DB.GetPlantById(plant_id)
+    if err != nil {
+        return nil, err
+    }
+    location := plantLocation(plant)
+
+    schedule := []MaintenanceOccurrence{}
+    for _, window := range windows {
+        occurrences, err := maintenanceOccurrences(window, location, from, to)
+        if err != nil {
+            return nil, err
+        }
+        schedule = append(schedule, occurrences...)
+        if len(schedule) > maxMaintenanceOccurrences {
+            return nil, ErrMaintenanceOccurrences
+        }
+    }
+    sort.SliceStable(schedule, func(i, j int) bool {
+        return schedule[i].StartsAt.Before(schedule[j].StartsAt)
+    })
+    return schedule, nil
+}
+
+type CreateMaintenanceWindowInput struct {
+    StartsAt time.Time `json:"starts_at" binding:"required"`
+    EndsAt   time.Time `json:"ends_at"   binding:"required"`
+    RRule    string    `json:"rrule"`
+    Reason   string    `json:"reason"`
+}
+
+func (s *Service) CreateAssetMaintenanceWindow(plant_id uint, asset_id uint, input CreateMaintenanceWindowInput) error {
+    if !input.EndsAt.After(input.StartsAt) {
+        return ErrMaintenanceWindow
+    }
+
+    window := models.MaintenanceWindow{
+        AssetID: asset_id,
+        StartsAt: input.StartsAt,
+        EndsAt: input.EndsAt,
+        RRule: input.RRule,
+        Reason: input.Reason,
+    }
+    if window.RRule != "" {
+        if _, err := maintenanceRRule(window, time.UTC); err != nil {
+            return ErrMaintenanceRRule
+        }
+    }
+
+    if _, err := s.GetPlantAsset(plant_id, asset_id); err != nil {
+        return err
+    }
+    return s.DB.CreateMaintenanceWindow(&window)
+}
+
+func (s *Service) DeleteAssetMaintenanceWindow(plant_id uint, asset_id uint, window_id uint) error {
+    if _, err := s.GetPlantAsset(plant_id, asset_id); err != nil {
+        return err
+    }
+    if _, err := s.DB.GetMaintenanceWindowByAssetId(asset_id, window_id); err != nil {
+        return err
+    }
+    return s.DB.DeleteMaintenanceWindowById(window_id)
+}
@@ -0,0 +1,33 @@
+package plants
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+func (db *PlantsDB) CreateMeasurements(measurements []models.Measurement) error {
+    result := db.gorm.Create(&measurements)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetMeasurementsByPlantId(id uint, from time.Time, to time.Time) ([]models.Measurement, error) {
+    var measurements []models.Measurement
+    result := db.gorm.
+        Where("plant_id = ? AND timestamp >= ? AND timestamp < ?", id, from, to).
+        Order("timestamp").
+        Find(&measurements)
+    if result.Error != nil {
+        return measurements, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return measurements, ErrEmptyResult
+    }
+    return measurements, nil
+}
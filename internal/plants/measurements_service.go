@@ -0,0 +1,84 @@
+package plants
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+var (
+    ErrMeasurementAsset = errors.New("Measurement asset does not belong to the plant")
+)
+
+// averagePower returns the mean power of the readings taken in [from, to).
+// ok is false when there is no reading in the window.
+func averagePower(measurements []models.Measurement, from time.Time, to time.Time) (avg float64, ok bool) {
+    var sum float64
+    var count int
+    for _, m := range measurements {
+        if m.Timestamp.Before(from) || !m.Timestamp.Before(to) {
+            continue
+        }
+        sum += m.Power
+        count++
+    }
+    if count == 0 {
+        return 0, false
+    }
+    return sum / float64(count), true
+}
+
+// plantMeterMeasurements keeps the readings of the plant's main meter.
+func plantMeterMeasurements(measurements []models.Measurement) []models.Measurement {
+    res := []models.Measurement{}
+    for _, m := range measurements {
+        if m.AssetID == nil {
+            res = append(res, m)
+        }
+    }
+    return res
+}
+
+type MeasurementInput struct {
+    AssetID   *uint     `json:"asset_id"`
+    Timestamp time.Time `json:"timestamp" binding:"required"`
+    Power     float64   `json:"power"`
+}
+
+type CreateMeasurementsInput struct {
+    Measurements []MeasurementInput `json:"measurements" binding:"required,min=1,dive"`
+}
+
+func (s *Service) CreateMeasurements(id uint, input CreateMeasurementsInput) error {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return err
+    }
+
+    measurements := make([]models.Measurement, 0, len(input.Measurements))
+    for _, m := range input.Measurements {
+        if m.AssetID != nil {
+            if _, err := s.DB.GetAssetByPlantId(id, *m.AssetID); err != nil {
+                return ErrMeasurementAsset
+            }
+        }
+        measurements = append(measurements, models.Measurement{
+            PlantID: id,
+            AssetID: m.AssetID,
+            Timestamp: m.Timestamp,
+            Power: m.Power,
+        })
+    }
+    return s.DB.CreateMeasurements(measurements)
+}
+
+func (s *Service) GetPlantMeasurements(id uint, from time.Time, to time.Time) ([]models.Measurement, error) {
+    if _, err := s.DB.GetPlantById(id); err != nil {
+        return nil, err
+    }
+    measurements, err := s.DB.GetMeasurementsByPlantId(id, from, to)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return measurements, nil
+}
@@ -0,0 +1,61 @@
+package plants
+
+import (
+	"github.com/jeandeducla/api-plant/internal/models"
+	"gorm.io/gorm"
+)
+
+func (db *PlantsDB) GetMovesByAssetId(asset_id uint) ([]models.AssetMove, error) {
+    var moves []models.AssetMove
+    result := db.gorm.Where("asset_id = ?", asset_id).Order("created_at, id").Find(&moves)
+    if result.Error != nil {
+        return moves, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return moves, ErrEmptyResult
+    }
+    return moves, nil
+}
+
+// MoveAsset saves asset, whose plant, group and agent have changed, records
+// the move and cancels the commands still on their way to the asset, all at
+// once. It returns ErrEmptyResult if the asset is not on move.FromPlantID
+// anymore, which happens when another request moved it first.
+func (db *PlantsDB) MoveAsset(asset *models.Asset, move *models.AssetMove) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        result := tx.Model(asset).
+            Where("plant_id = ?", move.FromPlantID).
+            Select("plant_id", "group_id", "agent_id").
+            Updates(asset)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+
+        var commands []models.Command
+        err := tx.Where("asset_id = ? AND status IN ?", asset.ID, []string{CommandQueued, CommandDelivered}).
+            Find(&commands).Error
+        if err != nil {
+            return err
+        }
+        for _, command := range commands {
+            from := command.Status
+            err := tx.Model(&command).Update("status", CommandCancelled).Error
+            if err != nil {
+                return err
+            }
+            err = tx.Create(&models.CommandTransition{
+                CommandID: command.ID,
+                From: from,
+                To: CommandCancelled,
+                Reason: "asset moved to another plant",
+            }).Error
+            if err != nil {
+                return err
+            }
+        }
+        return tx.Create(move).Error
+    })
+}
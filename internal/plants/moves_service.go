@@ -0,0 +1,94 @@
+package plants
+
+import (
+	"errors"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+var (
+    ErrAssetMoveDestination = errors.New("Asset can only be moved to another existing plant")
+)
+
+type MoveAssetInput struct {
+    PlantID uint   `json:"plant_id" binding:"required"`
+    GroupID *uint  `json:"group_id"`
+    Reason  string `json:"reason"`
+}
+
+// MovePlantAsset relocates an asset to another plant, and possibly one of its
+// groups, keeping its ID so that its commands and maintenance history follow
+// it. Measurements taken before the move stay with the plant they were read
+// on, still tagged with the asset. The destination must have room for the
+// asset the same way it would for a new one; it is locked while its power
+// budget is checked, so that two assets moved to it at once cannot both take
+// the last of it. The asset leaves its agent behind, agents being installed
+// on a plant, and the commands not acknowledged yet are cancelled. The
+// asset.moved event is logged for both plants.
+func (s *Service) MovePlantAsset(plant_id uint, asset_id uint, input MoveAssetInput) error {
+    asset, err := s.GetPlantAsset(plant_id, asset_id)
+    if err != nil {
+        return err
+    }
+    if input.PlantID == plant_id {
+        return ErrAssetMoveDestination
+    }
+
+    move := models.AssetMove{
+        AssetID: asset.ID,
+        FromPlantID: plant_id,
+        ToPlantID: input.PlantID,
+        FromGroupID: asset.GroupID,
+        ToGroupID: input.GroupID,
+        Reason: input.Reason,
+    }
+    asset.PlantID = input.PlantID
+    asset.GroupID = input.GroupID
+    asset.AgentID = nil
+    return s.transaction(func(tx DB) error {
+        err := s.checkPlantPower(tx, input.PlantID, 0, input.GroupID, asset.MaxPower)
+        if errors.Is(err, ErrEmptyResult) {
+            return ErrAssetMoveDestination
+        } else if err != nil {
+            return err
+        }
+
+        if err := tx.MoveAsset(asset, &move); err != nil {
+            return err
+        }
+        // both plants see the asset go, from one to the other
+        moved := assetMoved{Asset: asset, Move: &move, FromPlantID: plant_id}
+        if err := s.emit(tx, EventAssetMoved, asset.ID, &plant_id, nil, moved); err != nil {
+            return err
+        }
+        return s.emit(tx, EventAssetMoved, asset.ID, &asset.PlantID, nil, moved)
+    })
+}
+
+// assetMoved is the data of the asset.moved event.
+type assetMoved struct {
+    Asset       *models.Asset     `json:"asset"`
+    Move        *models.AssetMove `json:"move"`
+    FromPlantID uint              `json:"from_plant_id"`
+}
+
+func (m assetMoved) render(contract func(interface{}) interface{}) interface{} {
+    return struct {
+        Asset       interface{} `json:"asset"`
+        Move        interface{} `json:"move"`
+        FromPlantID uint        `json:"from_plant_id"`
+    }{contract(m.Asset), contract(m.Move), m.FromPlantID}
+}
+
+// GetPlantAssetMoves returns the plants an asset went through, oldest move
+// first.
+func (s *Service) GetPlantAssetMoves(plant_id uint, asset_id uint) ([]models.AssetMove, error) {
+    if _, err := s.GetPlantAsset(plant_id, asset_id); err != nil {
+        return nil, err
+    }
+    moves, err := s.DB.GetMovesByAssetId(asset_id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return moves, nil
+}
@@ -0,0 +1,55 @@
+package plants
+
+import (
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"gorm.io/gorm"
+)
+
+func (db *PlantsDB) GetNotificationPreferencesByEnergyManagerId(id uint) ([]models.NotificationPreference, error) {
+    var preferences []models.NotificationPreference
+    result := db.gorm.Where("energy_manager_id = ?", id).Order("id").Find(&preferences)
+    if result.Error != nil {
+        return preferences, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return preferences, ErrEmptyResult
+    }
+    return preferences, nil
+}
+
+// ReplaceNotificationPreferences swaps all the preferences of an energy
+// manager for the given ones.
+func (db *PlantsDB) ReplaceNotificationPreferences(id uint, preferences []models.NotificationPreference) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        err := tx.Where("energy_manager_id = ?", id).Delete(&models.NotificationPreference{}).Error
+        if err != nil {
+            return err
+        }
+        if len(preferences) == 0 {
+            return nil
+        }
+        return tx.Create(&preferences).Error
+    })
+}
+
+// GetEnergyManagersByPlantId returns the energy managers assigned to a plant
+// at a given time, whatever their role.
+func (db *PlantsDB) GetEnergyManagersByPlantId(id uint, at time.Time) ([]models.EnergyManager, error) {
+    var ems []models.EnergyManager
+    result := db.gorm.
+        Joins("JOIN plant_assignments ON plant_assignments.energy_manager_id = energy_managers.id").
+        Where("plant_assignments.plant_id = ?", id).
+        Where("plant_assignments.valid_from <= ?", at).
+        Where("plant_assignments.valid_to IS NULL OR plant_assignments.valid_to > ?", at).
+        Distinct("energy_managers.*").Order("energy_managers.id").
+        Find(&ems)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ems, ErrEmptyResult
+    }
+    return ems, nil
+}
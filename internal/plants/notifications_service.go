@@ -0,0 +1,207 @@
+package plants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/notify"
+)
+
+const (
+    NotificationEmail   = "email"
+    NotificationWebhook = "webhook"
+)
+
+// notificationTimeout bounds the delivery of a single notification.
+const notificationTimeout = 30 * time.Second
+
+var (
+    ErrEmEmail = errors.New("Energy manager Email is not a valid email address")
+    ErrEmPhone = errors.New("Energy manager Phone must be an E.164 phone number, like +33612345678")
+    ErrEmTimezone = errors.New("Energy manager Timezone must be an IANA timezone, like Europe/Paris")
+    ErrEmLanguage = errors.New("Energy manager Language must be a BCP 47 language tag, like fr or en-GB")
+    ErrNotificationChannel = errors.New("Notification Channel must be one of 'email' or 'webhook'")
+    ErrNotificationTarget = errors.New("Notification Target is missing or invalid for its channel")
+)
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// normalizeContactDetails validates the contact details of an energy manager
+// and puts them in their canonical form.
+func normalizeContactDetails(em *models.EnergyManager) error {
+    if em.Email != "" {
+        address, err := mail.ParseAddress(em.Email)
+        if err != nil || address.Address != em.Email {
+            return ErrEmEmail
+        }
+    }
+    if em.Phone != "" && !e164.MatchString(em.Phone) {
+        return ErrEmPhone
+    }
+
+    if em.Timezone == "" {
+        em.Timezone = "UTC"
+    }
+    if _, err := time.LoadLocation(em.Timezone); err != nil {
+        return ErrEmTimezone
+    }
+
+    if em.Language == "" {
+        em.Language = "en"
+    }
+    tag, err := language.Parse(em.Language)
+    if err != nil {
+        return ErrEmLanguage
+    }
+    em.Language = tag.String()
+    return nil
+}
+
+func (s *Service) GetEnergyManagerNotificationPreferences(id uint) ([]models.NotificationPreference, error) {
+    if _, err := s.DB.GetEnergyManagerById(id); err != nil {
+        return nil, err
+    }
+    preferences, err := s.DB.GetNotificationPreferencesByEnergyManagerId(id)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return preferences, nil
+}
+
+type NotificationPreferenceInput struct {
+    Channel string `json:"channel" binding:"required"`
+    Enabled bool   `json:"enabled"`
+    Target  string `json:"target"`
+}
+
+type UpdateNotificationPreferencesInput struct {
+    Preferences []NotificationPreferenceInput `json:"preferences" binding:"dive"`
+}
+
+func checkNotificationPreference(em *models.EnergyManager, input NotificationPreferenceInput) error {
+    switch input.Channel {
+    case NotificationEmail:
+        if input.Target == "" {
+            if input.Enabled && em.Email == "" {
+                return ErrNotificationTarget
+            }
+            return nil
+        }
+        address, err := mail.ParseAddress(input.Target)
+        if err != nil || address.Address != input.Target {
+            return ErrNotificationTarget
+        }
+    case NotificationWebhook:
+        target, err := url.Parse(input.Target)
+        if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+            return ErrNotificationTarget
+        }
+    default:
+        return ErrNotificationChannel
+    }
+    return nil
+}
+
+// UpdateEnergyManagerNotificationPreferences replaces the notification
+// preferences of an energy manager, one per channel.
+func (s *Service) UpdateEnergyManagerNotificationPreferences(id uint, input UpdateNotificationPreferencesInput) error {
+    em, err := s.DB.GetEnergyManagerById(id)
+    if err != nil {
+        return err
+    }
+
+    preferences := []models.NotificationPreference{}
+    channels := map[string]bool{}
+    for _, preference := range input.Preferences {
+        if err := checkNotificationPreference(em, preference); err != nil {
+            return err
+        }
+        if channels[preference.Channel] {
+            return ErrNotificationChannel
+        }
+        channels[preference.Channel] = true
+        preferences = append(preferences, models.NotificationPreference{
+            EnergyManagerID: id,
+            Channel: preference.Channel,
+            Enabled: preference.Enabled,
+            Target: preference.Target,
+        })
+    }
+    return s.DB.ReplaceNotificationPreferences(id, preferences)
+}
+
+// notifyPlantManagers sends a message to every energy manager currently
+// assigned to a plant, on each channel they enabled. The message is built
+// per energy manager so that it can be written in their timezone. Delivery
+// happens in the background: a failing channel is logged and does not fail
+// the operation that triggered it.
+func (s *Service) notifyPlantManagers(plant_id uint, message func(em models.EnergyManager) notify.Message) {
+    if s.Notifier == nil {
+        return
+    }
+    ems, err := s.DB.GetEnergyManagersByPlantId(plant_id, s.now())
+    if err != nil && err != ErrEmptyResult {
+        log.Printf("notifications of plant %d: %v", plant_id, err)
+        return
+    }
+
+    for _, em := range ems {
+        preferences, err := s.DB.GetNotificationPreferencesByEnergyManagerId(em.ID)
+        if err != nil && err != ErrEmptyResult {
+            log.Printf("notifications of energy manager %d: %v", em.ID, err)
+            continue
+        }
+        for _, preference := range preferences {
+            if !preference.Enabled {
+                continue
+            }
+            msg := message(em)
+            msg.To = preference.Target
+            if msg.To == "" && preference.Channel == NotificationEmail {
+                msg.To = em.Email
+            }
+            if msg.To == "" {
+                continue
+            }
+            go func(channel string, msg notify.Message) {
+                ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+                defer cancel()
+                if err := s.Notifier.Send(ctx, channel, msg); err != nil {
+                    log.Printf("notification to %s over %s: %v", msg.To, channel, err)
+                }
+            }(preference.Channel, msg)
+        }
+    }
+}
+
+// localTime formats t in the timezone of an energy manager.
+func localTime(em models.EnergyManager, t time.Time) string {
+    location, err := time.LoadLocation(em.Timezone)
+    if err != nil {
+        location = time.UTC
+    }
+    return t.In(location).Format("Mon 2 Jan 2006 15:04 MST")
+}
+
+func curtailmentMessage(plant *models.Plant, curtailment *models.CurtailmentEvent) func(em models.EnergyManager) notify.Message {
+    return func(em models.EnergyManager) notify.Message {
+        return notify.Message{
+            Subject: fmt.Sprintf("Curtailment %s at %s", curtailment.Status, plant.Name),
+            Body: fmt.Sprintf(
+                "Hello %s,\n\nThe curtailment of %s at %s from %s to %s is now %s.\n",
+                em.Name, curtailment.TargetPower, plant.Name,
+                localTime(em, curtailment.StartsAt), localTime(em, curtailment.EndsAt),
+                curtailment.Status,
+            ),
+        }
+    }
+}
@@ -0,0 +1,84 @@
+package plants
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+func (db *PlantsDB) CreateOutboxMessages(messages []models.OutboxMessage) error {
+    if len(messages) == 0 {
+        return nil
+    }
+    result := db.gorm.Omit("Event").Create(&messages)
+    if result.Error != nil {
+        return result.Error
+    }
+    return nil
+}
+
+// ClaimDueOutboxMessages returns at most limit pending messages due at `at`
+// with their event, oldest first, and leases them until `until` by pushing
+// their next attempt back to then. A message waits for the previous pending
+// messages of its key to the same sink, so that they are published in order.
+// The messages being claimed by another relay are skipped, and those it
+// leased are not due: being still pending, they hold back the next messages
+// of their key too. The messages of a relay gone before publishing them are
+// due again once their lease is over.
+func (db *PlantsDB) ClaimDueOutboxMessages(at time.Time, until time.Time, limit int) ([]models.OutboxMessage, error) {
+    var messages []models.OutboxMessage
+    err := db.gorm.Transaction(func(tx *gorm.DB) error {
+        result := tx.
+            Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+            Preload("Event").
+            Where("status = ? AND next_attempt_at <= ?", OutboxPending, at).
+            Where(`NOT EXISTS (
+                SELECT 1 FROM outbox_messages AS previous
+                WHERE previous.sink = outbox_messages.sink AND previous.key = outbox_messages.key
+                AND previous.status = ? AND previous.id < outbox_messages.id
+            )`, OutboxPending).
+            Order("id").
+            Limit(limit).
+            Find(&messages)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+        ids := make([]uint, len(messages))
+        for i := range messages {
+            ids[i] = messages[i].ID
+            messages[i].NextAttemptAt = until
+        }
+        return tx.Model(&models.OutboxMessage{}).Where("id IN ?", ids).Update("next_attempt_at", until).Error
+    })
+    return messages, err
+}
+
+// DeletePublishedOutboxMessagesBefore deletes the messages published before
+// `before`.
+func (db *PlantsDB) DeletePublishedOutboxMessagesBefore(before time.Time) error {
+    result := db.gorm.Where("status = ? AND published_at < ?", OutboxPublished, before).Delete(&models.OutboxMessage{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) UpdateOutboxMessage(message *models.OutboxMessage) error {
+    result := db.gorm.Model(message).Omit("Event").Select("*").Updates(message)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
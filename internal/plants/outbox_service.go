@@ -0,0 +1,221 @@
+package plants
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/dto"
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/outbox"
+	"github.com/jeandeducla/api-plant/internal/webhooks"
+)
+
+const (
+    OutboxPending   = "pending"
+    OutboxPublished = "published"
+)
+
+// SinkWebhooks is the sink of the webhooks: its messages become deliveries
+// to the webhooks subscribed to them.
+const SinkWebhooks = "webhooks"
+
+// outboxPollInterval is how often due retries are looked for when no change
+// wakes the relay up.
+const outboxPollInterval = 5 * time.Second
+
+const outboxBatchSize = 100
+
+// outboxLease is how long a relay holds the messages it claimed, longer than
+// it can take to publish a batch of them.
+const outboxLease = 5 * time.Minute
+
+// outboxRetention is how long the published messages are kept, for the
+// record, and outboxPurgeInterval how often the older ones are deleted.
+const (
+    outboxRetention     = 7 * 24 * time.Hour
+    outboxPurgeInterval = time.Hour
+)
+
+// outboxBackoff spaces out the attempts of a message. Since it holds back the
+// next messages of its key, it is attempted until it is published, at least
+// once a minute.
+var outboxBackoff = webhooks.Backoff{Base: time.Second, Max: time.Minute}
+
+var (
+    ErrOutboxSink = errors.New("No sink registered under this name")
+)
+
+// outboxKey is the aggregate an event is about, its messages are published in
+// the order of the log.
+func outboxKey(event *models.Event) string {
+    return fmt.Sprintf("%s:%d", event.EntityType, event.EntityID)
+}
+
+// sinkNames returns the sinks an event is written to the outbox of.
+func (s *Service) sinkNames() []string {
+    names := []string{}
+    if s.Webhooks != nil {
+        names = append(names, SinkWebhooks)
+    }
+    for name := range s.Sinks {
+        if name != SinkWebhooks {
+            names = append(names, name)
+        }
+    }
+    sort.Strings(names)
+    return names
+}
+
+// writeOutbox writes an event to the outbox of every sink, in the transaction
+// tx it is logged in.
+func (s *Service) writeOutbox(tx DB, event *models.Event) error {
+    messages := []models.OutboxMessage{}
+    for _, sink := range s.sinkNames() {
+        messages = append(messages, models.OutboxMessage{
+            EventID: event.ID,
+            Sink: sink,
+            Key: outboxKey(event),
+            Status: OutboxPending,
+            NextAttemptAt: event.CreatedAt,
+        })
+    }
+    return tx.CreateOutboxMessages(messages)
+}
+
+// wakeOutbox gets RelayOutbox to look for due messages right away.
+func (s *Service) wakeOutbox() {
+    select {
+    case s.outboxWakeup <- struct{}{}:
+    default:
+    }
+}
+
+// RelayOutbox publishes the messages of the outbox to their sink until ctx
+// is done: as soon as the change they come from is committed, then whenever
+// their retry is due. Every instance of the service runs a relay, they share
+// the messages without publishing any twice.
+func (s *Service) RelayOutbox(ctx context.Context) {
+    for {
+        s.relayOutbox(ctx)
+        select {
+        case <-ctx.Done():
+            return
+        case <-s.outboxWakeup:
+        case <-time.After(outboxPollInterval):
+        }
+    }
+}
+
+// relayOutbox attempts every message due now, each once. The messages held
+// back by a previous one of their key are attempted once it is published.
+// Each batch of messages is leased, then published to the sinks outside of
+// any transaction, and the outcome of the attempts written in a short one.
+// The messages of the webhooks become deliveries in that transaction.
+func (s *Service) relayOutbox(ctx context.Context) {
+    for ctx.Err() == nil {
+        now := s.now()
+        messages, err := s.DB.ClaimDueOutboxMessages(now, now.Add(outboxLease), outboxBatchSize)
+        if err != nil {
+            if err != ErrEmptyResult {
+                log.Printf("outbox: %v", err)
+            }
+            return
+        }
+        errs := make([]error, len(messages))
+        for i := range messages {
+            if !s.isWebhooksMessage(&messages[i]) {
+                errs[i] = s.publishOutboxMessage(ctx, &messages[i])
+            }
+        }
+        err = s.DB.Transaction(func(tx DB) error {
+            for i := range messages {
+                message := &messages[i]
+                if s.isWebhooksMessage(message) {
+                    if err := s.queueWebhooks(tx, &message.Event); err != nil {
+                        return fmt.Errorf("message %d: %w", message.ID, err)
+                    }
+                }
+                if err := s.recordOutboxAttempt(tx, message, errs[i]); err != nil {
+                    return fmt.Errorf("message %d: %w", message.ID, err)
+                }
+            }
+            return nil
+        })
+        if err != nil {
+            // attempted again once their lease is over
+            log.Printf("outbox: %v", err)
+            return
+        }
+        // the deliveries of the webhooks are committed now
+        s.wakeWebhooks()
+    }
+}
+
+// recordOutboxAttempt writes the outcome err of the attempt of a message, in
+// the transaction tx. A failed message is retried with an exponential
+// backoff.
+func (s *Service) recordOutboxAttempt(tx DB, message *models.OutboxMessage, err error) error {
+    now := s.now()
+    message.Attempts++
+    if err == nil {
+        message.Status = OutboxPublished
+        message.PublishedAt = &now
+        message.LastError = ""
+    } else {
+        message.NextAttemptAt = now.Add(outboxBackoff.Delay(message.Attempts))
+        message.LastError = err.Error()
+    }
+    return tx.UpdateOutboxMessage(message)
+}
+
+// isWebhooksMessage tells whether a message is queued as webhook deliveries
+// rather than published to a sink.
+func (s *Service) isWebhooksMessage(message *models.OutboxMessage) bool {
+    return message.Sink == SinkWebhooks && s.Webhooks != nil
+}
+
+// PurgeOutbox deletes the messages published more than outboxRetention ago
+// until ctx is done.
+func (s *Service) PurgeOutbox(ctx context.Context) {
+    for {
+        if err := s.purgeOutbox(); err != nil {
+            log.Printf("outbox: %v", err)
+        }
+        select {
+        case <-ctx.Done():
+            return
+        case <-time.After(outboxPurgeInterval):
+        }
+    }
+}
+
+func (s *Service) purgeOutbox() error {
+    err := s.DB.DeletePublishedOutboxMessagesBefore(s.now().Add(-outboxRetention))
+    if err == ErrEmptyResult {
+        return nil
+    }
+    return err
+}
+
+func (s *Service) publishOutboxMessage(ctx context.Context, message *models.OutboxMessage) error {
+    sink, ok := s.Sinks[message.Sink]
+    if !ok {
+        return fmt.Errorf("%w: %s", ErrOutboxSink, message.Sink)
+    }
+    payload, err := json.Marshal(dto.NewEventMessage(&message.Event))
+    if err != nil {
+        return err
+    }
+    return sink.Publish(ctx, outbox.Message{
+        ID: message.EventID,
+        Type: message.Event.Type,
+        Key: message.Key,
+        OccurredAt: message.Event.CreatedAt.UTC(),
+        Payload: payload,
+    })
+}
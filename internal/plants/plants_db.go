@@ -2,9 +2,12 @@ package plants
 
 import (
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/jeandeducla/api-plant/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -12,19 +15,93 @@ var (
 )
 
 type DB interface {
+    // Transaction runs fn with a DB whose changes are committed together
+    // when fn returns nil, and rolled back otherwise.
+    Transaction(fn func(tx DB) error) error
+
     GetAllEnergyManagers() ([]models.EnergyManager, error)
     CreateEnergyManager(em *models.EnergyManager) error
     GetEnergyManagerById(id uint) (*models.EnergyManager, error)
     DeleteEnergyManagerById(id uint) error
+    ReassignEnergyManager(from uint, to uint, at time.Time) error
     UpdateEnergyManager(em *models.EnergyManager) error
 
+    GetNotificationPreferencesByEnergyManagerId(id uint) ([]models.NotificationPreference, error)
+    ReplaceNotificationPreferences(id uint, preferences []models.NotificationPreference) error
+    GetEnergyManagersByPlantId(id uint, at time.Time) ([]models.EnergyManager, error)
+
+    CreateEvent(event *models.Event) error
+    GetLastEventId() (uint, error)
+    GetEventsAfter(after uint, filter EventFilter, at time.Time, limit int) ([]models.Event, error)
+
+    CreateOutboxMessages(messages []models.OutboxMessage) error
+    ClaimDueOutboxMessages(at time.Time, until time.Time, limit int) ([]models.OutboxMessage, error)
+    DeletePublishedOutboxMessagesBefore(before time.Time) error
+    UpdateOutboxMessage(message *models.OutboxMessage) error
+
+    CreateIdempotencyKey(key *models.IdempotencyKey) error
+    GetIdempotencyKey(key string) (*models.IdempotencyKey, error)
+    UpdateIdempotencyKey(key *models.IdempotencyKey) error
+    DeleteIdempotencyKey(id uint) error
+    DeleteIdempotencyKeysBefore(before time.Time) error
+
+    GetAllWebhookSubscriptions() ([]models.WebhookSubscription, error)
+    CreateWebhookSubscription(subscription *models.WebhookSubscription) error
+    GetWebhookSubscriptionById(id uint) (*models.WebhookSubscription, error)
+    DeleteWebhookSubscriptionById(id uint) error
+    UpdateWebhookSubscription(subscription *models.WebhookSubscription) error
+    CreateWebhookDeliveries(deliveries []models.WebhookDelivery) error
+    GetWebhookDeliveriesBySubscriptionId(id uint, status string) ([]models.WebhookDelivery, error)
+    GetWebhookDeliveryBySubscriptionId(subscription_id uint, delivery_id uint) (*models.WebhookDelivery, error)
+    ClaimDueWebhookDeliveries(at time.Time, until time.Time, limit int) ([]models.WebhookDelivery, error)
+    UpdateWebhookDelivery(delivery *models.WebhookDelivery) error
+
+    GetAllTariffs() ([]models.Tariff, error)
+    CreateTariff(tariff *models.Tariff) error
+    GetTariffById(id uint) (*models.Tariff, error)
+    DeleteTariffById(id uint) error
+    UpdateTariff(tariff *models.Tariff) error
+
+    GetStaticEmissionFactors() ([]models.EmissionFactor, error)
+    SetStaticEmissionFactor(factor *models.EmissionFactor) error
+    DeleteStaticEmissionFactor(zone string) error
+    GetEmissionFactors(zones []string, from time.Time, to time.Time) ([]models.EmissionFactor, error)
+    ReplaceHourlyEmissionFactors(zone string, from time.Time, to time.Time, factors []models.EmissionFactor) error
+
     GetAllPlants() ([]models.Plant, error)
     CreatePlant(plant *models.Plant) error
     GetPlantById(id uint) (*models.Plant, error)
+    LockPlantById(id uint) (*models.Plant, error)
     DeletePlantById(id uint) error
     UpdatePlant(plant *models.Plant) error
 
-    GetPlantsByEnergyManagerId(id uint) ([]models.Plant, error)
+    SearchPlants(query PlantQuery) ([]models.Plant, error)
+
+    GetPlantsByEnergyManagerId(id uint, role string, at time.Time) ([]models.Plant, error)
+    GetPlantsByEnergyManagerIdSince(id uint, at time.Time) ([]models.Plant, error)
+
+    GetEnergyManagersByIds(ids []uint) ([]models.EnergyManager, error)
+    GetPlantsByIds(ids []uint) ([]models.Plant, error)
+    GetAssetsByPlantIds(ids []uint) ([]models.Asset, error)
+    GetCurrentAssignmentsByEnergyManagerIds(ids []uint, role string, at time.Time) ([]models.PlantAssignment, error)
+    GetCurrentAssignmentsByPlantIds(ids []uint, role string, at time.Time) ([]models.PlantAssignment, error)
+
+    GetPlantByIdWithAssets(id uint, limit int) (*models.Plant, error)
+    GetCurrentPlantsByEnergyManagerId(id uint, at time.Time, limit int, assets_limit int) ([]models.Plant, error)
+    GetPrimaryEnergyManagerByPlantId(id uint, at time.Time) (*models.EnergyManager, error)
+
+    GetAssignmentsByPlantId(id uint) ([]models.PlantAssignment, error)
+    CreateAssignment(assignment *models.PlantAssignment) error
+    GetAssignmentByPlantId(plant_id uint, assignment_id uint) (*models.PlantAssignment, error)
+    DeleteAssignmentById(assignment_id uint) error
+    UpdateAssignment(assignment *models.PlantAssignment) error
+    ReplacePrimaryAssignment(plant_id uint, em_id uint, at time.Time) error
+
+    GetConnectionsByPlantId(id uint) ([]models.GridConnection, error)
+    CreateConnection(connection *models.GridConnection) error
+    GetConnectionByPlantId(plant_id uint, connection_id uint) (*models.GridConnection, error)
+    DeleteConnectionById(connection_id uint) error
+    UpdateConnection(connection *models.GridConnection) error
 
     GetAssetById(id uint) (*models.Asset, error)
     GetAssetsByPlantId(id uint) ([]models.Asset, error)
@@ -32,16 +109,67 @@ type DB interface {
     GetAssetByPlantId(plant_id uint, asset_id uint) (*models.Asset, error)
     DeleteAssetById(asset_id uint) error
     UpdateAsset(asset *models.Asset) error
+    GetMovesByAssetId(asset_id uint) ([]models.AssetMove, error)
+    MoveAsset(asset *models.Asset, move *models.AssetMove) error
+
+    GetCurtailmentsByPlantId(id uint) ([]models.CurtailmentEvent, error)
+    CreateCurtailment(curtailment *models.CurtailmentEvent) error
+    GetCurtailmentByPlantId(plant_id uint, curtailment_id uint) (*models.CurtailmentEvent, error)
+    DeleteCurtailmentById(curtailment_id uint) error
+    UpdateCurtailment(curtailment *models.CurtailmentEvent) error
+
+    CreateMeasurements(measurements []models.Measurement) error
+    GetMeasurementsByPlantId(id uint, from time.Time, to time.Time) ([]models.Measurement, error)
+
+    GetDispatchPlansByPlantId(id uint) ([]models.DispatchPlan, error)
+    CreateDispatchPlan(plan *models.DispatchPlan) error
+    GetDispatchPlanByPlantId(plant_id uint, plan_id uint) (*models.DispatchPlan, error)
+    LockDispatchPlanById(id uint) error
+
+    GetAllAgents() ([]models.Agent, error)
+    CreateAgent(agent *models.Agent) error
+    GetAgentById(id uint) (*models.Agent, error)
+    DeleteAgentById(id uint) error
+
+    CreateCommand(command *models.Command) error
+    GetCommandsByDispatchPlanId(plan_id uint, statuses []string) ([]models.Command, error)
+    GetCommandsByAgentId(agent_id uint, status string) ([]models.Command, error)
+    GetTimedOutCommands(at time.Time) ([]models.Command, error)
+    GetCommandByAgentId(agent_id uint, command_id uint) (*models.Command, error)
+    GetCommandsByAssetId(asset_id uint) ([]models.Command, error)
+    GetCommandByAssetId(asset_id uint, command_id uint) (*models.Command, error)
+    TransitionCommand(command *models.Command, from string, reason string) error
+
+    GetMaintenanceWindowsByAssetId(asset_id uint) ([]models.MaintenanceWindow, error)
+    GetMaintenanceWindowsByPlantId(plant_id uint) ([]models.MaintenanceWindow, error)
+    CreateMaintenanceWindow(window *models.MaintenanceWindow) error
+    GetMaintenanceWindowByAssetId(asset_id uint, window_id uint) (*models.MaintenanceWindow, error)
+    DeleteMaintenanceWindowById(window_id uint) error
+
+    GetGroupsByPlantId(id uint) ([]models.AssetGroup, error)
+    CreateGroup(group *models.AssetGroup) error
+    GetGroupByPlantId(plant_id uint, group_id uint) (*models.AssetGroup, error)
+    DeleteGroupById(group_id uint) error
+    UpdateGroup(group *models.AssetGroup) error
 }
 
 type PlantsDB struct  {
     gorm *gorm.DB
+
+    postgisOnce sync.Once
+    postgis     bool
 }
 
 func NewPlantsDB(db *gorm.DB) *PlantsDB {
     return &PlantsDB{gorm: db}
 }
 
+func (db *PlantsDB) Transaction(fn func(tx DB) error) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        return fn(&PlantsDB{gorm: tx})
+    })
+}
+
 func (db *PlantsDB) GetAllEnergyManagers() ([]models.EnergyManager, error) {
     var ems []models.EnergyManager
     if err := db.gorm.Find(&ems).Error; err != nil {
@@ -84,8 +212,63 @@ func (db *PlantsDB) DeleteEnergyManagerById(id uint) error {
     return nil
 }
 
+// ReassignEnergyManager hands the assignments of energy manager `from` that
+// are not over at `at` to energy manager `to`, from then on, and deletes
+// `from`. Assignments `to` already holds are not duplicated.
+func (db *PlantsDB) ReassignEnergyManager(from uint, to uint, at time.Time) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        var assignments []models.PlantAssignment
+        err := tx.Where("energy_manager_id = ?", from).
+            Where("valid_to IS NULL OR valid_to > ?", at).
+            Order("id").
+            Find(&assignments).Error
+        if err != nil {
+            return err
+        }
+        for _, assignment := range assignments {
+            valid_from := assignment.ValidFrom
+            if valid_from.Before(at) {
+                valid_from = at
+            }
+            query := tx.Model(&models.PlantAssignment{}).
+                Where("plant_id = ? AND energy_manager_id = ? AND role = ?", assignment.PlantID, to, assignment.Role).
+                Where("valid_to IS NULL OR valid_to > ?", valid_from)
+            if assignment.ValidTo != nil {
+                query = query.Where("valid_from < ?", *assignment.ValidTo)
+            }
+            var held int64
+            if err := query.Count(&held).Error; err != nil {
+                return err
+            }
+            if held > 0 {
+                continue
+            }
+            err := tx.Create(&models.PlantAssignment{
+                PlantID: assignment.PlantID,
+                EnergyManagerID: to,
+                Role: assignment.Role,
+                ValidFrom: valid_from,
+                ValidTo: assignment.ValidTo,
+            }).Error
+            if err != nil {
+                return err
+            }
+        }
+        // the assignments of `from` go along with it
+        result := tx.Delete(&models.EnergyManager{}, from)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+        return nil
+    })
+}
+
 func (db *PlantsDB) UpdateEnergyManager(em *models.EnergyManager) error {
-    result := db.gorm.Model(em).Updates(em)
+    // contact details can be removed
+    result := db.gorm.Model(em).Select("*").Updates(em)
     if result.Error != nil {
         return result.Error
     }
@@ -95,9 +278,26 @@ func (db *PlantsDB) UpdateEnergyManager(em *models.EnergyManager) error {
     return nil
 }
 
+// primaryEnergyManagerColumn selects the current primary energy manager of
+// each plant read into Plant.EnergyManagerID.
+const primaryEnergyManagerColumn = `(
+    SELECT plant_assignments.energy_manager_id FROM plant_assignments
+    WHERE plant_assignments.plant_id = plants.id
+    AND plant_assignments.role = 'primary'
+    AND plant_assignments.valid_from <= CURRENT_TIMESTAMP
+    AND (plant_assignments.valid_to IS NULL OR plant_assignments.valid_to > CURRENT_TIMESTAMP)
+    ORDER BY plant_assignments.valid_from DESC LIMIT 1
+) AS energy_manager_id`
+
+// selectPlants reads the plants of query along with their primary energy
+// manager.
+func selectPlants(query *gorm.DB) *gorm.DB {
+    return query.Select("plants.*", primaryEnergyManagerColumn)
+}
+
 func (db *PlantsDB) GetAllPlants() ([]models.Plant, error) {
     var ems []models.Plant
-    if err := db.gorm.Find(&ems).Error; err != nil {
+    if err := selectPlants(db.gorm).Find(&ems).Error; err != nil {
         return nil, err
     }
     return ems, nil
@@ -116,7 +316,21 @@ func (db *PlantsDB) CreatePlant(plant *models.Plant) error {
 
 func (db *PlantsDB) GetPlantById(id uint) (*models.Plant, error) {
     var plant models.Plant
-    result := db.gorm.Find(&plant, id)
+    result := selectPlants(db.gorm).Find(&plant, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &plant, nil
+}
+
+// LockPlantById reads a plant and locks it until the end of the
+// transaction.
+func (db *PlantsDB) LockPlantById(id uint) (*models.Plant, error) {
+    var plant models.Plant
+    result := db.gorm.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&plant, id)
     if result.Error != nil {
         return nil, result.Error
     }
@@ -138,7 +352,8 @@ func (db *PlantsDB) DeletePlantById(id uint) error {
 }
 
 func (db *PlantsDB) UpdatePlant(plant *models.Plant) error {
-    result := db.gorm.Model(plant).Updates(plant)
+    // the location of a plant can be removed
+    result := db.gorm.Model(plant).Select("*").Updates(plant)
     if result.Error != nil {
         return result.Error
     }
@@ -148,9 +363,40 @@ func (db *PlantsDB) UpdatePlant(plant *models.Plant) error {
     return nil
 }
 
-func (db *PlantsDB) GetPlantsByEnergyManagerId(id uint) ([]models.Plant, error) {
+// GetPlantsByEnergyManagerId returns the plants an energy manager is
+// assigned to at a given time, with the given role or with any role when
+// role is empty.
+func (db *PlantsDB) GetPlantsByEnergyManagerId(id uint, role string, at time.Time) ([]models.Plant, error) {
+    var plants []models.Plant
+    query := db.gorm.
+        Joins("JOIN plant_assignments ON plant_assignments.plant_id = plants.id").
+        Where("plant_assignments.energy_manager_id = ?", id).
+        Where("plant_assignments.valid_from <= ?", at).
+        Where("plant_assignments.valid_to IS NULL OR plant_assignments.valid_to > ?", at)
+    if role != "" {
+        query = query.Where("plant_assignments.role = ?", role)
+    }
+    result := query.Distinct("plants.*", primaryEnergyManagerColumn).Order("plants.id").Find(&plants)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return plants, ErrEmptyResult
+    }
+    return plants, nil
+}
+
+// GetPlantsByEnergyManagerIdSince returns the plants an energy manager is
+// assigned to at a given time or later on.
+func (db *PlantsDB) GetPlantsByEnergyManagerIdSince(id uint, at time.Time) ([]models.Plant, error) {
     var plants []models.Plant
-    result := db.gorm.Where("energy_manager_id = ?", id).Find(&plants)
+    result := db.gorm.
+        Joins("JOIN plant_assignments ON plant_assignments.plant_id = plants.id").
+        Where("plant_assignments.energy_manager_id = ?", id).
+        Where("plant_assignments.valid_to IS NULL OR plant_assignments.valid_to > ?", at).
+        Distinct("plants.*", primaryEnergyManagerColumn).
+        Order("plants.id").
+        Find(&plants)
     if result.Error != nil {
         return nil, result.Error
     }
@@ -219,7 +465,8 @@ func (db *PlantsDB) DeleteAssetById(asset_id uint) error {
 }
 
 func (db *PlantsDB) UpdateAsset(asset *models.Asset) error {
-    result := db.gorm.Model(asset).Updates(asset)
+    // flexibility parameters can legitimately be set back to zero
+    result := db.gorm.Model(asset).Select("*").Updates(asset)
     if result.Error != nil {
         return result.Error
     }
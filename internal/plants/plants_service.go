@@ -2,30 +2,63 @@ package plants
 
 import (
 	"errors"
+	"time"
 
+	"github.com/jeandeducla/api-plant/internal/geo"
 	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/notify"
+	"github.com/jeandeducla/api-plant/internal/outbox"
+	"github.com/jeandeducla/api-plant/internal/webhooks"
 )
 
 var (
     ErrAssetPower = errors.New("Asset MaxPower is too big for the plant")
     ErrAssetType = errors.New("Asset Type must be one of 'furnace', 'compressor', 'chiller' or 'rolling mill'")
     ErrNewEmDoesNotExist = errors.New("The EM you want to change to does not exist")
+    ErrAssetMinPower = errors.New("Asset MinPower cannot be bigger than its MaxPower")
+    ErrEmPlants = errors.New("The EM is still assigned to plants")
+    ErrEmReassign = errors.New("The EM to reassign the plants to must be another existing EM")
 )
 
-func sumAssetPower(assets []models.Asset) uint {
-    var sum uint
+func sumAssetPower(assets []models.Asset) models.Power {
+    var sum models.Power
     for _, asset := range assets {
-        sum += asset.MaxPower
+        sum = sum.Add(asset.MaxPower)
     }
     return sum
 }
 
 type Service struct {
     DB DB
+    // Notifier delivers notifications to energy managers, none are sent
+    // when it is nil.
+    Notifier *notify.Notifier
+    // Geocoder locates the plants created without coordinates, they stay
+    // unlocated when it is nil.
+    Geocoder geo.Geocoder
+    // Webhooks delivers the events to the webhooks subscribed to them, they
+    // are not recorded when it is nil.
+    Webhooks *webhooks.Client
+    // Sinks publish the events to brokers, by name. The events are written
+    // to their outbox from the moment they are registered.
+    Sinks map[string]outbox.Sink
+    now func() time.Time
+    commandSignals *signals
+    eventSignals *signals
+    webhookWakeup chan struct{}
+    outboxWakeup chan struct{}
 }
 
 func NewPlantsService(plantsDB DB) *Service {
-    return &Service{DB: plantsDB}
+    return &Service{
+        DB: plantsDB,
+        Sinks: map[string]outbox.Sink{},
+        now: time.Now,
+        commandSignals: newSignals(),
+        eventSignals: newSignals(),
+        webhookWakeup: make(chan struct{}, 1),
+        outboxWakeup: make(chan struct{}, 1),
+    }
 }
 
 func (s *Service) GetAllEnergyManagers() ([]models.EnergyManager, error) {
@@ -33,29 +66,87 @@ func (s *Service) GetAllEnergyManagers() ([]models.EnergyManager, error) {
 }
 
 type CreateEnergyManagerInput struct {
-    Name    string `json:"name" binding:"required"`
-    Surname string `json:"surname" binding:"required"`
+    Name     string `json:"name" binding:"required"`
+    Surname  string `json:"surname" binding:"required"`
+    Email    string `json:"email"`
+    Phone    string `json:"phone"`
+    Timezone string `json:"timezone"`
+    Language string `json:"language"`
 }
 
 func (s *Service) CreateEnergyManager(input CreateEnergyManagerInput) error {
     em := models.EnergyManager{
         Name: input.Name,
         Surname: input.Surname,
+        Email: input.Email,
+        Phone: input.Phone,
+        Timezone: input.Timezone,
+        Language: input.Language,
+    }
+    if err := normalizeContactDetails(&em); err != nil {
+        return err
     }
-    return s.DB.CreateEnergyManager(&em)
+    return s.transaction(func(tx DB) error {
+        if err := tx.CreateEnergyManager(&em); err != nil {
+            return err
+        }
+        return s.emit(tx, EventEmCreated, em.ID, nil, &em.ID, em)
+    })
 }
 
 func (s *Service) GetEnergyManager(id uint) (*models.EnergyManager, error) {
     return s.DB.GetEnergyManagerById(id)
 }
 
-func (s *Service) DeleteEnergyManager(id uint) error {
-    return s.DB.DeleteEnergyManagerById(id)
+// DeleteEnergyManager deletes an energy manager no longer assigned to any
+// plant, now or later on. Otherwise it returns those plants along with
+// ErrEmPlants, unless reassign_to is given: the current and planned
+// assignments are then handed over to that energy manager in the same
+// transaction as the deletion.
+func (s *Service) DeleteEnergyManager(id uint, reassign_to *uint) ([]models.Plant, error) {
+    if _, err := s.DB.GetEnergyManagerById(id); err != nil {
+        return nil, err
+    }
+    now := s.assignmentNow()
+    if reassign_to != nil {
+        if *reassign_to == id {
+            return nil, ErrEmReassign
+        }
+        if _, err := s.DB.GetEnergyManagerById(*reassign_to); errors.Is(err, ErrEmptyResult) {
+            return nil, ErrEmReassign
+        } else if err != nil {
+            return nil, err
+        }
+        return nil, s.transaction(func(tx DB) error {
+            if err := tx.ReassignEnergyManager(id, *reassign_to, now); err != nil {
+                return err
+            }
+            return s.emit(tx, EventEmDeleted, id, nil, &id, deletedResource{ID: id})
+        })
+    }
+
+    plants, err := s.DB.GetPlantsByEnergyManagerIdSince(id, now)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    if len(plants) > 0 {
+        return plants, ErrEmPlants
+    }
+    return nil, s.transaction(func(tx DB) error {
+        if err := tx.DeleteEnergyManagerById(id); err != nil {
+            return err
+        }
+        return s.emit(tx, EventEmDeleted, id, nil, &id, deletedResource{ID: id})
+    })
 }
 
 type UpdateEnergyManagerInput struct {
-    Name    string `json:"name"    binding:"required"`
-    Surname string `json:"surname" binding:"required"`
+    Name     string `json:"name"     binding:"required"`
+    Surname  string `json:"surname"  binding:"required"`
+    Email    string `json:"email"`
+    Phone    string `json:"phone"`
+    Timezone string `json:"timezone"`
+    Language string `json:"language"`
 }
 
 func (s *Service) UpdateEnergyManager(id uint, input UpdateEnergyManagerInput) error {
@@ -65,14 +156,31 @@ func (s *Service) UpdateEnergyManager(id uint, input UpdateEnergyManagerInput) e
     }
     em.Name = input.Name
     em.Surname = input.Surname
-    return s.DB.UpdateEnergyManager(em)
+    em.Email = input.Email
+    em.Phone = input.Phone
+    em.Timezone = input.Timezone
+    em.Language = input.Language
+    if err := normalizeContactDetails(em); err != nil {
+        return err
+    }
+    return s.transaction(func(tx DB) error {
+        if err := tx.UpdateEnergyManager(em); err != nil {
+            return err
+        }
+        return s.emit(tx, EventEmUpdated, em.ID, nil, &em.ID, em)
+    })
 }
 
-func (s *Service) GetEnergyManagerPlants(id uint) ([]models.Plant, error) {
+// GetEnergyManagerPlants returns the plants an energy manager is currently
+// assigned to. An empty role matches every role.
+func (s *Service) GetEnergyManagerPlants(id uint, role string) ([]models.Plant, error) {
+    if role != "" && !isAssignmentRole(role) {
+        return nil, ErrAssignmentRole
+    }
     if _, err := s.DB.GetEnergyManagerById(id); err != nil {
         return nil, err
     }
-    plants, err := s.DB.GetPlantsByEnergyManagerId(id)
+    plants, err := s.DB.GetPlantsByEnergyManagerId(id, role, s.now())
     if err != nil && err != ErrEmptyResult {
         return nil, err
     }
@@ -84,21 +192,52 @@ func (s *Service) GetAllPlants() ([]models.Plant, error) {
 }
 
 type CreatePlantInput struct {
-    Name            string `json:"name"              binding:"required"`
-    Address         string `json:"address"           binding:"required"`
-    MaxPower        uint   `json:"max_power"         binding:"required"`
-    EnergyManagerID uint   `json:"energy_manager_id" binding:"required"`
+    Name            string       `json:"name"              binding:"required"`
+    Address         string       `json:"address"           binding:"required"`
+    MaxPower        models.Power `json:"max_power"         binding:"required"`
+    EnergyManagerID uint         `json:"energy_manager_id" binding:"required"`
+    Latitude        *float64     `json:"latitude"`
+    Longitude       *float64     `json:"longitude"`
+    GridZone        string       `json:"grid_zone"`
+    CountryCode     string       `json:"country_code"`
+    Timezone        string       `json:"timezone"`
+    TariffID        *uint        `json:"tariff_id"`
 }
 
 func (s *Service) CreatePlant(input CreatePlantInput) error {
     if _, err := s.DB.GetEnergyManagerById(input.EnergyManagerID); err != nil {
         return err
     }
-    return s.DB.CreatePlant(&models.Plant{
+    timezone, err := plantTimezoneOrDefault(input.Timezone)
+    if err != nil {
+        return err
+    }
+    if err := s.checkPlantTariff(input.TariffID); err != nil {
+        return err
+    }
+    plant := models.Plant{
         Name: input.Name,
         Address: input.Address,
         MaxPower: input.MaxPower,
-        EnergyManagerID: input.EnergyManagerID,
+        Timezone: timezone,
+        TariffID: input.TariffID,
+        EnergyManagerID: &input.EnergyManagerID,
+        // the energy manager given at creation becomes the primary one
+        Assignments: []models.PlantAssignment{{
+            EnergyManagerID: input.EnergyManagerID,
+            Role: AssignmentPrimary,
+            ValidFrom: s.assignmentNow(),
+        }},
+    }
+    err = s.locatePlant(&plant, input.Latitude, input.Longitude, input.GridZone, input.CountryCode, true)
+    if err != nil {
+        return err
+    }
+    return s.transaction(func(tx DB) error {
+        if err := tx.CreatePlant(&plant); err != nil {
+            return err
+        }
+        return s.emit(tx, EventPlantCreated, plant.ID, &plant.ID, nil, plant)
     })
 }
 
@@ -107,14 +246,25 @@ func (s *Service) GetPlant(id uint) (*models.Plant, error) {
 }
 
 func (s *Service) DeletePlant(id uint) error {
-    return s.DB.DeletePlantById(id)
+    return s.transaction(func(tx DB) error {
+        if err := tx.DeletePlantById(id); err != nil {
+            return err
+        }
+        return s.emit(tx, EventPlantDeleted, id, &id, nil, deletedResource{ID: id})
+    })
 }
 
 type UpdatePlantInput struct {
-    Name            string `json:"name"              binding:"required"`
-    Address         string `json:"address"           binding:"required"`
-    MaxPower        uint   `json:"max_power"         binding:"required"`
-    EnergyManagerID uint   `json:"energy_manager_id" binding:"required"`
+    Name            string       `json:"name"              binding:"required"`
+    Address         string       `json:"address"           binding:"required"`
+    MaxPower        models.Power `json:"max_power"         binding:"required"`
+    EnergyManagerID uint         `json:"energy_manager_id" binding:"required"`
+    Latitude        *float64     `json:"latitude"`
+    Longitude       *float64     `json:"longitude"`
+    GridZone        string       `json:"grid_zone"`
+    CountryCode     string       `json:"country_code"`
+    Timezone        string       `json:"timezone"`
+    TariffID        *uint        `json:"tariff_id"`
 }
 
 func (s *Service) UpdatePlant(id uint, input UpdatePlantInput) error {
@@ -123,14 +273,6 @@ func (s *Service) UpdatePlant(id uint, input UpdatePlantInput) error {
         return err
     }
 
-    // checking new max power is ok with existing assets
-    existing_assets, err := s.DB.GetAssetsByPlantId(id)
-    if err != nil && err != ErrEmptyResult {
-        return err
-    }
-    if sumAssetPower(existing_assets) > input.MaxPower {
-        return ErrAssetPower
-    }
     plant.MaxPower = input.MaxPower
 
     // checking em exists
@@ -138,11 +280,47 @@ func (s *Service) UpdatePlant(id uint, input UpdatePlantInput) error {
     if err != nil {
         return ErrNewEmDoesNotExist
     }
-    plant.EnergyManagerID = input.EnergyManagerID
 
+    timezone, err := plantTimezoneOrDefault(input.Timezone)
+    if err != nil {
+        return err
+    }
+    plant.Timezone = timezone
+
+    if err := s.checkPlantTariff(input.TariffID); err != nil {
+        return err
+    }
+    plant.TariffID = input.TariffID
+
+    address_changed := plant.Address != input.Address
     plant.Name = input.Name
     plant.Address = input.Address
-    return s.DB.UpdatePlant(plant)
+    err = s.locatePlant(plant, input.Latitude, input.Longitude, input.GridZone, input.CountryCode, address_changed)
+    if err != nil {
+        return err
+    }
+    return s.transaction(func(tx DB) error {
+        // checking new max power is ok with existing assets, the plant being
+        // locked so that none is added meanwhile
+        if _, err := tx.LockPlantById(id); err != nil {
+            return err
+        }
+        existing_assets, err := tx.GetAssetsByPlantId(id)
+        if err != nil && err != ErrEmptyResult {
+            return err
+        }
+        if sumAssetPower(existing_assets).Watts() > input.MaxPower.Watts() {
+            return ErrAssetPower
+        }
+        if err := tx.UpdatePlant(plant); err != nil {
+            return err
+        }
+        if err := s.replacePrimaryEnergyManager(tx, id, input.EnergyManagerID); err != nil {
+            return err
+        }
+        plant.EnergyManagerID = &input.EnergyManagerID
+        return s.emit(tx, EventPlantUpdated, plant.ID, &plant.ID, nil, plant)
+    })
 }
 
 func (s *Service) GetPlantAssets(id uint) ([]models.Asset, error) {
@@ -157,28 +335,40 @@ func (s *Service) GetPlantAssets(id uint) ([]models.Asset, error) {
 }
 
 type CreateAssetInput struct {
-    Name     string `json:"name"      binding:"required"`
-    MaxPower uint   `json:"max_power" binding:"required"`
-    Type     string `json:"type"      binding:"required"`
+    Name            string       `json:"name"               binding:"required"`
+    MaxPower        models.Power `json:"max_power"          binding:"required"`
+    Type            string       `json:"type"               binding:"required"`
+    MinPower        models.Power `json:"min_power"`
+    RampUpRate      uint         `json:"ramp_up_rate"`
+    RampDownRate    uint         `json:"ramp_down_rate"`
+    MinRunTime      uint         `json:"min_run_time"`
+    MinOffTime      uint         `json:"min_off_time"`
+    MaxEventsPerDay uint         `json:"max_events_per_day"`
+    NoticePeriod    uint         `json:"notice_period"`
+    Priority        uint         `json:"priority"`
+    AgentID         *uint        `json:"agent_id"`
+    GroupID         *uint        `json:"group_id"`
+    Availability    string       `json:"availability"`
 }
 
 func (s *Service) CreateAsset(id uint, input CreateAssetInput) error  {
     if input.Type != "furnace" && input.Type != "compressor" && input.Type != "chiller" && input.Type != "rolling mill" {
         return ErrAssetType
     }
-
-    plant, err := s.DB.GetPlantById(id)
+    if input.MinPower.Watts() > input.MaxPower.Watts() {
+        return ErrAssetMinPower
+    }
+    availability, err := assetAvailabilityOrDefault(input.Availability)
     if err != nil {
         return err
     }
 
-    existing_assets, err := s.DB.GetAssetsByPlantId(id)
-    if err != nil && err != ErrEmptyResult {
+    if _, err := s.DB.GetPlantById(id); err != nil {
         return err
     }
 
-    if sumAssetPower(existing_assets) + input.MaxPower > plant.MaxPower {
-        return ErrAssetPower
+    if err := s.checkAssetAgent(id, input.AgentID); err != nil {
+        return err
     }
 
     asset := models.Asset{
@@ -186,8 +376,53 @@ func (s *Service) CreateAsset(id uint, input CreateAssetInput) error  {
         MaxPower: input.MaxPower,
         Type:  input.Type,
         PlantID: id,
+        MinPower: input.MinPower,
+        RampUpRate: input.RampUpRate,
+        RampDownRate: input.RampDownRate,
+        MinRunTime: input.MinRunTime,
+        MinOffTime: input.MinOffTime,
+        MaxEventsPerDay: input.MaxEventsPerDay,
+        NoticePeriod: input.NoticePeriod,
+        Priority: input.Priority,
+        AgentID: input.AgentID,
+        GroupID: input.GroupID,
+        Availability: availability,
     }
-    return s.DB.CreateAsset(&asset)
+    return s.transaction(func(tx DB) error {
+        if err := s.checkPlantPower(tx, id, 0, input.GroupID, input.MaxPower); err != nil {
+            return err
+        }
+        if err := tx.CreateAsset(&asset); err != nil {
+            return err
+        }
+        return s.emit(tx, EventAssetCreated, asset.ID, &asset.PlantID, nil, asset)
+    })
+}
+
+// checkPlantPower makes sure a plant has room for an asset of max_power,
+// asset_id being the asset it replaces if any, and so does its group. The
+// plant is locked until the end of the transaction tx, so that assets
+// created, resized or moved to it at once cannot all take the last of its
+// power.
+func (s *Service) checkPlantPower(tx DB, plant_id uint, asset_id uint, group_id *uint, max_power models.Power) error {
+    plant, err := tx.LockPlantById(plant_id)
+    if err != nil {
+        return err
+    }
+    existing_assets, err := tx.GetAssetsByPlantId(plant_id)
+    if err != nil && err != ErrEmptyResult {
+        return err
+    }
+    total := sumAssetPower(existing_assets)
+    for _, asset := range existing_assets {
+        if asset.ID == asset_id {
+            total = total.Sub(asset.MaxPower)
+        }
+    }
+    if total.Add(max_power).Watts() > plant.MaxPower.Watts() {
+        return ErrAssetPower
+    }
+    return s.checkAssetGroup(tx, plant_id, group_id, asset_id, max_power)
 }
 
 func (s *Service) GetPlantAsset(plant_id uint, asset_id uint) (*models.Asset, error) {
@@ -203,41 +438,74 @@ func (s *Service) DeletePlantAsset(plant_id uint, asset_id uint) error {
     if err != nil {
         return err
     }
-    return s.DB.DeleteAssetById(asset_id)
+    return s.transaction(func(tx DB) error {
+        if err := tx.DeleteAssetById(asset_id); err != nil {
+            return err
+        }
+        return s.emit(tx, EventAssetDeleted, asset_id, &plant_id, nil, deletedResource{ID: asset_id, PlantID: &plant_id})
+    })
 }
 
 type UpdateAssetInput struct {
-    Name     string `json:"name"      binding:"required"`
-    MaxPower uint   `json:"max_power" binding:"required"`
-    Type     string `json:"type"      binding:"required"`
+    Name            string       `json:"name"               binding:"required"`
+    MaxPower        models.Power `json:"max_power"          binding:"required"`
+    Type            string       `json:"type"               binding:"required"`
+    MinPower        models.Power `json:"min_power"`
+    RampUpRate      uint         `json:"ramp_up_rate"`
+    RampDownRate    uint         `json:"ramp_down_rate"`
+    MinRunTime      uint         `json:"min_run_time"`
+    MinOffTime      uint         `json:"min_off_time"`
+    MaxEventsPerDay uint         `json:"max_events_per_day"`
+    NoticePeriod    uint         `json:"notice_period"`
+    Priority        uint         `json:"priority"`
+    AgentID         *uint        `json:"agent_id"`
+    GroupID         *uint        `json:"group_id"`
+    Availability    string       `json:"availability"`
 }
 
 func (s *Service) UpdatePlantAsset(plant_id uint, asset_id uint, input UpdateAssetInput) error {
     if input.Type != "furnace" && input.Type != "compressor" && input.Type != "chiller" && input.Type != "rolling mill" {
         return ErrAssetType
     }
-
-    // checks the asset belongs to the plant
-    asset_to_change, err := s.GetPlantAsset(plant_id, asset_id)
+    if input.MinPower.Watts() > input.MaxPower.Watts() {
+        return ErrAssetMinPower
+    }
+    availability, err := assetAvailabilityOrDefault(input.Availability)
     if err != nil {
         return err
     }
 
-    // business rule enforcement
-    plant, err := s.DB.GetPlantById(plant_id)
+    // checks the asset belongs to the plant
+    asset_to_change, err := s.GetPlantAsset(plant_id, asset_id)
     if err != nil {
         return err
     }
-    existing_assets, err := s.DB.GetAssetsByPlantId(plant_id)
-    if err != nil && err != ErrEmptyResult {
+
+    if err := s.checkAssetAgent(plant_id, input.AgentID); err != nil {
         return err
     }
-    if sumAssetPower(existing_assets) - asset_to_change.MaxPower + input.MaxPower > plant.MaxPower {
-        return ErrAssetPower
-    }
 
     asset_to_change.Name = input.Name
     asset_to_change.MaxPower = input.MaxPower
     asset_to_change.Type = input.Type
-    return s.DB.UpdateAsset(asset_to_change)
+    asset_to_change.MinPower = input.MinPower
+    asset_to_change.RampUpRate = input.RampUpRate
+    asset_to_change.RampDownRate = input.RampDownRate
+    asset_to_change.MinRunTime = input.MinRunTime
+    asset_to_change.MinOffTime = input.MinOffTime
+    asset_to_change.MaxEventsPerDay = input.MaxEventsPerDay
+    asset_to_change.NoticePeriod = input.NoticePeriod
+    asset_to_change.Priority = input.Priority
+    asset_to_change.AgentID = input.AgentID
+    asset_to_change.GroupID = input.GroupID
+    asset_to_change.Availability = availability
+    return s.transaction(func(tx DB) error {
+        if err := s.checkPlantPower(tx, plant_id, asset_id, input.GroupID, input.MaxPower); err != nil {
+            return err
+        }
+        if err := tx.UpdateAsset(asset_to_change); err != nil {
+            return err
+        }
+        return s.emit(tx, EventAssetUpdated, asset_to_change.ID, &asset_to_change.PlantID, nil, asset_to_change)
+    })
 }
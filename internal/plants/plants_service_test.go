@@ -2,12 +2,26 @@ package plants
 
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
     "gorm.io/gorm"
 
+	"github.com/jeandeducla/api-plant/internal/dto"
+	"github.com/jeandeducla/api-plant/internal/geo"
 	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/notify"
+	"github.com/jeandeducla/api-plant/internal/outbox"
+	"github.com/jeandeducla/api-plant/internal/webhooks"
 )
 
 type MainTestSuite struct {
@@ -30,9 +44,30 @@ func (t *MainTestSuite) SetupTest() {
 }
 
 func (t *MainTestSuite) TearDownTest() {
+    t.db.Migrator().DropTable(&models.IdempotencyKey{})
+    t.db.Migrator().DropTable(&models.WebhookDelivery{})
+    t.db.Migrator().DropTable(&models.WebhookSubscription{})
+    t.db.Migrator().DropTable(&models.OutboxMessage{})
+    t.db.Migrator().DropTable(&models.Event{})
+    t.db.Migrator().DropTable(&models.AssetMove{})
+    t.db.Migrator().DropTable(&models.GridConnection{})
+    t.db.Migrator().DropTable(&models.NotificationPreference{})
+    t.db.Migrator().DropTable(&models.PlantAssignment{})
+    t.db.Migrator().DropTable(&models.AssetGroup{})
+    t.db.Migrator().DropTable(&models.MaintenanceWindow{})
+    t.db.Migrator().DropTable(&models.CommandTransition{})
+    t.db.Migrator().DropTable(&models.Command{})
+    t.db.Migrator().DropTable(&models.Agent{})
+    t.db.Migrator().DropTable(&models.DispatchSetpoint{})
+    t.db.Migrator().DropTable(&models.DispatchPlan{})
+    t.db.Migrator().DropTable(&models.Measurement{})
+    t.db.Migrator().DropTable(&models.CurtailmentEvent{})
     t.db.Migrator().DropTable(&models.Asset{})
     t.db.Migrator().DropTable(&models.Plant{})
     t.db.Migrator().DropTable(&models.EnergyManager{})
+    t.db.Migrator().DropTable(&models.TariffPeriod{})
+    t.db.Migrator().DropTable(&models.Tariff{})
+    t.db.Migrator().DropTable(&models.EmissionFactor{})
 }
 
 func (t *MainTestSuite) TestCreateEnergyManager() {
@@ -66,7 +101,7 @@ func (t *MainTestSuite) TestCreateEnergyManager() {
 
 func (t *MainTestSuite) TestDeleteEnergyManager() {
     // should not be able to delete a em that does not exist
-    err := t.service.DeleteEnergyManager(uint(1))
+    _, err := t.service.DeleteEnergyManager(uint(1), nil)
     t.Require().Error(err)
 
     // should be able to delete one that exists
@@ -75,36 +110,56 @@ func (t *MainTestSuite) TestDeleteEnergyManager() {
         Surname: "Depardieu",
     })
     t.Require().NoError(err)
-    err = t.service.DeleteEnergyManager(uint(1))
+    _, err = t.service.DeleteEnergyManager(uint(1), nil)
     t.Require().NoError(err)
     em, err := t.service.GetEnergyManager(uint(1))
     t.Require().Error(err)
     t.Nil(em)
 
-    // Deleting a em with plants attached to him should not delete the plants
-    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
-        Name: "Gerard",
-        Surname: "Depardieu",
-    })
+    // Deleting a em with plants attached to him is refused, unless the
+    // plants are reassigned, and should not delete the plants
+    for _, name := range []string{"Gerard", "Jacques"} {
+        err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
+            Name: name,
+            Surname: "Depardieu",
+        })
+        t.Require().NoError(err)
+    }
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 2,
     })
     t.Require().NoError(err)
-    err = t.service.DeleteEnergyManager(uint(2))
+    plants, err := t.service.DeleteEnergyManager(uint(2), nil)
+    t.Require().ErrorIs(err, ErrEmPlants)
+    t.Require().Len(plants, 1)
+    t.Equal("plant1", plants[0].Name)
+    reassign_to := uint(2)
+    _, err = t.service.DeleteEnergyManager(uint(2), &reassign_to)
+    t.Require().ErrorIs(err, ErrEmReassign)
+    reassign_to = 42
+    _, err = t.service.DeleteEnergyManager(uint(2), &reassign_to)
+    t.Require().ErrorIs(err, ErrEmReassign)
+    reassign_to = 3
+    _, err = t.service.DeleteEnergyManager(uint(2), &reassign_to)
     t.Require().NoError(err)
+    _, err = t.service.GetEnergyManager(uint(2))
+    t.Require().ErrorIs(err, ErrEmptyResult)
     plant, err := t.service.GetAllPlants()
     t.Require().NoError(err)
     t.NotNil(plant)
     t.Equal(len(plant), 1)
     t.Equal(plant[0].Name, "plant1")
+    plants, err = t.service.GetEnergyManagerPlants(uint(3), AssignmentPrimary)
+    t.Require().NoError(err)
+    t.Require().Len(plants, 1)
 }
 
 func (t *MainTestSuite) TestUpdateEnergyManager() {
     // Updating an em that does not exist should return an error
-    err := t.service.UpdateEnergyManager(uint(123), UpdateEnergyManagerInput{"Jacques", "Chirac"})
+    err := t.service.UpdateEnergyManager(uint(123), UpdateEnergyManagerInput{Name: "Jacques", Surname: "Chirac"})
     t.Require().Error(err)
 
     // You can update an existing em
@@ -113,7 +168,7 @@ func (t *MainTestSuite) TestUpdateEnergyManager() {
         Surname: "Depardieu",
     })
     t.Require().NoError(err)
-    err = t.service.UpdateEnergyManager(uint(1), UpdateEnergyManagerInput{"Jacques", "Chirac"})
+    err = t.service.UpdateEnergyManager(uint(1), UpdateEnergyManagerInput{Name: "Jacques", Surname: "Chirac"})
     t.Require().NoError(err)
     em, err := t.service.GetEnergyManager(uint(1))
     t.Require().NoError(err)
@@ -128,13 +183,13 @@ func (t *MainTestSuite) TestGetEnergyManagerPlants() {
         Surname: "Depardieu",
     })
     t.Require().NoError(err)
-    plants, err := t.service.GetEnergyManagerPlants(uint(1))
+    plants, err := t.service.GetEnergyManagerPlants(uint(1), "")
     t.Require().NoError(err)
     t.NotNil(plants)
     t.Equal(len(plants), 0)
 
     // Getting plants of an unexisting em should return an error
-    plants, err = t.service.GetEnergyManagerPlants(uint(123))
+    plants, err = t.service.GetEnergyManagerPlants(uint(123), "")
     t.Require().Error(err)
     t.Equal(len(plants), 0)
 }
@@ -152,7 +207,7 @@ func (t *MainTestSuite) TestCreatePlant() {
     err := t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 123,
     })
     t.Require().Error(err)
@@ -166,7 +221,7 @@ func (t *MainTestSuite) TestCreatePlant() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
@@ -179,7 +234,7 @@ func (t *MainTestSuite) TestCreatePlant() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant2",
         Address: "13 rue truc",
-        MaxPower: 200,
+        MaxPower: models.Kilowatts(200),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
@@ -191,7 +246,7 @@ func (t *MainTestSuite) TestCreatePlant() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant4",
         Address: "67 rue de la paix",
-        MaxPower: 1001,
+        MaxPower: models.Kilowatts(1001),
         EnergyManagerID: 2,
     })
     t.Require().NoError(err)
@@ -216,7 +271,7 @@ func (t *MainTestSuite) GetPlant() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
@@ -240,7 +295,7 @@ func (t *MainTestSuite) TestDeletePlant() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
@@ -249,7 +304,7 @@ func (t *MainTestSuite) TestDeletePlant() {
     plant, err := t.service.GetPlant(uint(1))
     t.Require().Error(err)
     t.Nil(plant)
-    plants, err := t.service.GetEnergyManagerPlants(uint(1))
+    plants, err := t.service.GetEnergyManagerPlants(uint(1), "")
     t.Require().NoError(err)
     t.Equal(len(plants), 0)
 
@@ -257,19 +312,19 @@ func (t *MainTestSuite) TestDeletePlant() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
     err = t.service.CreateAsset(uint(2), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().NoError(err)
     err = t.service.CreateAsset(uint(2), CreateAssetInput{
         Name: "asset2",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().NoError(err)
@@ -291,7 +346,7 @@ func (t *MainTestSuite) TestUpdatePlant() {
     err := t.service.UpdatePlant(uint(123), UpdatePlantInput{
         Name: "plantDeOuf",
         Address: "Mars a droite",
-        MaxPower: 1234,
+        MaxPower: models.Kilowatts(1234),
         EnergyManagerID: 2,
     })
     t.Require().Error(err)
@@ -305,14 +360,14 @@ func (t *MainTestSuite) TestUpdatePlant() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
     err = t.service.UpdatePlant(uint(1), UpdatePlantInput{
         Name: "plantDeOuf",
         Address: "Mars a droite",
-        MaxPower: 1234,
+        MaxPower: models.Kilowatts(1234),
         EnergyManagerID: 22,
     })
     t.Require().Error(err)
@@ -325,28 +380,28 @@ func (t *MainTestSuite) TestUpdatePlant() {
     err = t.service.UpdatePlant(uint(1), UpdatePlantInput{
         Name: "plantDeOuf",
         Address: "Mars a droite",
-        MaxPower: 1234,
+        MaxPower: models.Kilowatts(1234),
         EnergyManagerID: 2,
     })
     t.Require().NoError(err)
-    plants, err := t.service.GetEnergyManagerPlants(uint(2))
+    plants, err := t.service.GetEnergyManagerPlants(uint(2), "")
     t.Require().NoError(err)
     t.Equal(len(plants), 1)
-    plants, err = t.service.GetEnergyManagerPlants(uint(1))
+    plants, err = t.service.GetEnergyManagerPlants(uint(1), "")
     t.Require().NoError(err)
     t.Equal(len(plants), 0)
 
     // Updating max power of a plant that breaks its power constraint should return an error
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().NoError(err)
     err = t.service.UpdatePlant(uint(1), UpdatePlantInput{
         Name: "plantDeOuf",
         Address: "Mars a droite",
-        MaxPower: 1,
+        MaxPower: models.Kilowatts(1),
         EnergyManagerID: 2,
     })
     t.Require().Error(err)
@@ -368,7 +423,7 @@ func (t *MainTestSuite) TestGetPlantAssets() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
@@ -382,7 +437,7 @@ func (t *MainTestSuite) TestCreateAsset() {
     // Creating an asset that is not the right type should raise an error
     err := t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "eau",
     })
     t.Require().Error(err)
@@ -390,7 +445,7 @@ func (t *MainTestSuite) TestCreateAsset() {
     // Creating an asset to an unexisting plant should retiurn an error
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().Error(err)
@@ -402,12 +457,12 @@ func (t *MainTestSuite) TestCreateAsset() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     err = t.service.CreateAsset(uint(123), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().Error(err)
@@ -415,13 +470,13 @@ func (t *MainTestSuite) TestCreateAsset() {
     // Creating an asset that overpass the plant max power should return an error
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 101,
+        MaxPower: models.Kilowatts(101),
         Type: "furnace",
     })
     t.Require().Error(err)
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 99,
+        MaxPower: models.Kilowatts(99),
         Type: "furnace",
     })
     t.Require().NoError(err)
@@ -433,7 +488,7 @@ func (t *MainTestSuite) TestCreateAsset() {
     t.Equal(len(assets), 1)
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset2",
-        MaxPower: 2,
+        MaxPower: models.Kilowatts(2),
         Type: "compressor",
     })
     t.Require().Error(err)
@@ -456,12 +511,12 @@ func (t *MainTestSuite) TestGetPlantAsset() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().NoError(err)
@@ -488,13 +543,13 @@ func (t *MainTestSuite) TestDeletePlantAsset() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().NoError(err)
@@ -514,13 +569,13 @@ func (t *MainTestSuite) TestDeletePlantAsset() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant2",
         Address: "Le lune",
-        MaxPower: 101,
+        MaxPower: models.Kilowatts(101),
         EnergyManagerID: 2,
     })
     t.Require().NoError(err)
     err = t.service.CreateAsset(uint(2), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().NoError(err)
@@ -532,7 +587,7 @@ func (t *MainTestSuite) TestUpdatePlantAsset() {
     // Updating asset from a plant that does not exist should return an error
     err := t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{
         Name: "asset123",
-        MaxPower: 67,
+        MaxPower: models.Kilowatts(67),
         Type: "chiller",
     })
     t.Require().Error(err)
@@ -540,7 +595,7 @@ func (t *MainTestSuite) TestUpdatePlantAsset() {
     // Updating asset with a new invalid type should return an error
     err = t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{
         Name: "asset123",
-        MaxPower: 67,
+        MaxPower: models.Kilowatts(67),
         Type: "chill",
     })
     t.Require().Error(err)
@@ -553,19 +608,19 @@ func (t *MainTestSuite) TestUpdatePlantAsset() {
     err = t.service.CreatePlant(CreatePlantInput{
         Name: "plant1",
         Address: "17 rue truc",
-        MaxPower: 100,
+        MaxPower: models.Kilowatts(100),
         EnergyManagerID: 1,
     })
     t.Require().NoError(err)
     err = t.service.CreateAsset(uint(1), CreateAssetInput{
         Name: "asset1",
-        MaxPower: 10,
+        MaxPower: models.Kilowatts(10),
         Type: "furnace",
     })
     t.Require().NoError(err)
     err = t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{
         Name: "asset123",
-        MaxPower: 67,
+        MaxPower: models.Kilowatts(67),
         Type: "chill",
     })
     t.Require().Error(err)
@@ -573,7 +628,7 @@ func (t *MainTestSuite) TestUpdatePlantAsset() {
     // Updating asset from a plant that does exist but the asset does not exist
     err = t.service.UpdatePlantAsset(uint(1), uint(112), UpdateAssetInput{
         Name: "asset123",
-        MaxPower: 67,
+        MaxPower: models.Kilowatts(67),
         Type: "chiller",
     })
     t.Require().Error(err)
@@ -581,8 +636,2172 @@ func (t *MainTestSuite) TestUpdatePlantAsset() {
     // Updating asset that breaks the plant power limit should return an error
     err = t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{
         Name: "asset123",
-        MaxPower: 1000,
+        MaxPower: models.Kilowatts(1000),
         Type: "chiller",
     })
     t.Require().Error(err)
 }
+
+func (t *MainTestSuite) TestCreateCurtailment() {
+    starts := time.Date(2022, 5, 2, 14, 0, 0, 0, time.UTC)
+    ends := starts.Add(2 * time.Hour)
+
+    // Creating a curtailment on a plant that does not exist should return an error
+    err := t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(10),
+        StartsAt: starts,
+        EndsAt: ends,
+    })
+    t.Require().Error(err)
+
+    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(100),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "asset1",
+        MaxPower: models.Kilowatts(50),
+        Type: "furnace",
+    })
+    t.Require().NoError(err)
+
+    // A window that ends before it starts should return an error
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(10),
+        StartsAt: ends,
+        EndsAt: starts,
+    })
+    t.Require().ErrorIs(err, ErrCurtailmentWindow)
+
+    // Shedding more than the assets draw should return an error
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(51),
+        StartsAt: starts,
+        EndsAt: ends,
+    })
+    t.Require().ErrorIs(err, ErrCurtailmentPower)
+    // even by a watt
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Watts(50001),
+        StartsAt: starts,
+        EndsAt: ends,
+    })
+    t.Require().ErrorIs(err, ErrCurtailmentPower)
+
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(50),
+        StartsAt: starts,
+        EndsAt: ends,
+    })
+    t.Require().NoError(err)
+    curtailments, err := t.service.GetPlantCurtailments(uint(1))
+    t.Require().NoError(err)
+    t.Equal(len(curtailments), 1)
+    t.Equal(curtailments[0].Status, CurtailmentPlanned)
+}
+
+func (t *MainTestSuite) TestUpdateCurtailmentStatus() {
+    starts := time.Date(2022, 5, 2, 14, 0, 0, 0, time.UTC)
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(100),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "asset1",
+        MaxPower: models.Kilowatts(50),
+        Type: "furnace",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(20),
+        StartsAt: starts,
+        EndsAt: starts.Add(time.Hour),
+    })
+    t.Require().NoError(err)
+
+    // Unknown status should return an error
+    err = t.service.UpdatePlantCurtailmentStatus(uint(1), uint(1), UpdateCurtailmentStatusInput{"paused"})
+    t.Require().ErrorIs(err, ErrCurtailmentStatus)
+
+    // A planned event cannot be completed before being active
+    err = t.service.UpdatePlantCurtailmentStatus(uint(1), uint(1), UpdateCurtailmentStatusInput{CurtailmentCompleted})
+    t.Require().ErrorIs(err, ErrCurtailmentTransition)
+
+    err = t.service.UpdatePlantCurtailmentStatus(uint(1), uint(1), UpdateCurtailmentStatusInput{CurtailmentActive})
+    t.Require().NoError(err)
+    err = t.service.UpdatePlantCurtailmentStatus(uint(1), uint(1), UpdateCurtailmentStatusInput{CurtailmentCompleted})
+    t.Require().NoError(err)
+    curtailment, err := t.service.GetPlantCurtailment(uint(1), uint(1))
+    t.Require().NoError(err)
+    t.Equal(curtailment.Status, CurtailmentCompleted)
+
+    // Completed events are final
+    err = t.service.UpdatePlantCurtailmentStatus(uint(1), uint(1), UpdateCurtailmentStatusInput{CurtailmentFailed})
+    t.Require().ErrorIs(err, ErrCurtailmentTransition)
+}
+
+func (t *MainTestSuite) TestEvaluateCurtailment() {
+    starts := time.Date(2022, 5, 2, 14, 0, 0, 0, time.UTC)
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(100),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "asset1",
+        MaxPower: models.Kilowatts(50),
+        Type: "furnace",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(20),
+        StartsAt: starts,
+        EndsAt: starts.Add(time.Hour),
+    })
+    t.Require().NoError(err)
+
+    // No measurements, nothing to evaluate
+    _, err = t.service.EvaluatePlantCurtailment(uint(1), uint(1))
+    t.Require().ErrorIs(err, ErrNotEnoughMeasurements)
+
+    // The plant drew 80kW the hour before and 55kW during the event
+    asset_id := uint(1)
+    err = t.service.CreateMeasurements(uint(1), CreateMeasurementsInput{
+        Measurements: []MeasurementInput{
+            {Timestamp: starts.Add(-time.Hour), Power: 80},
+            {Timestamp: starts.Add(-30 * time.Minute), Power: 80},
+            {Timestamp: starts, Power: 60},
+            {Timestamp: starts.Add(30 * time.Minute), Power: 50},
+            {AssetID: &asset_id, Timestamp: starts, Power: 1000},
+        },
+    })
+    t.Require().NoError(err)
+    evaluation, err := t.service.EvaluatePlantCurtailment(uint(1), uint(1))
+    t.Require().NoError(err)
+    t.Equal(evaluation.BaselinePower, float64(80))
+    t.Equal(evaluation.MeasuredPower, float64(55))
+    t.Equal(evaluation.DeliveredPower, float64(25))
+    t.True(evaluation.Met)
+
+    // Measurements of assets from another plant are refused
+    other_asset_id := uint(123)
+    err = t.service.CreateMeasurements(uint(1), CreateMeasurementsInput{
+        Measurements: []MeasurementInput{
+            {AssetID: &other_asset_id, Timestamp: starts, Power: 10},
+        },
+    })
+    t.Require().ErrorIs(err, ErrMeasurementAsset)
+}
+
+func (t *MainTestSuite) TestGetPlantFlexibility() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+    from := now.Add(2 * time.Hour)
+    to := from.Add(30 * time.Minute)
+
+    // Getting the flexibility of a plant that does not exist should return an error
+    flexibility, err := t.service.GetPlantFlexibility(uint(1), from, to)
+    t.Require().Error(err)
+    t.Nil(flexibility)
+
+    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+
+    // MinPower cannot be above MaxPower
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "asset1",
+        MaxPower: models.Kilowatts(100),
+        MinPower: models.Kilowatts(101),
+        Type: "furnace",
+    })
+    t.Require().ErrorIs(err, ErrAssetMinPower)
+
+    // Can be switched off for 30 minutes, but not on
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "furnace",
+        MaxPower: models.Kilowatts(100),
+        MinPower: models.Kilowatts(40),
+        MinOffTime: 30,
+        MinRunTime: 60,
+        Type: "furnace",
+    })
+    t.Require().NoError(err)
+    // Ramps too slowly to reach its full range in 30 minutes
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "chiller",
+        MaxPower: models.Kilowatts(200),
+        RampDownRate: 2,
+        RampUpRate: 1,
+        Type: "chiller",
+    })
+    t.Require().NoError(err)
+    // Needs to be warned a day ahead
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "mill",
+        MaxPower: models.Kilowatts(300),
+        NoticePeriod: 24 * 60,
+        Type: "rolling mill",
+    })
+    t.Require().NoError(err)
+
+    flexibility, err = t.service.GetPlantFlexibility(uint(1), from, to)
+    t.Require().NoError(err)
+    t.Equal(len(flexibility.Assets), 3)
+    t.Equal(flexibility.Assets[0].Downward, models.Kilowatts(100))
+    t.Equal(flexibility.Assets[0].Upward, models.Kilowatts(0))
+    t.Equal(flexibility.Assets[1].Downward, models.Kilowatts(60))
+    t.Equal(flexibility.Assets[1].Upward, models.Kilowatts(30))
+    t.False(flexibility.Assets[2].Available)
+    t.Equal(flexibility.Assets[2].Reason, UnavailableNoticePeriod)
+    t.Equal(flexibility.Downward, models.Kilowatts(160))
+    t.Equal(flexibility.Upward, models.Kilowatts(30))
+}
+
+func (t *MainTestSuite) TestAllocateReduction() {
+    candidate := func(id uint, priority uint, downward uint) dispatchCandidate {
+        asset := models.Asset{MaxPower: models.Kilowatts(downward), Priority: priority}
+        asset.ID = id
+        return dispatchCandidate{asset: asset, downward: models.Kilowatts(downward)}
+    }
+    candidates := []dispatchCandidate{
+        candidate(1, 0, 30),
+        candidate(2, 1, 100),
+        candidate(3, 2, 80),
+        candidate(4, 0, 70),
+    }
+
+    // A single asset is enough, the one with the lowest priority is used
+    allocation, err := allocateReduction(candidates, models.Kilowatts(60))
+    t.Require().NoError(err)
+    t.Equal(len(allocation), 1)
+    t.Equal(allocation[0].asset.ID, uint(4))
+
+    // Two assets are needed, lower priorities are loaded first
+    allocation, err = allocateReduction(candidates, models.Kilowatts(120))
+    t.Require().NoError(err)
+    t.Equal(len(allocation), 2)
+    t.Equal(allocation[0].asset.ID, uint(1))
+    t.Equal(allocation[0].downward, models.Kilowatts(30))
+    t.Equal(allocation[1].asset.ID, uint(2))
+    t.Equal(allocation[1].downward, models.Kilowatts(90))
+
+    // Not enough flexibility
+    _, err = allocateReduction(candidates, models.Kilowatts(281))
+    t.Require().ErrorIs(err, ErrDispatchFlexibility)
+}
+
+func (t *MainTestSuite) TestCreateDispatchPlan() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+    starts := now.Add(2 * time.Hour)
+    ends := starts.Add(time.Hour)
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "furnace",
+        MaxPower: models.Kilowatts(100),
+        RampDownRate: 10,
+        Type: "furnace",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "chiller",
+        MaxPower: models.Kilowatts(200),
+        Type: "chiller",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(250),
+        StartsAt: starts,
+        EndsAt: ends,
+    })
+    t.Require().NoError(err)
+
+    // Needs a target
+    _, err = t.service.CreateDispatchPlan(uint(1), CreateDispatchPlanInput{}, true)
+    t.Require().ErrorIs(err, ErrDispatchTarget)
+
+    // Dry run does not save anything
+    curtailment_id := uint(1)
+    plan, err := t.service.CreateDispatchPlan(uint(1), CreateDispatchPlanInput{CurtailmentID: &curtailment_id}, true)
+    t.Require().NoError(err)
+    t.Equal(len(plan.Setpoints), 2)
+    plans, err := t.service.GetPlantDispatchPlans(uint(1))
+    t.Require().NoError(err)
+    t.Equal(len(plans), 0)
+
+    plan, err = t.service.CreateDispatchPlan(uint(1), CreateDispatchPlanInput{CurtailmentID: &curtailment_id}, false)
+    t.Require().NoError(err)
+    plan, err = t.service.GetPlantDispatchPlan(uint(1), plan.ID)
+    t.Require().NoError(err)
+    t.Equal(plan.TargetReduction, models.Kilowatts(250))
+    t.Equal(len(plan.Setpoints), 2)
+    t.Equal(plan.Setpoints[0].AssetID, uint(1))
+    t.Equal(plan.Setpoints[0].Setpoint, models.Kilowatts(0))
+    t.True(plan.Setpoints[0].RampStartsAt.Equal(starts.Add(-10 * time.Minute)))
+    t.Equal(plan.Setpoints[1].AssetID, uint(2))
+    t.Equal(plan.Setpoints[1].Setpoint, models.Kilowatts(50))
+
+    // Above what the assets can do
+    _, err = t.service.CreateDispatchPlan(uint(1), CreateDispatchPlanInput{
+        TargetReduction: models.Kilowatts(301),
+        StartsAt: starts,
+        EndsAt: ends,
+    }, true)
+    t.Require().ErrorIs(err, ErrDispatchFlexibility)
+}
+
+func (t *MainTestSuite) TestAgentCommands() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+    ctx := context.Background()
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(100),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAgent(CreateAgentInput{Name: "edge1", PlantID: 1})
+    t.Require().NoError(err)
+
+    // An asset with no agent cannot receive commands
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "asset1",
+        MaxPower: models.Kilowatts(50),
+        Type: "furnace",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAssetCommand(uint(1), uint(1), CreateCommandInput{Setpoint: models.Kilowatts(10)})
+    t.Require().ErrorIs(err, ErrAssetNoAgent)
+
+    // An agent of another plant cannot be used
+    agent_id := uint(2)
+    err = t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{
+        Name: "asset1",
+        MaxPower: models.Kilowatts(50),
+        Type: "furnace",
+        AgentID: &agent_id,
+    })
+    t.Require().ErrorIs(err, ErrAssetAgent)
+
+    agent_id = uint(1)
+    err = t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{
+        Name: "asset1",
+        MaxPower: models.Kilowatts(50),
+        Type: "furnace",
+        AgentID: &agent_id,
+    })
+    t.Require().NoError(err)
+
+    // Setpoint above the asset max power
+    err = t.service.CreateAssetCommand(uint(1), uint(1), CreateCommandInput{Setpoint: models.Kilowatts(51)})
+    t.Require().ErrorIs(err, ErrCommandSetpoint)
+
+    // Nothing to fetch yet
+    commands, err := t.service.FetchAgentCommands(ctx, uint(1), 0)
+    t.Require().NoError(err)
+    t.Equal(len(commands), 0)
+
+    err = t.service.CreateAssetCommand(uint(1), uint(1), CreateCommandInput{Setpoint: models.Kilowatts(10)})
+    t.Require().NoError(err)
+    commands, err = t.service.FetchAgentCommands(ctx, uint(1), 0)
+    t.Require().NoError(err)
+    t.Equal(len(commands), 1)
+    t.Equal(commands[0].Setpoint, models.Kilowatts(10))
+    t.Equal(commands[0].Attempts, uint(1))
+
+    // Delivered commands are not fetched twice
+    commands, err = t.service.FetchAgentCommands(ctx, uint(1), 0)
+    t.Require().NoError(err)
+    t.Equal(len(commands), 0)
+
+    // Not acknowledged in time, it is delivered again
+    now = now.Add(CommandAckTimeout)
+    commands, err = t.service.FetchAgentCommands(ctx, uint(1), 0)
+    t.Require().NoError(err)
+    t.Equal(len(commands), 1)
+    t.Equal(commands[0].Attempts, uint(2))
+
+    err = t.service.AcknowledgeAgentCommand(uint(1), commands[0].ID)
+    t.Require().NoError(err)
+    err = t.service.RejectAgentCommand(uint(1), commands[0].ID, RejectCommandInput{"too late"})
+    t.Require().ErrorIs(err, ErrCommandStatus)
+
+    command, err := t.service.GetAssetCommand(uint(1), uint(1), commands[0].ID)
+    t.Require().NoError(err)
+    t.Equal(command.Status, CommandAcknowledged)
+    t.Equal(len(command.History), 5)
+
+    // Never acknowledged, it expires after the last attempt
+    err = t.service.CreateAssetCommand(uint(1), uint(1), CreateCommandInput{Setpoint: models.Kilowatts(20)})
+    t.Require().NoError(err)
+    for i := 0; i < CommandMaxAttempts; i++ {
+        commands, err = t.service.FetchAgentCommands(ctx, uint(1), 0)
+        t.Require().NoError(err)
+        t.Equal(len(commands), 1)
+        now = now.Add(CommandAckTimeout)
+    }
+    commands, err = t.service.FetchAgentCommands(ctx, uint(1), 0)
+    t.Require().NoError(err)
+    t.Equal(len(commands), 0)
+    commands, err = t.service.GetAssetCommands(uint(1), uint(1))
+    t.Require().NoError(err)
+    t.Equal(len(commands), 2)
+    t.Equal(commands[1].Status, CommandExpired)
+}
+
+func (t *MainTestSuite) TestDispatchPlanCommands() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+    ctx := context.Background()
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAgent(CreateAgentInput{Name: "edge1", PlantID: 1})
+    t.Require().NoError(err)
+    agent_id := uint(1)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "furnace",
+        MaxPower: models.Kilowatts(100),
+        Type: "furnace",
+        AgentID: &agent_id,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "chiller",
+        MaxPower: models.Kilowatts(200),
+        Type: "chiller",
+    })
+    t.Require().NoError(err)
+    plan, err := t.service.CreateDispatchPlan(uint(1), CreateDispatchPlanInput{
+        TargetReduction: models.Kilowatts(250),
+        StartsAt: now.Add(2 * time.Hour),
+        EndsAt: now.Add(3 * time.Hour),
+    }, false)
+    t.Require().NoError(err)
+    t.Require().Equal(len(plan.Setpoints), 2)
+
+    // Nothing is queued when one of the assets cannot take a command
+    err = t.service.QueueDispatchPlanCommands(uint(1), plan.ID)
+    t.Require().ErrorIs(err, ErrAssetNoAgent)
+    commands, err := t.service.GetAssetCommands(uint(1), uint(1))
+    t.Require().NoError(err)
+    t.Equal(len(commands), 0)
+
+    err = t.service.UpdatePlantAsset(uint(1), uint(2), UpdateAssetInput{
+        Name: "chiller",
+        MaxPower: models.Kilowatts(200),
+        Type: "chiller",
+        AgentID: &agent_id,
+    })
+    t.Require().NoError(err)
+    err = t.service.QueueDispatchPlanCommands(uint(1), plan.ID)
+    t.Require().NoError(err)
+
+    // Queuing the plan again does not queue its setpoints twice
+    err = t.service.QueueDispatchPlanCommands(uint(1), plan.ID)
+    t.Require().NoError(err)
+    commands, err = t.service.FetchAgentCommands(ctx, uint(1), 0)
+    t.Require().NoError(err)
+    t.Require().Equal(len(commands), 2)
+
+    // but it does queue the rejected ones again
+    err = t.service.RejectAgentCommand(uint(1), commands[0].ID, RejectCommandInput{"busy"})
+    t.Require().NoError(err)
+    err = t.service.QueueDispatchPlanCommands(uint(1), plan.ID)
+    t.Require().NoError(err)
+    commands, err = t.service.FetchAgentCommands(ctx, uint(1), 0)
+    t.Require().NoError(err)
+    t.Require().Equal(len(commands), 1)
+    t.Equal(*commands[0].DispatchSetpointID, plan.Setpoints[0].ID)
+}
+
+func (t *MainTestSuite) TestAssetMaintenance() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "furnace",
+        MaxPower: models.Kilowatts(100),
+        Type: "furnace",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "chiller",
+        MaxPower: models.Kilowatts(200),
+        Type: "chiller",
+    })
+    t.Require().NoError(err)
+
+    // Unknown availability
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{
+        Name: "mill",
+        MaxPower: models.Kilowatts(300),
+        Type: "rolling mill",
+        Availability: "sometimes",
+    })
+    t.Require().ErrorIs(err, ErrAssetAvailability)
+
+    // Invalid windows
+    err = t.service.CreateAssetMaintenanceWindow(uint(1), uint(1), CreateMaintenanceWindowInput{
+        StartsAt: now,
+        EndsAt: now.Add(-time.Hour),
+    })
+    t.Require().ErrorIs(err, ErrMaintenanceWindow)
+    err = t.service.CreateAssetMaintenanceWindow(uint(1), uint(1), CreateMaintenanceWindowInput{
+        StartsAt: now,
+        EndsAt: now.Add(time.Hour),
+        RRule: "FREQ=SOMETIMES",
+    })
+    t.Require().ErrorIs(err, ErrMaintenanceRRule)
+
+    // The furnace is serviced every day from 10:00 to 12:00
+    err = t.service.CreateAssetMaintenanceWindow(uint(1), uint(1), CreateMaintenanceWindowInput{
+        StartsAt: now.Add(2 * time.Hour),
+        EndsAt: now.Add(4 * time.Hour),
+        RRule: "FREQ=DAILY",
+        Reason: "cleaning",
+    })
+    t.Require().NoError(err)
+    windows, err := t.service.GetAssetMaintenanceWindows(uint(1), uint(1))
+    t.Require().NoError(err)
+    t.Equal(len(windows), 1)
+
+    nextDay := now.Add(24 * time.Hour)
+    availability, err := t.service.GetAssetAvailability(uint(1), uint(1), nextDay.Add(3 * time.Hour), nextDay.Add(5 * time.Hour))
+    t.Require().NoError(err)
+    t.False(availability.Available)
+    t.Equal(availability.Reason, UnavailableMaintenance)
+    availability, err = t.service.GetAssetAvailability(uint(1), uint(1), nextDay.Add(4 * time.Hour), nextDay.Add(5 * time.Hour))
+    t.Require().NoError(err)
+    t.True(availability.Available)
+
+    // During maintenance the furnace neither counts toward flexibility nor capacity
+    flexibility, err := t.service.GetPlantFlexibility(uint(1), nextDay.Add(2 * time.Hour), nextDay.Add(3 * time.Hour))
+    t.Require().NoError(err)
+    t.Equal(flexibility.Downward, models.Kilowatts(200))
+    t.Equal(flexibility.Assets[0].Reason, UnavailableMaintenance)
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(250),
+        StartsAt: nextDay.Add(2 * time.Hour),
+        EndsAt: nextDay.Add(3 * time.Hour),
+    })
+    t.Require().ErrorIs(err, ErrCurtailmentPower)
+
+    // Out of service assets cannot receive commands
+    err = t.service.CreateAgent(CreateAgentInput{Name: "edge1", PlantID: 1})
+    t.Require().NoError(err)
+    agent_id := uint(1)
+    err = t.service.UpdatePlantAsset(uint(1), uint(2), UpdateAssetInput{
+        Name: "chiller",
+        MaxPower: models.Kilowatts(200),
+        Type: "chiller",
+        AgentID: &agent_id,
+        Availability: AssetUnavailable,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAssetCommand(uint(1), uint(2), CreateCommandInput{Setpoint: models.Kilowatts(10)})
+    t.Require().ErrorIs(err, ErrAssetUnavailable)
+
+    err = t.service.DeleteAssetMaintenanceWindow(uint(1), uint(1), uint(1))
+    t.Require().NoError(err)
+    windows, err = t.service.GetAssetMaintenanceWindows(uint(1), uint(1))
+    t.Require().NoError(err)
+    t.Equal(len(windows), 0)
+}
+
+func (t *MainTestSuite) TestAssetGroups() {
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+
+    // Unknown kind and unknown parent
+    err = t.service.CreateGroup(uint(1), CreateGroupInput{Name: "b1", Kind: "floor"})
+    t.Require().ErrorIs(err, ErrGroupKind)
+    parent := uint(42)
+    err = t.service.CreateGroup(uint(1), CreateGroupInput{Name: "b1", Kind: "building", ParentID: &parent})
+    t.Require().ErrorIs(err, ErrGroupParent)
+
+    // building 1 (max 300) > line 2 (max 200), building 3 without limit
+    err = t.service.CreateGroup(uint(1), CreateGroupInput{Name: "b1", Kind: "building", MaxPower: models.Kilowatts(300)})
+    t.Require().NoError(err)
+    building := uint(1)
+    err = t.service.CreateGroup(uint(1), CreateGroupInput{Name: "l1", Kind: "line", ParentID: &building, MaxPower: models.Kilowatts(200)})
+    t.Require().NoError(err)
+    err = t.service.CreateGroup(uint(1), CreateGroupInput{Name: "b2", Kind: "building"})
+    t.Require().NoError(err)
+    line := uint(2)
+
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(150), Type: "furnace", GroupID: &line})
+    t.Require().NoError(err)
+    // too big for the line
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "chiller", MaxPower: models.Kilowatts(100), Type: "chiller", GroupID: &line})
+    t.Require().ErrorIs(err, ErrAssetGroupPower)
+    // too big for the building
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "chiller", MaxPower: models.Kilowatts(200), Type: "chiller", GroupID: &building})
+    t.Require().ErrorIs(err, ErrAssetGroupPower)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "chiller", MaxPower: models.Kilowatts(100), Type: "chiller", GroupID: &building})
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "mill", MaxPower: models.Kilowatts(500), Type: "rolling mill"})
+    t.Require().NoError(err)
+
+    // updating the furnace in place only counts its new power
+    err = t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(200), Type: "furnace", GroupID: &line})
+    t.Require().NoError(err)
+    err = t.service.UpdatePlantAsset(uint(1), uint(1), UpdateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(210), Type: "furnace", GroupID: &line})
+    t.Require().ErrorIs(err, ErrAssetGroupPower)
+
+    assets, err := t.service.GetPlantGroupAssets(uint(1), uint(1), false)
+    t.Require().NoError(err)
+    t.Require().Len(assets, 1)
+    t.Require().Equal("chiller", assets[0].Name)
+    assets, err = t.service.GetPlantGroupAssets(uint(1), uint(1), true)
+    t.Require().NoError(err)
+    t.Require().Len(assets, 2)
+
+    tree, err := t.service.GetPlantTree(uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(tree.Assets, 1)
+    t.Require().Equal("mill", tree.Assets[0].Name)
+    t.Require().Len(tree.Groups, 2)
+    t.Require().Len(tree.Groups[0].Assets, 1)
+    t.Require().Len(tree.Groups[0].Children, 1)
+    t.Require().Equal("furnace", tree.Groups[0].Children[0].Assets[0].Name)
+    t.Require().Len(tree.Groups[1].Children, 0)
+
+    // a group cannot be moved under its own subtree
+    err = t.service.UpdatePlantGroup(uint(1), uint(1), UpdateGroupInput{Name: "b1", Kind: "building", ParentID: &line})
+    t.Require().ErrorIs(err, ErrGroupParent)
+    // nor get a limit below what it already contains
+    err = t.service.UpdatePlantGroup(uint(1), uint(1), UpdateGroupInput{Name: "b1", Kind: "building", MaxPower: models.Kilowatts(250)})
+    t.Require().ErrorIs(err, ErrGroupPower)
+    // moving the line to building 2 frees building 1
+    other := uint(3)
+    err = t.service.UpdatePlantGroup(uint(1), uint(2), UpdateGroupInput{Name: "l1", Kind: "line", ParentID: &other, MaxPower: models.Kilowatts(200)})
+    t.Require().NoError(err)
+    err = t.service.UpdatePlantGroup(uint(1), uint(1), UpdateGroupInput{Name: "b1", Kind: "building", MaxPower: models.Kilowatts(100)})
+    t.Require().NoError(err)
+
+    // deleting a group detaches its assets
+    err = t.service.DeletePlantGroup(uint(1), uint(3))
+    t.Require().NoError(err)
+    groups, err := t.service.GetPlantGroups(uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(groups, 1)
+    asset, err := t.service.GetPlantAsset(uint(1), uint(1))
+    t.Require().NoError(err)
+    t.Require().Nil(asset.GroupID)
+}
+
+func (t *MainTestSuite) TestPlantAssignments() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+
+    for _, name := range []string{"Gerard", "Jacques", "Catherine"} {
+        err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+            Name: name,
+            Surname: "Depardieu",
+        })
+        t.Require().NoError(err)
+    }
+    err := t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+
+    // the energy manager given at creation is the primary one
+    assignments, err := t.service.GetPlantAssignments(uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(assignments, 1)
+    t.Equal(uint(1), assignments[0].EnergyManagerID)
+    t.Equal(AssignmentPrimary, assignments[0].Role)
+    t.Nil(assignments[0].ValidTo)
+    plant, err := t.service.GetPlant(uint(1))
+    t.Require().NoError(err)
+    t.Require().NotNil(plant.EnergyManagerID)
+    t.Equal(uint(1), *plant.EnergyManagerID)
+
+    // invalid assignments
+    err = t.service.CreateAssignment(uint(1), CreateAssignmentInput{EnergyManagerID: 2, Role: "boss"})
+    t.Require().ErrorIs(err, ErrAssignmentRole)
+    err = t.service.CreateAssignment(uint(1), CreateAssignmentInput{EnergyManagerID: 42, Role: AssignmentBackup})
+    t.Require().ErrorIs(err, ErrEmptyResult)
+    before := now.Add(-time.Hour)
+    err = t.service.CreateAssignment(uint(1), CreateAssignmentInput{EnergyManagerID: 2, Role: AssignmentBackup, ValidTo: &before})
+    t.Require().ErrorIs(err, ErrAssignmentWindow)
+    // a plant has a single primary energy manager at once
+    err = t.service.CreateAssignment(uint(1), CreateAssignmentInput{EnergyManagerID: 2, Role: AssignmentPrimary})
+    t.Require().ErrorIs(err, ErrAssignmentOverlap)
+
+    err = t.service.CreateAssignment(uint(1), CreateAssignmentInput{EnergyManagerID: 2, Role: AssignmentBackup})
+    t.Require().NoError(err)
+    tomorrow := now.Add(24 * time.Hour)
+    err = t.service.CreateAssignment(uint(1), CreateAssignmentInput{EnergyManagerID: 3, Role: AssignmentOnCall, ValidFrom: &tomorrow})
+    t.Require().NoError(err)
+
+    // energy managers see the plants they currently hold any role on
+    plants, err := t.service.GetEnergyManagerPlants(uint(2), "")
+    t.Require().NoError(err)
+    t.Require().Len(plants, 1)
+    plants, err = t.service.GetEnergyManagerPlants(uint(2), AssignmentPrimary)
+    t.Require().NoError(err)
+    t.Require().Len(plants, 0)
+    plants, err = t.service.GetEnergyManagerPlants(uint(3), "")
+    t.Require().NoError(err)
+    t.Require().Len(plants, 0)
+    _, err = t.service.GetEnergyManagerPlants(uint(3), "boss")
+    t.Require().ErrorIs(err, ErrAssignmentRole)
+
+    now = tomorrow
+    plants, err = t.service.GetEnergyManagerPlants(uint(3), AssignmentOnCall)
+    t.Require().NoError(err)
+    t.Require().Len(plants, 1)
+
+    // changing the energy manager of the plant replaces its primary assignment
+    err = t.service.UpdatePlant(uint(1), UpdatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 2,
+    })
+    t.Require().NoError(err)
+    plants, err = t.service.GetEnergyManagerPlants(uint(1), "")
+    t.Require().NoError(err)
+    t.Require().Len(plants, 0)
+    plants, err = t.service.GetEnergyManagerPlants(uint(2), AssignmentPrimary)
+    t.Require().NoError(err)
+    t.Require().Len(plants, 1)
+    t.Require().NotNil(plants[0].EnergyManagerID)
+    t.Equal(uint(2), *plants[0].EnergyManagerID)
+    assignments, err = t.service.GetPlantAssignments(uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(assignments, 4)
+    t.Require().NotNil(assignments[0].ValidTo)
+    t.True(assignments[0].ValidTo.Equal(now))
+
+    // an energy manager on call is only deleted once replaced, which
+    // removes its past assignments
+    _, err = t.service.DeleteEnergyManager(uint(3), nil)
+    t.Require().ErrorIs(err, ErrEmPlants)
+    reassign_to := uint(1)
+    _, err = t.service.DeleteEnergyManager(uint(3), &reassign_to)
+    t.Require().NoError(err)
+    assignments, err = t.service.GetPlantAssignments(uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(assignments, 4)
+    t.Equal(uint(1), assignments[3].EnergyManagerID)
+    t.Equal(AssignmentOnCall, assignments[3].Role)
+    t.True(assignments[3].ValidFrom.Equal(now))
+}
+
+type channelSender chan notify.Message
+
+func (c channelSender) Send(ctx context.Context, msg notify.Message) error {
+    c <- msg
+    return nil
+}
+
+func (t *MainTestSuite) TestEnergyManagerNotifications() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+    emails := make(channelSender, 10)
+    webhooks := make(channelSender, 10)
+    t.service.Notifier = notify.NewNotifier()
+    t.service.Notifier.Register(NotificationEmail, emails)
+    t.service.Notifier.Register(NotificationWebhook, webhooks)
+
+    // contact details are validated
+    invalid := []CreateEnergyManagerInput{
+        {Name: "Gerard", Surname: "Depardieu", Email: "gerard"},
+        {Name: "Gerard", Surname: "Depardieu", Phone: "06 12 34 56 78"},
+        {Name: "Gerard", Surname: "Depardieu", Timezone: "Europe/Nowhere"},
+        {Name: "Gerard", Surname: "Depardieu", Language: "not a language"},
+    }
+    for _, input := range invalid {
+        err := t.service.CreateEnergyManager(input)
+        t.Require().Error(err)
+    }
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+        Email: "gerard@example.com",
+        Phone: "+33612345678",
+        Timezone: "Europe/Paris",
+        Language: "FR-fr",
+    })
+    t.Require().NoError(err)
+    em, err := t.service.GetEnergyManager(uint(1))
+    t.Require().NoError(err)
+    t.Equal("fr-FR", em.Language)
+
+    // defaults are used when nothing is given
+    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: "Jacques", Surname: "Chirac"})
+    t.Require().NoError(err)
+    em, err = t.service.GetEnergyManager(uint(2))
+    t.Require().NoError(err)
+    t.Equal("UTC", em.Timezone)
+    t.Equal("en", em.Language)
+
+    // preferences
+    err = t.service.UpdateEnergyManagerNotificationPreferences(uint(2), UpdateNotificationPreferencesInput{
+        Preferences: []NotificationPreferenceInput{{Channel: NotificationEmail, Enabled: true}},
+    })
+    t.Require().ErrorIs(err, ErrNotificationTarget)
+    err = t.service.UpdateEnergyManagerNotificationPreferences(uint(1), UpdateNotificationPreferencesInput{
+        Preferences: []NotificationPreferenceInput{{Channel: "pigeon", Enabled: true}},
+    })
+    t.Require().ErrorIs(err, ErrNotificationChannel)
+    err = t.service.UpdateEnergyManagerNotificationPreferences(uint(1), UpdateNotificationPreferencesInput{
+        Preferences: []NotificationPreferenceInput{{Channel: NotificationWebhook, Enabled: true, Target: "ftp://example.com"}},
+    })
+    t.Require().ErrorIs(err, ErrNotificationTarget)
+    err = t.service.UpdateEnergyManagerNotificationPreferences(uint(1), UpdateNotificationPreferencesInput{
+        Preferences: []NotificationPreferenceInput{
+            {Channel: NotificationEmail, Enabled: true},
+            {Channel: NotificationWebhook, Enabled: false, Target: "https://example.com/hook"},
+        },
+    })
+    t.Require().NoError(err)
+    preferences, err := t.service.GetEnergyManagerNotificationPreferences(uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(preferences, 2)
+
+    // curtailments are notified to the managers of the plant
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(100), Type: "furnace"})
+    t.Require().NoError(err)
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(50),
+        StartsAt: now.Add(2 * time.Hour),
+        EndsAt: now.Add(3 * time.Hour),
+    })
+    t.Require().NoError(err)
+
+    select {
+    case msg := <-emails:
+        t.Equal("gerard@example.com", msg.To)
+        t.Equal("Curtailment planned at plant1", msg.Subject)
+        // written in the timezone of the energy manager
+        t.Contains(msg.Body, "Mon 2 May 2022 12:00 CEST")
+    case <-time.After(5 * time.Second):
+        t.Fail("no email sent")
+    }
+    t.Len(webhooks, 0)
+}
+
+func (t *MainTestSuite) TestSearchPlants() {
+    gazetteer, err := geo.NewGazetteer(strings.NewReader("Paris,48.8566,2.3522,FR\nLyon,45.7640,4.8357,FR\n"))
+    t.Require().NoError(err)
+    t.service.Geocoder = gazetteer
+
+    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+
+    // invalid locations
+    lat, lon := 48.9, 2.5
+    err = t.service.CreatePlant(CreatePlantInput{Name: "p", Address: "a", MaxPower: models.Kilowatts(100), EnergyManagerID: 1, Latitude: &lat})
+    t.Require().ErrorIs(err, ErrPlantLocation)
+    err = t.service.CreatePlant(CreatePlantInput{Name: "p", Address: "a", MaxPower: models.Kilowatts(100), EnergyManagerID: 1, CountryCode: "FRA"})
+    t.Require().ErrorIs(err, ErrPlantCountry)
+
+    // located from its coordinates, from its address, and not at all
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "saint-denis",
+        Address: "somewhere north",
+        MaxPower: models.Kilowatts(100),
+        EnergyManagerID: 1,
+        Latitude: &lat,
+        Longitude: &lon,
+        GridZone: "FR",
+        CountryCode: "fr",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{Name: "paris", Address: "17 rue truc, Paris", MaxPower: models.Kilowatts(100), EnergyManagerID: 1, GridZone: "FR"})
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{Name: "lyon", Address: "1 place Bellecour, Lyon", MaxPower: models.Kilowatts(100), EnergyManagerID: 1})
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{Name: "nowhere", Address: "Mars a droite", MaxPower: models.Kilowatts(100), EnergyManagerID: 1})
+    t.Require().NoError(err)
+
+    plant, err := t.service.GetPlant(uint(2))
+    t.Require().NoError(err)
+    t.Require().NotNil(plant.Latitude)
+    t.Equal(48.8566, *plant.Latitude)
+    t.Equal("FR", plant.CountryCode)
+    plant, err = t.service.GetPlant(uint(4))
+    t.Require().NoError(err)
+    t.Nil(plant.Latitude)
+
+    // closest first
+    paris := geo.Point{Latitude: 48.85, Longitude: 2.35}
+    plants, err := t.service.SearchPlants(PlantQuery{Near: &paris, RadiusKm: 50})
+    t.Require().NoError(err)
+    t.Require().Len(plants, 2)
+    t.Equal("paris", plants[0].Name)
+    t.Equal("saint-denis", plants[1].Name)
+    plants, err = t.service.SearchPlants(PlantQuery{Near: &paris, RadiusKm: 500})
+    t.Require().NoError(err)
+    t.Require().Len(plants, 3)
+
+    bbox := geo.BBox{MinLongitude: 2, MinLatitude: 48, MaxLongitude: 3, MaxLatitude: 49}
+    plants, err = t.service.SearchPlants(PlantQuery{BBox: &bbox, GridZone: "FR"})
+    t.Require().NoError(err)
+    t.Require().Len(plants, 2)
+    plants, err = t.service.SearchPlants(PlantQuery{CountryCode: "fr"})
+    t.Require().NoError(err)
+    t.Require().Len(plants, 3)
+
+    _, err = t.service.SearchPlants(PlantQuery{Near: &paris})
+    t.Require().ErrorIs(err, ErrPlantQuery)
+    _, err = t.service.SearchPlants(PlantQuery{Near: &paris, RadiusKm: 50, BBox: &bbox})
+    t.Require().ErrorIs(err, ErrPlantQuery)
+
+    // a new address is located again
+    err = t.service.UpdatePlant(uint(3), UpdatePlantInput{Name: "lyon", Address: "2 rue de Rivoli, Paris", MaxPower: models.Kilowatts(100), EnergyManagerID: 1})
+    t.Require().NoError(err)
+    plants, err = t.service.SearchPlants(PlantQuery{Near: &paris, RadiusKm: 50})
+    t.Require().NoError(err)
+    t.Require().Len(plants, 3)
+}
+
+func (t *MainTestSuite) TestPlantTimezone() {
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+        Timezone: "Europe/Nowhere",
+    })
+    t.Require().ErrorIs(err, ErrPlantTimezone)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+        Timezone: "Europe/Paris",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(100), Type: "furnace"})
+    t.Require().NoError(err)
+
+    // a constant 100 kW, read every hour around both DST changes of 2022
+    readings := []MeasurementInput{}
+    for _, start := range []time.Time{
+        time.Date(2022, 3, 25, 23, 0, 0, 0, time.UTC),
+        time.Date(2022, 10, 28, 22, 0, 0, 0, time.UTC),
+    } {
+        for h := 0; h < 72; h++ {
+            readings = append(readings, MeasurementInput{Timestamp: start.Add(time.Duration(h) * time.Hour), Power: 100})
+        }
+    }
+    err = t.service.CreateMeasurements(uint(1), CreateMeasurementsInput{Measurements: readings})
+    t.Require().NoError(err)
+
+    _, err = t.service.GetPlantLoadCurve(uint(1), time.Now(), time.Now(), "fortnight")
+    t.Require().ErrorIs(err, ErrInterval)
+
+    // clocks go forward on Sunday 27 March, the local day lasts 23 hours
+    curve, err := t.service.GetPlantLoadCurve(uint(1), time.Date(2022, 3, 26, 12, 0, 0, 0, time.UTC), time.Date(2022, 3, 27, 12, 0, 0, 0, time.UTC), IntervalDay)
+    t.Require().NoError(err)
+    t.Equal("Europe/Paris", curve.Timezone)
+    t.Require().Len(curve.Buckets, 2)
+    t.True(curve.Buckets[0].StartsAt.Equal(time.Date(2022, 3, 25, 23, 0, 0, 0, time.UTC)))
+    t.True(curve.Buckets[1].StartsAt.Equal(time.Date(2022, 3, 26, 23, 0, 0, 0, time.UTC)))
+    t.True(curve.Buckets[1].EndsAt.Equal(time.Date(2022, 3, 27, 22, 0, 0, 0, time.UTC)))
+    t.Equal(24, curve.Buckets[0].Readings)
+    t.Equal(23, curve.Buckets[1].Readings)
+    t.Require().NotNil(curve.Buckets[1].Energy)
+    t.InDelta(2300, *curve.Buckets[1].Energy, 1e-9)
+
+    // clocks go back on Sunday 30 October, the local day lasts 25 hours and
+    // 02:00 happens twice
+    curve, err = t.service.GetPlantLoadCurve(uint(1), time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 30, 1, 0, 0, 0, time.UTC), IntervalDay)
+    t.Require().NoError(err)
+    t.Require().Len(curve.Buckets, 1)
+    t.Equal(25, curve.Buckets[0].Readings)
+    t.InDelta(2500, *curve.Buckets[0].Energy, 1e-9)
+    curve, err = t.service.GetPlantLoadCurve(uint(1), time.Date(2022, 10, 29, 23, 30, 0, 0, time.UTC), time.Date(2022, 10, 30, 2, 0, 0, 0, time.UTC), IntervalHour)
+    t.Require().NoError(err)
+    t.Require().Len(curve.Buckets, 3)
+    t.Equal(curve.Buckets[1].StartsAt.Hour(), curve.Buckets[2].StartsAt.Hour())
+    for _, bucket := range curve.Buckets {
+        t.Equal(1, bucket.Readings)
+        t.Equal(time.Hour, bucket.EndsAt.Sub(bucket.StartsAt))
+    }
+
+    // weeks start on Monday, months on the first, both in local time
+    curve, err = t.service.GetPlantLoadCurve(uint(1), time.Date(2022, 3, 26, 12, 0, 0, 0, time.UTC), time.Date(2022, 3, 26, 13, 0, 0, 0, time.UTC), IntervalWeek)
+    t.Require().NoError(err)
+    t.True(curve.Buckets[0].StartsAt.Equal(time.Date(2022, 3, 20, 23, 0, 0, 0, time.UTC)))
+    t.True(curve.Buckets[0].EndsAt.Equal(time.Date(2022, 3, 27, 22, 0, 0, 0, time.UTC)))
+    curve, err = t.service.GetPlantLoadCurve(uint(1), time.Date(2022, 3, 26, 12, 0, 0, 0, time.UTC), time.Date(2022, 3, 26, 13, 0, 0, 0, time.UTC), IntervalMonth)
+    t.Require().NoError(err)
+    t.True(curve.Buckets[0].StartsAt.Equal(time.Date(2022, 2, 28, 23, 0, 0, 0, time.UTC)))
+    t.True(curve.Buckets[0].EndsAt.Equal(time.Date(2022, 3, 31, 22, 0, 0, 0, time.UTC)))
+
+    // a daily maintenance at 08:00 local time stays at 08:00 across DST
+    err = t.service.CreateAssetMaintenanceWindow(uint(1), uint(1), CreateMaintenanceWindowInput{
+        StartsAt: time.Date(2022, 3, 25, 7, 0, 0, 0, time.UTC),
+        EndsAt: time.Date(2022, 3, 25, 8, 0, 0, 0, time.UTC),
+        RRule: "FREQ=DAILY",
+    })
+    t.Require().NoError(err)
+    schedule, err := t.service.GetAssetMaintenanceSchedule(uint(1), uint(1), time.Date(2022, 3, 26, 0, 0, 0, 0, time.UTC), time.Date(2022, 3, 28, 0, 0, 0, 0, time.UTC))
+    t.Require().NoError(err)
+    t.Require().Len(schedule, 2)
+    t.True(schedule[0].StartsAt.Equal(time.Date(2022, 3, 26, 7, 0, 0, 0, time.UTC)))
+    t.True(schedule[1].StartsAt.Equal(time.Date(2022, 3, 27, 6, 0, 0, 0, time.UTC)))
+    availability, err := t.service.GetAssetAvailability(uint(1), uint(1), time.Date(2022, 3, 28, 6, 30, 0, 0, time.UTC), time.Date(2022, 3, 28, 6, 45, 0, 0, time.UTC))
+    t.Require().NoError(err)
+    t.False(availability.Available)
+    availability, err = t.service.GetAssetAvailability(uint(1), uint(1), time.Date(2022, 3, 28, 7, 15, 0, 0, time.UTC), time.Date(2022, 3, 28, 7, 45, 0, 0, time.UTC))
+    t.Require().NoError(err)
+    t.True(availability.Available)
+}
+
+func (t *MainTestSuite) TestPlantCosts() {
+    weekdays := []string{"mon", "tue", "wed", "thu", "fri"}
+    // periods must cover the whole year
+    err := t.service.CreateTariff(CreateTariffInput{
+        Name: "green",
+        Periods: []TariffPeriodInput{
+            {Name: "peak", Days: weekdays, StartTime: "08:00", EndTime: "20:00", EnergyPrice: 0.2, DemandPrice: 10},
+        },
+    })
+    t.Require().ErrorIs(err, ErrTariffCoverage)
+    err = t.service.CreateTariff(CreateTariffInput{
+        Name: "green",
+        Periods: []TariffPeriodInput{
+            {Name: "peak", Days: weekdays, StartTime: "08:00", EndTime: "20:00", EnergyPrice: 0.2, DemandPrice: 10},
+            {Name: "off-peak", Days: weekdays, StartTime: "20:00", EndTime: "08:00", EnergyPrice: 0.1},
+            {Name: "week-end", Days: []string{"sat", "sun"}, StartTime: "00:00", EndTime: "24:00", EnergyPrice: 0.1},
+        },
+    })
+    t.Require().NoError(err)
+    tariff, err := t.service.GetTariff(uint(1))
+    t.Require().NoError(err)
+    t.Equal("EUR", tariff.Currency)
+    t.Len(tariff.Periods, 3)
+
+    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    missing := uint(2)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+        Timezone: "Europe/Paris",
+        TariffID: &missing,
+    })
+    t.Require().ErrorIs(err, ErrPlantTariff)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+        Timezone: "Europe/Paris",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(100), Type: "furnace"})
+    t.Require().NoError(err)
+
+    // Monday 2 May 2022, from 07:00 to 10:00 local time
+    from := time.Date(2022, 5, 2, 5, 0, 0, 0, time.UTC)
+    to := from.Add(3 * time.Hour)
+    _, err = t.service.GetPlantCosts(uint(1), from, to)
+    t.Require().ErrorIs(err, ErrPlantNoTariff)
+
+    tariff_id := uint(1)
+    err = t.service.UpdatePlant(uint(1), UpdatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+        Timezone: "Europe/Paris",
+        TariffID: &tariff_id,
+    })
+    t.Require().NoError(err)
+
+    asset_id := uint(1)
+    err = t.service.CreateMeasurements(uint(1), CreateMeasurementsInput{Measurements: []MeasurementInput{
+        {Timestamp: from, Power: 100},
+        {Timestamp: from.Add(time.Hour), Power: 200},
+        {Timestamp: from.Add(2 * time.Hour), Power: 300},
+        {AssetID: &asset_id, Timestamp: from, Power: 50},
+        {AssetID: &asset_id, Timestamp: from.Add(time.Hour), Power: 50},
+        {AssetID: &asset_id, Timestamp: from.Add(2 * time.Hour), Power: 50},
+    }})
+    t.Require().NoError(err)
+
+    costs, err := t.service.GetPlantCosts(uint(1), from, to)
+    t.Require().NoError(err)
+    t.Equal("EUR", costs.Currency)
+    t.InDelta(600, costs.Energy, 1e-9)
+    // 100 kWh off-peak then 500 kWh at peak
+    t.InDelta(110, costs.EnergyCost, 1e-9)
+    // the 300 kW peak, for 3 hours of May
+    t.InDelta(300*10*3.0/744, costs.DemandCost, 1e-9)
+    t.InDelta(costs.EnergyCost+costs.DemandCost, costs.TotalCost, 1e-9)
+    t.Require().Len(costs.Periods, 3)
+    t.Equal("peak", costs.Periods[0].Name)
+    t.InDelta(500, costs.Periods[0].Energy, 1e-9)
+    t.InDelta(0, costs.Periods[2].Energy, 1e-9)
+    t.Require().Len(costs.Assets, 1)
+    t.InDelta(150, costs.Assets[0].Energy, 1e-9)
+    t.InDelta(50*0.1+100*0.2, costs.Assets[0].EnergyCost, 1e-9)
+    t.InDelta(50*10*3.0/744, costs.Assets[0].DemandCost, 1e-9)
+
+    // deleting the tariff detaches it from the plant
+    err = t.service.DeleteTariff(uint(1))
+    t.Require().NoError(err)
+    _, err = t.service.GetPlantCosts(uint(1), from, to)
+    t.Require().ErrorIs(err, ErrPlantNoTariff)
+}
+
+func (t *MainTestSuite) TestEmissions() {
+    factor := 100.0
+    err := t.service.SetStaticEmissionFactor("FR", SetEmissionFactorInput{Factor: &factor})
+    t.Require().NoError(err)
+    factors, err := t.service.GetStaticEmissionFactors()
+    t.Require().NoError(err)
+    t.Require().Len(factors, 1)
+    t.Equal(100.0, factors[0].Factor)
+
+    err = t.service.ImportEmissionFactors("FR", strings.NewReader("timestamp,factor\n2022-05-02T14:30:00Z,50\n"))
+    t.Require().ErrorIs(err, ErrEmissionFactorsCSV)
+    err = t.service.ImportEmissionFactors("FR", strings.NewReader("timestamp,factor\n2022-05-02T14:00:00Z,50\n2022-05-02T15:00:00Z,60\n"))
+    t.Require().NoError(err)
+    // importing again replaces the hours of the series
+    err = t.service.ImportEmissionFactors("FR", strings.NewReader("2022-05-02T15:00:00Z,40\n"))
+    t.Require().NoError(err)
+    hourly, err := t.service.GetHourlyEmissionFactors("FR", time.Date(2022, 5, 2, 0, 0, 0, 0, time.UTC), time.Date(2022, 5, 3, 0, 0, 0, 0, time.UTC))
+    t.Require().NoError(err)
+    t.Require().Len(hourly, 2)
+    t.Equal(40.0, hourly[1].Factor)
+
+    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    for _, country_code := range []string{"FR", "BE"} {
+        err = t.service.CreatePlant(CreatePlantInput{
+            Name: "plant",
+            Address: "17 rue truc",
+            MaxPower: models.Kilowatts(1000),
+            EnergyManagerID: 1,
+            CountryCode: country_code,
+        })
+        t.Require().NoError(err)
+    }
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(100), Type: "furnace"})
+    t.Require().NoError(err)
+
+    // 100 kW on both plants from 13:00 to 16:00, 50 kW of it by the furnace
+    from := time.Date(2022, 5, 2, 13, 0, 0, 0, time.UTC)
+    to := from.Add(3 * time.Hour)
+    asset_id := uint(1)
+    readings := []MeasurementInput{}
+    for h := 0; h < 3; h++ {
+        at := from.Add(time.Duration(h) * time.Hour)
+        readings = append(readings, MeasurementInput{Timestamp: at, Power: 100}, MeasurementInput{AssetID: &asset_id, Timestamp: at, Power: 50})
+    }
+    err = t.service.CreateMeasurements(uint(1), CreateMeasurementsInput{Measurements: readings})
+    t.Require().NoError(err)
+    err = t.service.CreateMeasurements(uint(2), CreateMeasurementsInput{Measurements: []MeasurementInput{{Timestamp: from, Power: 100}}})
+    t.Require().NoError(err)
+
+    emissions, err := t.service.GetPlantEmissions(uint(1), from, to)
+    t.Require().NoError(err)
+    t.InDelta(300, emissions.Energy, 1e-9)
+    // the static factor at 13:00, then the hourly ones
+    t.InDelta((100*100+100*50+100*40)/1000.0, emissions.Emissions, 1e-9)
+    t.InDelta(0, emissions.UnknownEnergy, 1e-9)
+    t.Require().Len(emissions.Assets, 1)
+    t.InDelta((50*100+50*50+50*40)/1000.0, emissions.Assets[0].Emissions, 1e-9)
+
+    // there is no factor for Belgium
+    rollup, err := t.service.GetEnergyManagerEmissions(uint(1), "", from, to)
+    t.Require().NoError(err)
+    t.Require().Len(rollup.Plants, 2)
+    t.InDelta(400, rollup.Energy, 1e-9)
+    t.InDelta(emissions.Emissions, rollup.Emissions, 1e-9)
+    t.InDelta(100, rollup.UnknownEnergy, 1e-9)
+
+    _, err = t.service.GetEnergyManagerEmissions(uint(2), "", from, to)
+    t.Require().ErrorIs(err, ErrEmptyResult)
+}
+
+func (t *MainTestSuite) TestGridConnections() {
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(600), Type: "furnace"})
+    t.Require().NoError(err)
+
+    // a plant without any connection only gets a warning
+    compliance, err := t.service.GetPlantCompliance(uint(1), time.Now().Add(-time.Hour), time.Now())
+    t.Require().NoError(err)
+    t.True(compliance.Compliant)
+    t.Require().Len(compliance.Issues, 1)
+    t.Equal(IssueNoConnection, compliance.Issues[0].Code)
+
+    // the contract was upgraded on 1 May 2022
+    upgrade := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+    first := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+    err = t.service.CreateConnection(uint(1), CreateConnectionInput{
+        ConnectionPointID: "30001234567890",
+        VoltageLevel: "hv",
+        ImportCapacity: models.Kilowatts(400),
+        ValidFrom: &first,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateConnection(uint(1), CreateConnectionInput{
+        ConnectionPointID: "30001234567890",
+        VoltageLevel: "mv",
+        ImportCapacity: models.Kilowatts(800),
+        ExportCapacity: models.Kilowatts(100),
+        ValidFrom: &upgrade,
+    })
+    t.Require().ErrorIs(err, ErrConnectionOverlap)
+    err = t.service.UpdatePlantConnection(uint(1), uint(1), UpdateConnectionInput{
+        ConnectionPointID: "30001234567890",
+        VoltageLevel: "mv",
+        ImportCapacity: models.Kilowatts(400),
+        ValidFrom: first,
+        ValidTo: &upgrade,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateConnection(uint(1), CreateConnectionInput{
+        ConnectionPointID: "30001234567890",
+        VoltageLevel: "xv",
+        ImportCapacity: models.Kilowatts(800),
+        ValidFrom: &upgrade,
+    })
+    t.Require().ErrorIs(err, ErrConnectionVoltage)
+    err = t.service.CreateConnection(uint(1), CreateConnectionInput{
+        ConnectionPointID: "30001234567890",
+        VoltageLevel: "mv",
+        ImportCapacity: models.Kilowatts(800),
+        ExportCapacity: models.Kilowatts(100),
+        ValidFrom: &upgrade,
+    })
+    t.Require().NoError(err)
+    connections, err := t.service.GetPlantConnections(uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(connections, 2)
+    t.Equal(int64(400000), connections[0].ImportCapacity.Watts())
+    t.Equal(int64(800000), connections[1].ImportCapacity.Watts())
+
+    // 500 kW was above the old contract only, then 120 kW were exported
+    err = t.service.CreateMeasurements(uint(1), CreateMeasurementsInput{Measurements: []MeasurementInput{
+        {Timestamp: time.Date(2022, 4, 30, 12, 0, 0, 0, time.UTC), Power: 500},
+        {Timestamp: time.Date(2022, 4, 30, 13, 0, 0, 0, time.UTC), Power: 450},
+        {Timestamp: time.Date(2022, 5, 2, 12, 0, 0, 0, time.UTC), Power: 500},
+        {Timestamp: time.Date(2022, 5, 2, 13, 0, 0, 0, time.UTC), Power: -120},
+    }})
+    t.Require().NoError(err)
+
+    compliance, err = t.service.GetPlantCompliance(uint(1), first, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC))
+    t.Require().NoError(err)
+    t.False(compliance.Compliant)
+    t.Require().NotNil(compliance.Connection)
+    t.Equal(uint(2), compliance.Connection.ID)
+    t.Equal(int64(600000), compliance.AssetPower.Watts())
+    t.Require().Len(compliance.Issues, 3)
+    // the plant could draw 1000 kW out of 800 kW
+    t.Equal(ComplianceWarning, compliance.Issues[0].Level)
+    t.Equal(IssuePlantPowerAboveImport, compliance.Issues[0].Code)
+    t.Equal(ComplianceError, compliance.Issues[1].Level)
+    t.Equal(IssueImportPeakAboveCapacity, compliance.Issues[1].Code)
+    t.Equal(uint(1), *compliance.Issues[1].ConnectionID)
+    t.Equal(int64(500000), compliance.Issues[1].Value.Watts())
+    t.True(compliance.Issues[1].At.Equal(time.Date(2022, 4, 30, 12, 0, 0, 0, time.UTC)))
+    t.Equal(IssueExportPeakAboveCapacity, compliance.Issues[2].Code)
+    t.Equal(uint(2), *compliance.Issues[2].ConnectionID)
+    t.Equal(int64(120000), compliance.Issues[2].Value.Watts())
+}
+
+// countingDB counts the batched reads of the loaders.
+type countingDB struct {
+    DB
+    reads map[string]int
+}
+
+func (c *countingDB) GetCurrentAssignmentsByEnergyManagerIds(ids []uint, role string, at time.Time) ([]models.PlantAssignment, error) {
+    c.reads["em plants"]++
+    return c.DB.GetCurrentAssignmentsByEnergyManagerIds(ids, role, at)
+}
+
+func (c *countingDB) GetCurrentAssignmentsByPlantIds(ids []uint, role string, at time.Time) ([]models.PlantAssignment, error) {
+    c.reads["plant ems"]++
+    return c.DB.GetCurrentAssignmentsByPlantIds(ids, role, at)
+}
+
+func (c *countingDB) GetAssetsByPlantIds(ids []uint) ([]models.Asset, error) {
+    c.reads["plant assets"]++
+    return c.DB.GetAssetsByPlantIds(ids)
+}
+
+func (t *MainTestSuite) TestLoaders() {
+    for _, name := range []string{"Gerard", "Catherine"} {
+        err := t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: name, Surname: "Depardieu"})
+        t.Require().NoError(err)
+    }
+    for _, em_id := range []uint{1, 2, 1} {
+        err := t.service.CreatePlant(CreatePlantInput{
+            Name: "plant",
+            Address: "17 rue truc",
+            MaxPower: models.Kilowatts(1000),
+            EnergyManagerID: em_id,
+        })
+        t.Require().NoError(err)
+    }
+    err := t.service.CreateAssignment(uint(1), CreateAssignmentInput{EnergyManagerID: 2, Role: AssignmentBackup})
+    t.Require().NoError(err)
+    for _, plant_id := range []uint{1, 3, 3} {
+        err = t.service.CreateAsset(plant_id, CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(100), Type: "furnace"})
+        t.Require().NoError(err)
+    }
+
+    db := &countingDB{DB: t.service.DB, reads: map[string]int{}}
+    t.service.DB = db
+    loaders := t.service.NewLoaders()
+    ems, err := t.service.GetAllEnergyManagers()
+    t.Require().NoError(err)
+    loaders.AddEnergyManagers(ems)
+
+    // the plants of every energy manager are read at once
+    plants, err := loaders.EnergyManagerPlants(uint(1), "")
+    t.Require().NoError(err)
+    t.Require().Len(plants, 2)
+    t.Equal(uint(1), plants[0].ID)
+    t.Equal(uint(3), plants[1].ID)
+    loaders.AddPlants(plants)
+    plants, err = loaders.EnergyManagerPlants(uint(2), "")
+    t.Require().NoError(err)
+    t.Require().Len(plants, 2)
+    loaders.AddPlants(plants)
+    t.Equal(1, db.reads["em plants"])
+    plants, err = loaders.EnergyManagerPlants(uint(2), AssignmentPrimary)
+    t.Require().NoError(err)
+    t.Require().Len(plants, 1)
+    t.Equal(uint(2), plants[0].ID)
+    _, err = loaders.EnergyManagerPlants(uint(2), "owner")
+    t.Require().ErrorIs(err, ErrAssignmentRole)
+
+    // and so are the assets and energy managers of every plant
+    for plant_id, count := range map[uint]int{1: 1, 2: 0, 3: 2} {
+        assets, err := loaders.PlantAssets(plant_id)
+        t.Require().NoError(err)
+        t.Len(assets, count)
+    }
+    t.Equal(1, db.reads["plant assets"])
+    for plant_id, count := range map[uint]int{1: 2, 2: 1, 3: 1} {
+        ems, err := loaders.PlantEnergyManagers(plant_id, "")
+        t.Require().NoError(err)
+        t.Len(ems, count)
+    }
+    t.Equal(1, db.reads["plant ems"])
+}
+
+func (t *MainTestSuite) TestEvents() {
+    last, err := t.service.LastEventID()
+    t.Require().NoError(err)
+    t.Equal(uint(0), last)
+
+    for _, name := range []string{"Gerard", "Catherine"} {
+        err = t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: name, Surname: "Depardieu"})
+        t.Require().NoError(err)
+    }
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(300), Type: "furnace"})
+    t.Require().NoError(err)
+    last, err = t.service.LastEventID()
+    t.Require().NoError(err)
+    t.Equal(uint(4), last)
+
+    // every event, oldest first
+    events, err := t.service.WaitEvents(context.Background(), EventFilter{}, 0, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 4)
+    t.Equal(EventEmCreated, events[0].Type)
+    t.Equal(EventAssetCreated, events[3].Type)
+
+    // resuming after the last one seen
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{}, 2, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 2)
+    t.Equal(uint(3), events[0].ID)
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{}, 4, 0)
+    t.Require().NoError(err)
+    t.Len(events, 0)
+
+    // the events of a plant, of an entity type, of an energy manager and its plants
+    plant_id := uint(1)
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{PlantID: &plant_id}, 0, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 2)
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{EntityTypes: []string{EntityEm, EntityAsset}}, 0, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 3)
+    em_id := uint(1)
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{EnergyManagerID: &em_id}, 0, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 3)
+    em_id = uint(2)
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{EnergyManagerID: &em_id}, 0, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 1)
+
+    // a waiting stream gets the next event
+    go func() {
+        time.Sleep(50 * time.Millisecond)
+        t.service.DeletePlantAsset(uint(1), uint(1))
+    }()
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{PlantID: &plant_id}, 4, 5*time.Second)
+    t.Require().NoError(err)
+    t.Require().Len(events, 1)
+    t.Equal(EventAssetDeleted, events[0].Type)
+    t.JSONEq(`{"id": 1, "plant_id": 1}`, string(events[0].Data))
+
+    err = t.service.CheckEventFilter(EventFilter{EntityTypes: []string{"tariff"}})
+    t.Require().ErrorIs(err, ErrEventEntityType)
+    plant_id = uint(2)
+    err = t.service.CheckEventFilter(EventFilter{PlantID: &plant_id})
+    t.Require().ErrorIs(err, ErrEmptyResult)
+}
+
+// fakeSink records the messages published to it, and fails those of a key.
+// It takes delay to publish each message.
+type fakeSink struct {
+    mu       sync.Mutex
+    attempts []string
+    failing  string
+    delay    time.Duration
+}
+
+func (f *fakeSink) Publish(ctx context.Context, msg outbox.Message) error {
+    time.Sleep(f.delay)
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.attempts = append(f.attempts, msg.Type + " " + msg.Key)
+    if msg.Key == f.failing {
+        return errors.New("broker unavailable")
+    }
+    return nil
+}
+
+func (t *MainTestSuite) TestOutbox() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+    broker := &fakeSink{failing: "plant:1"}
+    t.service.Sinks["broker"] = broker
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: "Gerard", Surname: "Depardieu"})
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.UpdatePlant(uint(1), UpdatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(2000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+
+    // a failed message holds back the next ones of its aggregate only
+    t.service.relayOutbox(context.Background())
+    t.Equal([]string{"em.created em:1", "plant.created plant:1"}, broker.attempts)
+    t.service.relayOutbox(context.Background())
+    t.Len(broker.attempts, 2)
+
+    // once the broker is back, the retry is published then the next one
+    broker.failing = ""
+    now = now.Add(time.Minute)
+    t.service.relayOutbox(context.Background())
+    t.Equal([]string{
+        "em.created em:1",
+        "plant.created plant:1",
+        "plant.created plant:1",
+        "plant.updated plant:1",
+    }, broker.attempts)
+
+    // an event is only logged along with the change it comes from
+    err = t.service.transaction(func(tx DB) error {
+        if err := t.service.emit(tx, EventPlantDeleted, 1, nil, nil, deletedResource{ID: 1}); err != nil {
+            return err
+        }
+        return errors.New("rolled back")
+    })
+    t.Require().Error(err)
+    last, err := t.service.LastEventID()
+    t.Require().NoError(err)
+    t.Equal(uint(3), last)
+    t.service.relayOutbox(context.Background())
+    t.Len(broker.attempts, 4)
+
+    // the published messages are deleted once they are old enough
+    now = now.Add(outboxRetention - 30 * time.Second)
+    t.Require().NoError(t.service.purgeOutbox())
+    var published int64
+    t.Require().NoError(t.db.Model(&models.OutboxMessage{}).Where("status = ?", OutboxPublished).Count(&published).Error)
+    t.Equal(int64(2), published)
+}
+
+func (t *MainTestSuite) TestOutboxConcurrentRelays() {
+    broker := &fakeSink{delay: 50 * time.Millisecond}
+    t.service.Sinks["broker"] = broker
+    for i := 0; i < 4; i++ {
+        err := t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: "Gerard", Surname: "Depardieu"})
+        t.Require().NoError(err)
+    }
+    err := t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.UpdatePlant(uint(1), UpdatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(2000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+
+    // two relays share the messages, none is published twice
+    var wg sync.WaitGroup
+    for i := 0; i < 2; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            t.service.relayOutbox(context.Background())
+        }()
+    }
+    wg.Wait()
+    t.ElementsMatch([]string{
+        "em.created em:1",
+        "em.created em:2",
+        "em.created em:3",
+        "em.created em:4",
+        "plant.created plant:1",
+        "plant.updated plant:1",
+    }, broker.attempts)
+
+    // the messages of a resource are still published in order
+    var plant []string
+    for _, attempt := range broker.attempts {
+        if strings.HasSuffix(attempt, " plant:1") {
+            plant = append(plant, attempt)
+        }
+    }
+    t.Equal([]string{"plant.created plant:1", "plant.updated plant:1"}, plant)
+}
+
+func (t *MainTestSuite) TestEventsCommitOrder() {
+    // a slow transaction logs the first event, and keeps going
+    logged := make(chan bool)
+    release := make(chan bool)
+    slow := make(chan error, 1)
+    go func() {
+        slow <- t.service.transaction(func(tx DB) error {
+            if err := t.service.emit(tx, EventEmCreated, 1, nil, nil, deletedResource{ID: 1}); err != nil {
+                return err
+            }
+            logged <- true
+            <-release
+            return nil
+        })
+    }()
+    <-logged
+
+    // a quicker one has to wait for it to commit its event
+    quick := make(chan error, 1)
+    go func() {
+        quick <- t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: "Gerard", Surname: "Depardieu"})
+    }()
+    time.Sleep(100 * time.Millisecond)
+    events, err := t.service.WaitEvents(context.Background(), EventFilter{}, 0, 0)
+    t.Require().NoError(err)
+    t.Len(events, 0)
+
+    close(release)
+    t.Require().NoError(<-slow)
+    t.Require().NoError(<-quick)
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{}, 0, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 2)
+    t.Equal(EventEmCreated, events[0].Type)
+    t.JSONEq(`{"id": 1}`, string(events[0].Data))
+    t.True(events[0].ID < events[1].ID)
+}
+
+func (t *MainTestSuite) TestAlarmEvents() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: "Gerard", Surname: "Depardieu"})
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+    err = t.service.CreateAgent(CreateAgentInput{Name: "edge1", PlantID: 1})
+    t.Require().NoError(err)
+    agent_id := uint(1)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(300), Type: "furnace", AgentID: &agent_id})
+    t.Require().NoError(err)
+
+    // a curtailment that fails opens an alarm
+    err = t.service.CreateCurtailment(uint(1), CreateCurtailmentInput{
+        TargetPower: models.Kilowatts(100),
+        StartsAt: now.Add(time.Hour),
+        EndsAt: now.Add(2 * time.Hour),
+    })
+    t.Require().NoError(err)
+    for _, status := range []string{CurtailmentActive, CurtailmentFailed} {
+        err = t.service.UpdatePlantCurtailmentStatus(uint(1), uint(1), UpdateCurtailmentStatusInput{Status: status})
+        t.Require().NoError(err)
+    }
+    filter := EventFilter{EntityTypes: []string{EntityCurtailment, EntityAlarm}}
+    events, err := t.service.WaitEvents(context.Background(), filter, 0, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 4)
+    t.Equal(EventCurtailmentCreated, events[0].Type)
+    t.Equal(EventCurtailmentStatusChanged, events[1].Type)
+    t.Equal(EventCurtailmentStatusChanged, events[2].Type)
+    var changed struct {
+        From        string
+        Curtailment struct{ Status string }
+    }
+    t.Require().NoError(json.Unmarshal(events[2].Data, &changed))
+    t.Equal(CurtailmentActive, changed.From)
+    t.Equal(CurtailmentFailed, changed.Curtailment.Status)
+    t.Equal(EventAlarmOpened, events[3].Type)
+    t.JSONEq(`{"code": "curtailment_failed", "source": "curtailment", "source_id": 1, "plant_id": 1}`, string(events[3].Data))
+
+    // and so does a command rejected by its agent, or never acknowledged
+    for _, setpoint := range []uint{100, 200} {
+        err = t.service.CreateAssetCommand(uint(1), uint(1), CreateCommandInput{Setpoint: models.Kilowatts(setpoint)})
+        t.Require().NoError(err)
+    }
+    commands, err := t.service.FetchAgentCommands(context.Background(), agent_id, 0)
+    t.Require().NoError(err)
+    t.Require().Len(commands, 2)
+    err = t.service.AcknowledgeAgentCommand(agent_id, commands[0].ID)
+    t.Require().NoError(err)
+    err = t.service.RejectAgentCommand(agent_id, commands[1].ID, RejectCommandInput{Reason: "furnace too hot"})
+    t.Require().NoError(err)
+    last := events[3].ID
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{EntityTypes: []string{EntityAlarm}}, last, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 1)
+    t.JSONEq(`{"code": "command_rejected", "source": "command", "source_id": 2, "plant_id": 1, "asset_id": 1, "reason": "furnace too hot"}`, string(events[0].Data))
+
+    err = t.service.CreateAssetCommand(uint(1), uint(1), CreateCommandInput{Setpoint: models.Kilowatts(100)})
+    t.Require().NoError(err)
+    for i := 0; i < CommandMaxAttempts; i++ {
+        _, err = t.service.FetchAgentCommands(context.Background(), agent_id, 0)
+        t.Require().NoError(err)
+        now = now.Add(CommandAckTimeout)
+    }
+    // the agent gone, the sweep expires it all the same
+    t.Require().NoError(t.service.sweepCommands())
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{EntityTypes: []string{EntityAlarm}}, events[0].ID, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 1)
+    t.JSONEq(`{"code": "command_expired", "source": "command", "source_id": 3, "plant_id": 1, "asset_id": 1, "reason": "acknowledgement timed out"}`, string(events[0].Data))
+
+    // deleting the curtailment is an event too
+    err = t.service.DeletePlantCurtailment(uint(1), uint(1))
+    t.Require().NoError(err)
+    events, err = t.service.WaitEvents(context.Background(), EventFilter{EntityTypes: []string{EntityCurtailment}}, events[0].ID, 0)
+    t.Require().NoError(err)
+    t.Require().Len(events, 1)
+    t.Equal(EventCurtailmentDeleted, events[0].Type)
+}
+
+func (t *MainTestSuite) TestMoveAsset() {
+    err := t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    for _, max_power := range []uint{1000, 500} {
+        err = t.service.CreatePlant(CreatePlantInput{
+            Name: "plant",
+            Address: "17 rue truc",
+            MaxPower: models.Kilowatts(max_power),
+            EnergyManagerID: 1,
+        })
+        t.Require().NoError(err)
+    }
+    err = t.service.CreateAgent(CreateAgentInput{Name: "edge1", PlantID: 1})
+    t.Require().NoError(err)
+    err = t.service.CreateGroup(uint(2), CreateGroupInput{Name: "line1", Kind: "line", MaxPower: models.Kilowatts(400)})
+    t.Require().NoError(err)
+    agent_id := uint(1)
+    err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(300), Type: "furnace", AgentID: &agent_id})
+    t.Require().NoError(err)
+    err = t.service.CreateAsset(uint(2), CreateAssetInput{Name: "chiller", MaxPower: models.Kilowatts(250), Type: "chiller"})
+    t.Require().NoError(err)
+    err = t.service.CreateAssetCommand(uint(1), uint(1), CreateCommandInput{Setpoint: models.Kilowatts(100)})
+    t.Require().NoError(err)
+
+    // the destination must be another existing plant with room for the asset
+    err = t.service.MovePlantAsset(uint(1), uint(1), MoveAssetInput{PlantID: 1})
+    t.Require().ErrorIs(err, ErrAssetMoveDestination)
+    err = t.service.MovePlantAsset(uint(1), uint(1), MoveAssetInput{PlantID: 3})
+    t.Require().ErrorIs(err, ErrAssetMoveDestination)
+    err = t.service.MovePlantAsset(uint(2), uint(1), MoveAssetInput{PlantID: 1})
+    t.Require().ErrorIs(err, ErrEmptyResult)
+    err = t.service.MovePlantAsset(uint(1), uint(1), MoveAssetInput{PlantID: 2})
+    t.Require().ErrorIs(err, ErrAssetPower)
+
+    err = t.service.DeletePlantAsset(uint(2), uint(2))
+    t.Require().NoError(err)
+    group_id := uint(1)
+    err = t.service.MovePlantAsset(uint(1), uint(1), MoveAssetInput{PlantID: 2, GroupID: &group_id, Reason: "relocated"})
+    t.Require().NoError(err)
+
+    _, err = t.service.GetPlantAsset(uint(1), uint(1))
+    t.Require().ErrorIs(err, ErrEmptyResult)
+    asset, err := t.service.GetPlantAsset(uint(2), uint(1))
+    t.Require().NoError(err)
+    t.Nil(asset.AgentID)
+    t.Require().NotNil(asset.GroupID)
+    t.Equal(group_id, *asset.GroupID)
+
+    // the command the asset never received is cancelled, its history kept
+    commands, err := t.service.GetAssetCommands(uint(2), uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(commands, 1)
+    t.Equal(CommandCancelled, commands[0].Status)
+
+    moves, err := t.service.GetPlantAssetMoves(uint(2), uint(1))
+    t.Require().NoError(err)
+    t.Require().Len(moves, 1)
+    t.Equal(uint(1), moves[0].FromPlantID)
+    t.Equal(uint(2), moves[0].ToPlantID)
+    t.Nil(moves[0].FromGroupID)
+    t.Equal("relocated", moves[0].Reason)
+
+    // both plants see the asset go
+    for _, plant_id := range []uint{1, 2} {
+        filter := EventFilter{PlantID: &plant_id, EntityTypes: []string{EntityAsset}}
+        events, err := t.service.WaitEvents(context.Background(), filter, 0, 0)
+        t.Require().NoError(err)
+        moved := events[len(events)-1]
+        t.Equal(EventAssetMoved, moved.Type)
+        var data struct {
+            FromPlantID uint `json:"from_plant_id"`
+            Asset       struct {
+                PlantID uint `json:"plant_id"`
+            } `json:"asset"`
+        }
+        t.Require().NoError(json.Unmarshal(moved.Data, &data))
+        t.Equal(uint(1), data.FromPlantID)
+        t.Equal(uint(2), data.Asset.PlantID)
+    }
+
+    // the destination has room for one of two assets moved to it at once
+    for _, name := range []string{"press", "oven"} {
+        err = t.service.CreateAsset(uint(1), CreateAssetInput{Name: name, MaxPower: models.Kilowatts(150), Type: "press"})
+        t.Require().NoError(err)
+    }
+    errs := make(chan error, 2)
+    for _, asset_id := range []uint{3, 4} {
+        go func(asset_id uint) {
+            errs <- t.service.MovePlantAsset(uint(1), asset_id, MoveAssetInput{PlantID: 2})
+        }(asset_id)
+    }
+    first, second := <-errs, <-errs
+    if first != nil {
+        first, second = second, first
+    }
+    t.NoError(first)
+    t.ErrorIs(second, ErrAssetPower)
+    assets, err := t.service.GetPlantAssets(uint(2))
+    t.Require().NoError(err)
+    t.Len(assets, 2)
+}
+
+func (t *MainTestSuite) TestWebhooks() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    t.service.now = func() time.Time { return now }
+    t.service.Webhooks = webhooks.NewClient()
+
+    // a CMMS receiving every event and a billing system down for now
+    received := make(chan dto.EventMessage, 10)
+    cmms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        payload, _ := io.ReadAll(r.Body)
+        if err := webhooks.Verify("cmms-secret", r.Header.Get(webhooks.SignatureHeader), payload, time.Now(), time.Minute); err != nil {
+            w.WriteHeader(http.StatusUnauthorized)
+            return
+        }
+        var event dto.EventMessage
+        json.Unmarshal(payload, &event)
+        received <- event
+    }))
+    defer cmms.Close()
+    billing_status := http.StatusServiceUnavailable
+    billing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(billing_status)
+    }))
+    defer billing.Close()
+
+    err := t.service.CreateWebhookSubscription(CreateWebhookSubscriptionInput{URL: "ftp://cmms", Secret: "cmms-secret"})
+    t.Require().ErrorIs(err, ErrWebhookURL)
+    err = t.service.CreateWebhookSubscription(CreateWebhookSubscriptionInput{URL: cmms.URL, Secret: "cmms-secret", Events: []string{"alarm.closed"}})
+    t.Require().ErrorIs(err, ErrWebhookEvent)
+    err = t.service.CreateWebhookSubscription(CreateWebhookSubscriptionInput{URL: cmms.URL, Secret: "cmms-secret"})
+    t.Require().NoError(err)
+    err = t.service.CreateWebhookSubscription(CreateWebhookSubscriptionInput{
+        URL: billing.URL,
+        Secret: "billing-secret",
+        Events: []string{EventPlantCreated, EventPlantUpdated},
+    })
+    t.Require().NoError(err)
+
+    err = t.service.CreateEnergyManager(CreateEnergyManagerInput{
+        Name: "Gerard",
+        Surname: "Depardieu",
+    })
+    t.Require().NoError(err)
+    err = t.service.CreatePlant(CreatePlantInput{
+        Name: "plant1",
+        Address: "17 rue truc",
+        MaxPower: models.Kilowatts(1000),
+        EnergyManagerID: 1,
+    })
+    t.Require().NoError(err)
+
+    t.service.relayOutbox(context.Background())
+    t.service.deliverDueWebhooks(context.Background())
+    event := <-received
+    t.Equal(EventEmCreated, event.Type)
+    var em dto.EnergyManager
+    t.Require().NoError(json.Unmarshal(event.Data, &em))
+    t.Equal("Gerard", em.Name)
+    event = <-received
+    t.Equal(EventPlantCreated, event.Type)
+    t.True(event.OccurredAt.Equal(now))
+
+    // the billing system is retried later and later, then given up on
+    deliveries, err := t.service.GetWebhookDeliveries(uint(2), WebhookDeliveryPending)
+    t.Require().NoError(err)
+    t.Require().Len(deliveries, 1)
+    t.Equal(uint(1), deliveries[0].Attempts)
+    t.True(deliveries[0].NextAttemptAt.Equal(now.Add(30 * time.Second)))
+    t.Contains(deliveries[0].LastError, "503")
+    for i := 1; i < maxWebhookAttempts; i++ {
+        now = now.Add(time.Hour)
+        t.service.deliverDueWebhooks(context.Background())
+    }
+    deliveries, err = t.service.GetWebhookDeliveries(uint(2), WebhookDeliveryDead)
+    t.Require().NoError(err)
+    t.Require().Len(deliveries, 1)
+    t.Equal(uint(maxWebhookAttempts), deliveries[0].Attempts)
+    t.Equal(EventPlantCreated, deliveries[0].Event)
+    _, err = t.service.GetWebhookDeliveries(uint(2), "lost")
+    t.Require().ErrorIs(err, ErrWebhookDeliveryStatus)
+
+    // once fixed, the dead delivery is sent again
+    billing_status = http.StatusOK
+    err = t.service.RedeliverWebhook(uint(2), deliveries[0].ID)
+    t.Require().NoError(err)
+    t.service.deliverDueWebhooks(context.Background())
+    deliveries, err = t.service.GetWebhookDeliveries(uint(2), "")
+    t.Require().NoError(err)
+    t.Require().Len(deliveries, 1)
+    t.Equal(WebhookDeliveryDelivered, deliveries[0].Status)
+    t.NotNil(deliveries[0].DeliveredAt)
+
+    // deleted resources are given by their ids
+    err = t.service.DeletePlant(uint(1))
+    t.Require().NoError(err)
+    t.service.relayOutbox(context.Background())
+    t.service.deliverDueWebhooks(context.Background())
+    event = <-received
+    t.Equal(EventPlantDeleted, event.Type)
+    t.JSONEq(`{"id": 1}`, string(event.Data))
+    deliveries, err = t.service.GetWebhookDeliveries(uint(2), "")
+    t.Require().NoError(err)
+    t.Require().Len(deliveries, 1)
+}
+
+func (t *MainTestSuite) TestIncludes() {
+    for _, name := range []string{"Gerard", "Catherine"} {
+        err := t.service.CreateEnergyManager(CreateEnergyManagerInput{Name: name, Surname: "Depardieu"})
+        t.Require().NoError(err)
+    }
+    for _, em_id := range []uint{1, 1, 2} {
+        err := t.service.CreatePlant(CreatePlantInput{
+            Name: "plant",
+            Address: "17 rue truc",
+            MaxPower: models.Kilowatts(1000),
+            EnergyManagerID: em_id,
+        })
+        t.Require().NoError(err)
+    }
+    for _, plant_id := range []uint{1, 1, 2} {
+        err := t.service.CreateAsset(plant_id, CreateAssetInput{Name: "furnace", MaxPower: models.Kilowatts(100), Type: "furnace"})
+        t.Require().NoError(err)
+    }
+
+    // nested relationships include the ones they go through
+    include, err := ParseInclude("plants.assets")
+    t.Require().NoError(err)
+    t.Equal(Include{"plants": true, "plants.assets": true}, include)
+    em, err := t.service.GetEnergyManagerIncluding(uint(1), include)
+    t.Require().NoError(err)
+    t.Require().Len(em.Plants, 2)
+    t.Len(em.Plants[0].Assets, 2)
+    t.Len(em.Plants[1].Assets, 1)
+    include, _ = ParseInclude("plants")
+    em, err = t.service.GetEnergyManagerIncluding(uint(2), include)
+    t.Require().NoError(err)
+    t.Require().Len(em.Plants, 1)
+    t.Nil(em.Plants[0].Assets)
+
+    include, _ = ParseInclude("assets, energy_manager.plants")
+    plant, err := t.service.GetPlantIncluding(uint(2), include)
+    t.Require().NoError(err)
+    t.Len(plant.Assets, 1)
+    t.Require().NotNil(plant.EnergyManager)
+    t.Equal(uint(1), plant.EnergyManager.ID)
+    t.Len(plant.EnergyManager.Plants, 2)
+    include, _ = ParseInclude("")
+    plant, err = t.service.GetPlantIncluding(uint(2), include)
+    t.Require().NoError(err)
+    t.Nil(plant.Assets)
+    t.Nil(plant.EnergyManager)
+
+    // unknown or too deep relationships
+    _, err = ParseInclude("plants.assets.commands")
+    t.ErrorIs(err, ErrIncludeDepth)
+    _, err = ParseInclude("plants..assets")
+    t.ErrorIs(err, ErrInclude)
+    include, _ = ParseInclude("assets")
+    _, err = t.service.GetEnergyManagerIncluding(uint(1), include)
+    t.ErrorIs(err, ErrInclude)
+    _, err = t.service.GetPlantIncluding(uint(4), include)
+    t.ErrorIs(err, ErrEmptyResult)
+
+    // too many resources
+    for i := 0; i < maxIncluded; i++ {
+        err := t.service.CreateAsset(uint(3), CreateAssetInput{Name: "chiller", MaxPower: models.Watts(1), Type: "chiller"})
+        t.Require().NoError(err)
+    }
+    _, err = t.service.GetPlantIncluding(uint(3), include)
+    t.ErrorIs(err, ErrIncludeSize)
+}
+
+func (t *MainTestSuite) TestIdempotencyKeys() {
+    record, err := t.service.StartIdempotentRequest("key", "hash")
+    t.Require().NoError(err)
+    t.Equal(0, record.Status)
+
+    // the key is taken until the request is handled
+    _, err = t.service.StartIdempotentRequest("key", "hash")
+    t.ErrorIs(err, ErrIdempotencyKeyInProgress)
+    _, err = t.service.StartIdempotentRequest("key", "other")
+    t.ErrorIs(err, ErrIdempotencyKeyReuse)
+
+    err = t.service.CompleteIdempotentRequest(record, 201, "application/json", []byte(`{"ID": 1}`))
+    t.Require().NoError(err)
+    previous, err := t.service.StartIdempotentRequest("key", "hash")
+    t.Require().NoError(err)
+    t.Equal(201, previous.Status)
+    t.Equal("application/json", previous.ContentType)
+    t.Equal(`{"ID": 1}`, string(previous.Body))
+    _, err = t.service.StartIdempotentRequest("key", "other")
+    t.ErrorIs(err, ErrIdempotencyKeyReuse)
+
+    // expired keys can be used anew
+    now := time.Now().Add(idempotencyKeyTTL + time.Hour)
+    t.service.now = func() time.Time { return now }
+    record, err = t.service.StartIdempotentRequest("key", "other")
+    t.Require().NoError(err)
+    t.Equal(0, record.Status)
+
+    // released keys too
+    t.Require().NoError(t.service.ReleaseIdempotencyKey(record))
+    record, err = t.service.StartIdempotentRequest("key", "hash")
+    t.Require().NoError(err)
+    t.Equal(0, record.Status)
+}
+
+func (t *MainTestSuite) TestIdempotencyKeyLease() {
+    abandoned, err := t.service.StartIdempotentRequest("key", "hash")
+    t.Require().NoError(err)
+
+    // a key held past its lease was abandoned, a retry takes it over
+    now := time.Now().Add(idempotencyKeyLease + time.Second)
+    t.service.now = func() time.Time { return now }
+    record, err := t.service.StartIdempotentRequest("key", "hash")
+    t.Require().NoError(err)
+    t.Equal(0, record.Status)
+    t.NotEqual(abandoned.ID, record.ID)
+
+    // the abandoned request can neither complete nor release it anymore
+    err = t.service.CompleteIdempotentRequest(abandoned, 200, "application/json", []byte(`{}`))
+    t.ErrorIs(err, ErrEmptyResult)
+    t.Require().NoError(t.service.ReleaseIdempotencyKey(abandoned))
+    _, err = t.service.StartIdempotentRequest("key", "hash")
+    t.ErrorIs(err, ErrIdempotencyKeyInProgress)
+
+    // a completed key is kept past the lease
+    t.Require().NoError(t.service.CompleteIdempotentRequest(record, 200, "application/json", []byte(`{}`)))
+    now = now.Add(time.Hour)
+    previous, err := t.service.StartIdempotentRequest("key", "hash")
+    t.Require().NoError(err)
+    t.Equal(record.ID, previous.ID)
+}
+
+func (t *MainTestSuite) TestPurgeIdempotencyKeys() {
+    record, err := t.service.StartIdempotentRequest("key", "hash")
+    t.Require().NoError(err)
+    t.Require().NoError(t.service.CompleteIdempotentRequest(record, 200, "application/json", []byte(`{}`)))
+    t.Require().NoError(t.service.purgeIdempotencyKeys())
+    _, err = t.service.DB.GetIdempotencyKey("key")
+    t.Require().NoError(err)
+
+    // expired keys are deleted
+    now := time.Now().Add(idempotencyKeyTTL + time.Hour)
+    t.service.now = func() time.Time { return now }
+    t.Require().NoError(t.service.purgeIdempotencyKeys())
+    _, err = t.service.DB.GetIdempotencyKey("key")
+    t.ErrorIs(err, ErrEmptyResult)
+    t.Require().NoError(t.service.purgeIdempotencyKeys())
+}
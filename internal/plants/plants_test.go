@@ -0,0 +1,222 @@
+package plants
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/jeandeducla/api-plant/internal/dto"
+	"github.com/jeandeducla/api-plant/internal/dto/v1"
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// UnitTestSuite tests the computations of the service that need no database.
+type UnitTestSuite struct {
+    suite.Suite
+}
+
+func TestUnitTestSuite(t *testing.T) {
+    suite.Run(t, new(UnitTestSuite))
+}
+
+func (t *UnitTestSuite) TestAssetFlexibility() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    from := now.Add(2 * time.Hour)
+    asset := models.Asset{
+        MaxPower: models.Kilowatts(100),
+        MinPower: models.Kilowatts(40),
+        MinOffTime: 30,
+        MinRunTime: 60,
+    }
+
+    // a window shorter than the minimum run time offers no upward flexibility
+    flexibility := assetFlexibility(asset, from, from.Add(30 * time.Minute), now, 0)
+    t.True(flexibility.Available)
+    t.Equal(models.Kilowatts(100), flexibility.Downward)
+    t.Equal(models.Kilowatts(0), flexibility.Upward)
+
+    // a window shorter than the minimum off time only offers the modulation
+    flexibility = assetFlexibility(asset, from, from.Add(15 * time.Minute), now, 0)
+    t.Equal(models.Kilowatts(60), flexibility.Downward)
+    t.Equal(models.Kilowatts(0), flexibility.Upward)
+
+    flexibility = assetFlexibility(asset, from, from.Add(time.Hour), now, 0)
+    t.Equal(models.Kilowatts(100), flexibility.Downward)
+    t.Equal(models.Kilowatts(100), flexibility.Upward)
+
+    // sub-kW bands are kept to the watt
+    asset = models.Asset{MaxPower: models.Watts(900)}
+    flexibility = assetFlexibility(asset, from, from.Add(time.Hour), now, 0)
+    t.Equal(models.Watts(900), flexibility.Downward)
+    t.Equal(models.Watts(900), flexibility.Upward)
+
+    // and so are ramps
+    asset = models.Asset{MaxPower: models.Kilowatts(100), RampDownRate: 1}
+    flexibility = assetFlexibility(asset, from, from.Add(90 * time.Second), now, 0)
+    t.Equal(models.Watts(1500), flexibility.Downward)
+}
+
+func (t *UnitTestSuite) TestLocalBuckets() {
+    paris, err := time.LoadLocation("Europe/Paris")
+    t.Require().NoError(err)
+    utc := func(month time.Month, day int, hour int, min int) time.Time {
+        return time.Date(2022, month, day, hour, min, 0, 0, time.UTC)
+    }
+    durations := func(buckets [][2]time.Time) []time.Duration {
+        res := []time.Duration{}
+        for _, bucket := range buckets {
+            res = append(res, bucket[1].Sub(bucket[0]))
+        }
+        return res
+    }
+
+    // clocks go forward on Sunday 27 March: the local day lasts 23 hours
+    buckets := localBuckets(utc(3, 26, 12, 0), utc(3, 28, 12, 0), paris, IntervalDay)
+    t.Require().Len(buckets, 3)
+    t.Equal([]time.Duration{24 * time.Hour, 23 * time.Hour, 24 * time.Hour}, durations(buckets))
+    t.True(buckets[1][0].Equal(utc(3, 26, 23, 0)))
+    t.True(buckets[1][1].Equal(utc(3, 27, 22, 0)))
+
+    // and 02:00 never happens
+    buckets = localBuckets(utc(3, 27, 0, 30), utc(3, 27, 2, 0), paris, IntervalHour)
+    t.Require().Len(buckets, 2)
+    t.Equal(1, buckets[0][0].Hour())
+    t.Equal(3, buckets[1][0].Hour())
+
+    // clocks go back on Sunday 30 October: the local day lasts 25 hours
+    buckets = localBuckets(utc(10, 29, 12, 0), utc(10, 31, 12, 0), paris, IntervalDay)
+    t.Require().Len(buckets, 3)
+    t.Equal([]time.Duration{24 * time.Hour, 25 * time.Hour, 24 * time.Hour}, durations(buckets))
+
+    // and 02:00 happens twice, as two buckets of an hour
+    buckets = localBuckets(utc(10, 30, 0, 0), utc(10, 30, 2, 0), paris, IntervalHour)
+    t.Require().Len(buckets, 2)
+    t.Equal(2, buckets[0][0].Hour())
+    t.Equal(2, buckets[1][0].Hour())
+    t.True(buckets[0][1].Equal(buckets[1][0]))
+    t.Equal([]time.Duration{time.Hour, time.Hour}, durations(buckets))
+    // a time in the second 02:00 falls in the second bucket
+    buckets = localBuckets(utc(10, 30, 1, 30), utc(10, 30, 1, 45), paris, IntervalHour)
+    t.Require().Len(buckets, 1)
+    t.True(buckets[0][0].Equal(utc(10, 30, 1, 0)))
+
+    // weeks start on local Mondays, and last 167 or 169 hours across a change
+    buckets = localBuckets(utc(3, 27, 12, 0), utc(3, 28, 12, 0), paris, IntervalWeek)
+    t.Require().Len(buckets, 2)
+    t.True(buckets[0][0].Equal(utc(3, 20, 23, 0)))
+    t.Equal(time.Monday, buckets[0][0].Weekday())
+    t.True(buckets[1][0].Equal(utc(3, 27, 22, 0)))
+    t.Equal(time.Monday, buckets[1][0].Weekday())
+    t.Equal(167 * time.Hour, durations(buckets)[0])
+    buckets = localBuckets(utc(10, 29, 12, 0), utc(10, 29, 13, 0), paris, IntervalWeek)
+    t.Equal([]time.Duration{169 * time.Hour}, durations(buckets))
+    // early on Monday in Paris is a new week, though it is Sunday in UTC
+    buckets = localBuckets(utc(5, 1, 22, 30), utc(5, 1, 22, 45), paris, IntervalWeek)
+    t.True(buckets[0][0].Equal(utc(5, 1, 22, 0)))
+
+    // months start on the local first, and lose or gain the changed hour
+    buckets = localBuckets(utc(2, 28, 23, 30), utc(4, 1, 0, 0), paris, IntervalMonth)
+    t.Require().Len(buckets, 2)
+    t.True(buckets[0][0].Equal(utc(2, 28, 23, 0)))
+    t.Equal((31 * 24 - 1) * time.Hour, durations(buckets)[0])
+    t.True(buckets[1][0].Equal(utc(3, 31, 22, 0)))
+    buckets = localBuckets(utc(10, 15, 0, 0), utc(10, 16, 0, 0), paris, IntervalMonth)
+    t.Equal([]time.Duration{(31 * 24 + 1) * time.Hour}, durations(buckets))
+
+    // southern hemisphere clocks go back in April, by half an hour on Lord Howe
+    lord_howe, err := time.LoadLocation("Australia/Lord_Howe")
+    t.Require().NoError(err)
+    buckets = localBuckets(utc(4, 2, 12, 0), utc(4, 3, 12, 0), lord_howe, IntervalDay)
+    t.Require().Len(buckets, 2)
+    t.Equal([]time.Duration{24 * time.Hour, 24 * time.Hour + 30 * time.Minute}, durations(buckets))
+
+    // local hours stay on the hour, the one when clocks go back lasts 90
+    // minutes
+    buckets = localBuckets(utc(4, 2, 13, 0), utc(4, 2, 17, 0), lord_howe, IntervalHour)
+    t.Require().Len(buckets, 4)
+    t.Equal([]time.Duration{time.Hour, 90 * time.Minute, time.Hour, time.Hour}, durations(buckets))
+    for _, bucket := range buckets {
+        t.Equal(0, bucket[0].Minute())
+    }
+    buckets = localBuckets(utc(4, 2, 15, 10), utc(4, 2, 15, 20), lord_howe, IntervalHour)
+    t.Require().Len(buckets, 1)
+    t.True(buckets[0][0].Equal(utc(4, 2, 14, 0)))
+
+    // from after to gives no bucket
+    t.Len(localBuckets(utc(3, 2, 0, 0), utc(3, 1, 0, 0), paris, IntervalDay), 0)
+}
+
+func (t *UnitTestSuite) TestRenderEventData() {
+    asset := &models.Asset{Name: "furnace", PlantID: 2, MaxPower: models.Kilowatts(100)}
+    asset.ID = 1
+    move := &models.AssetMove{AssetID: 1, FromPlantID: 1, ToPlantID: 2}
+    moved := assetMoved{Asset: asset, Move: move, FromPlantID: 1}
+
+    // each version of the contract renders the wrapped resources its way
+    data, err := renderEventData(moved, dto.New)
+    t.Require().NoError(err)
+    var body struct {
+        Asset       map[string]interface{} `json:"asset"`
+        Move        map[string]interface{} `json:"move"`
+        FromPlantID uint                   `json:"from_plant_id"`
+    }
+    t.Require().NoError(json.Unmarshal(data, &body))
+    t.Equal("furnace", body.Asset["name"])
+    t.Equal(float64(2), body.Asset["plant_id"])
+    t.Equal(float64(1), body.Move["from_plant_id"])
+    t.NotContains(body.Asset, "Name")
+    t.Equal(uint(1), body.FromPlantID)
+
+    data_v1, err := renderEventData(moved, v1.New)
+    t.Require().NoError(err)
+    var body_v1 struct {
+        Asset       map[string]interface{} `json:"asset"`
+        Move        map[string]interface{} `json:"move"`
+        FromPlantID uint                   `json:"from_plant_id"`
+    }
+    t.Require().NoError(json.Unmarshal(data_v1, &body_v1))
+    t.Equal("furnace", body_v1.Asset["Name"])
+    t.Equal(float64(2), body_v1.Asset["PlantID"])
+    t.Equal(float64(1), body_v1.Move["FromPlantID"])
+    t.NotContains(body_v1.Asset, "name")
+    t.Equal(uint(1), body_v1.FromPlantID)
+
+    // the events of deleted resources are the same in both
+    data, err = renderEventData(deletedResource{ID: 1}, dto.New)
+    t.Require().NoError(err)
+    data_v1, err = renderEventData(deletedResource{ID: 1}, v1.New)
+    t.Require().NoError(err)
+    t.JSONEq(string(data), string(data_v1))
+}
+
+func (t *UnitTestSuite) TestMaintenanceOccurrences() {
+    starts := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    window := models.MaintenanceWindow{StartsAt: starts, EndsAt: starts.Add(time.Hour), RRule: "FREQ=DAILY"}
+    from := starts.Add(24 * time.Hour)
+
+    occurrences, err := maintenanceOccurrences(window, time.UTC, from, from.Add(30 * 24 * time.Hour))
+    t.Require().NoError(err)
+    t.Len(occurrences, 30)
+    t.Equal(from, occurrences[0].StartsAt)
+
+    // a period with too many occurrences is refused
+    window.RRule = "FREQ=MINUTELY"
+    _, err = maintenanceOccurrences(window, time.UTC, from, from.Add(24 * time.Hour))
+    t.ErrorIs(err, ErrMaintenanceOccurrences)
+
+    // and so is a recurrence too long to expand up to the period, which
+    // keeps the asset out of use
+    window.RRule = "FREQ=SECONDLY"
+    from = starts.Add(30 * 24 * time.Hour)
+    _, err = maintenanceOccurrences(window, time.UTC, from, from.Add(time.Minute))
+    t.ErrorIs(err, ErrMaintenanceOccurrences)
+    t.True(maintenanceOverlaps(window, time.UTC, from, from.Add(time.Minute)))
+
+    // a single window is never refused
+    window.RRule = ""
+    occurrences, err = maintenanceOccurrences(window, time.UTC, starts, from)
+    t.Require().NoError(err)
+    t.Len(occurrences, 1)
+}
@@ -0,0 +1,160 @@
+package plants
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+// tariffSlot is the resolution of tariffs: periods start and end on quarter
+// hours, and demand is the average power over a quarter hour.
+const tariffSlot = 15 * time.Minute
+
+var (
+    ErrTariffPeriodDays = errors.New("Tariff period Days must be among 'mon', 'tue', 'wed', 'thu', 'fri', 'sat' and 'sun'")
+    ErrTariffPeriodTime = errors.New("Tariff period StartTime and EndTime must be distinct 'HH:MM' quarter hours")
+    ErrTariffPeriodMonths = errors.New("Tariff period StartMonth and EndMonth must be within 1 and 12, or both 0")
+    ErrTariffPeriodPrice = errors.New("Tariff period prices cannot be negative")
+    ErrTariffCoverage = errors.New("Tariff periods must cover every time of the year exactly once")
+)
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseDays reads a comma separated list of days, all days when empty.
+func parseDays(days string) ([7]bool, error) {
+    var res [7]bool
+    if strings.TrimSpace(days) == "" {
+        for i := range res {
+            res[i] = true
+        }
+        return res, nil
+    }
+    for _, day := range strings.Split(days, ",") {
+        day = strings.ToLower(strings.TrimSpace(day))
+        found := false
+        for i, weekday := range weekdays {
+            if day == weekday {
+                res[i] = true
+                found = true
+            }
+        }
+        if !found {
+            return res, ErrTariffPeriodDays
+        }
+    }
+    return res, nil
+}
+
+// parseTimeOfDay reads a 'HH:MM' quarter hour as minutes after midnight,
+// '24:00' being the end of the day.
+func parseTimeOfDay(s string) (int, error) {
+    var hours, minutes int
+    if n, err := fmt.Sscanf(s, "%d:%d", &hours, &minutes); err != nil || n != 2 || len(s) != 5 {
+        return 0, ErrTariffPeriodTime
+    }
+    m := hours*60 + minutes
+    if hours < 0 || minutes < 0 || minutes >= 60 || m > 24*60 || m%15 != 0 {
+        return 0, ErrTariffPeriodTime
+    }
+    return m, nil
+}
+
+// tariffPeriod is a TariffPeriod ready to be matched against times.
+type tariffPeriod struct {
+    period     models.TariffPeriod
+    days       [7]bool
+    start, end int
+}
+
+func parseTariffPeriod(period models.TariffPeriod) (tariffPeriod, error) {
+    res := tariffPeriod{period: period}
+    var err error
+    if res.days, err = parseDays(period.Days); err != nil {
+        return res, err
+    }
+    if res.start, err = parseTimeOfDay(period.StartTime); err != nil {
+        return res, err
+    }
+    if res.end, err = parseTimeOfDay(period.EndTime); err != nil {
+        return res, err
+    }
+    if res.start == res.end || res.start == 24*60 {
+        return res, ErrTariffPeriodTime
+    }
+    if (period.StartMonth == 0) != (period.EndMonth == 0) || period.StartMonth > 12 || period.EndMonth > 12 {
+        return res, ErrTariffPeriodMonths
+    }
+    if period.EnergyPrice < 0 || period.DemandPrice < 0 {
+        return res, ErrTariffPeriodPrice
+    }
+    return res, nil
+}
+
+// matches tells if the period applies at a local month, weekday and minute
+// of the day.
+func (p tariffPeriod) matches(month time.Month, weekday time.Weekday, minute int) bool {
+    if p.period.StartMonth != 0 {
+        start, end := time.Month(p.period.StartMonth), time.Month(p.period.EndMonth)
+        if start <= end && (month < start || month > end) {
+            return false
+        }
+        if start > end && month > end && month < start {
+            return false
+        }
+    }
+    if !p.days[weekday] {
+        return false
+    }
+    if p.start < p.end {
+        return minute >= p.start && minute < p.end
+    }
+    return minute >= p.start || minute < p.end
+}
+
+// compiledTariff finds the period applying at any time.
+type compiledTariff []tariffPeriod
+
+// compileTariff checks that the periods cover every quarter hour of every
+// day of the week of every month exactly once.
+func compileTariff(periods []models.TariffPeriod) (compiledTariff, error) {
+    compiled := make(compiledTariff, 0, len(periods))
+    for _, period := range periods {
+        p, err := parseTariffPeriod(period)
+        if err != nil {
+            return nil, err
+        }
+        compiled = append(compiled, p)
+    }
+
+    for month := time.January; month <= time.December; month++ {
+        for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+            for minute := 0; minute < 24*60; minute += 15 {
+                count := 0
+                for _, p := range compiled {
+                    if p.matches(month, weekday, minute) {
+                        count++
+                    }
+                }
+                if count != 1 {
+                    return nil, ErrTariffCoverage
+                }
+            }
+        }
+    }
+    return compiled, nil
+}
+
+// at returns the index of the period applying at t, read in location.
+func (c compiledTariff) at(t time.Time, location *time.Location) int {
+    t = t.In(location)
+    minute := t.Hour()*60 + t.Minute()
+    for i, p := range c {
+        if p.matches(t.Month(), t.Weekday(), minute) {
+            return i
+        }
+    }
+    return -1
+}
@@ -0,0 +1,76 @@
+package plants
+
+import (
+	"github.com/jeandeducla/api-plant/internal/models"
+	"gorm.io/gorm"
+)
+
+func orderById(db *gorm.DB) *gorm.DB {
+    return db.Order("id")
+}
+
+func (db *PlantsDB) GetAllTariffs() ([]models.Tariff, error) {
+    var tariffs []models.Tariff
+    if err := db.gorm.Preload("Periods", orderById).Order("id").Find(&tariffs).Error; err != nil {
+        return nil, err
+    }
+    return tariffs, nil
+}
+
+func (db *PlantsDB) CreateTariff(tariff *models.Tariff) error {
+    result := db.gorm.Create(tariff)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetTariffById(id uint) (*models.Tariff, error) {
+    var tariff models.Tariff
+    result := db.gorm.Preload("Periods", orderById).Find(&tariff, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &tariff, nil
+}
+
+func (db *PlantsDB) DeleteTariffById(id uint) error {
+    result := db.gorm.Delete(&models.Tariff{}, id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+// UpdateTariff saves a tariff and replaces all its periods.
+func (db *PlantsDB) UpdateTariff(tariff *models.Tariff) error {
+    return db.gorm.Transaction(func(tx *gorm.DB) error {
+        result := tx.Model(tariff).Omit("Periods").Updates(map[string]interface{}{
+            "name": tariff.Name,
+            "currency": tariff.Currency,
+        })
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+        err := tx.Where("tariff_id = ?", tariff.ID).Delete(&models.TariffPeriod{}).Error
+        if err != nil {
+            return err
+        }
+        for i := range tariff.Periods {
+            tariff.Periods[i].TariffID = tariff.ID
+        }
+        return tx.Create(&tariff.Periods).Error
+    })
+}
@@ -0,0 +1,171 @@
+package plants
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+var (
+    ErrTariffCurrency = errors.New("Tariff Currency must be a 3 letters ISO 4217 code")
+    ErrPlantTariff = errors.New("Plant tariff does not exist")
+    ErrPlantNoTariff = errors.New("Plant has no tariff")
+)
+
+type TariffPeriodInput struct {
+    Name        string   `json:"name"         binding:"required"`
+    StartMonth  uint     `json:"start_month"`
+    EndMonth    uint     `json:"end_month"`
+    Days        []string `json:"days"`
+    StartTime   string   `json:"start_time"   binding:"required"`
+    EndTime     string   `json:"end_time"     binding:"required"`
+    EnergyPrice float64  `json:"energy_price"`
+    DemandPrice float64  `json:"demand_price"`
+}
+
+type CreateTariffInput struct {
+    Name     string              `json:"name"     binding:"required"`
+    Currency string              `json:"currency"`
+    Periods  []TariffPeriodInput `json:"periods"  binding:"required,min=1,dive"`
+}
+
+type UpdateTariffInput CreateTariffInput
+
+// newTariff builds a tariff from its input and checks its periods cover the
+// whole year exactly once.
+func newTariff(name string, currency string, inputs []TariffPeriodInput) (*models.Tariff, error) {
+    currency = strings.ToUpper(strings.TrimSpace(currency))
+    if currency == "" {
+        currency = "EUR"
+    }
+    if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+        return nil, ErrTariffCurrency
+    }
+    tariff := models.Tariff{Name: name, Currency: currency}
+    for _, input := range inputs {
+        tariff.Periods = append(tariff.Periods, models.TariffPeriod{
+            Name: input.Name,
+            StartMonth: input.StartMonth,
+            EndMonth: input.EndMonth,
+            Days: strings.Join(input.Days, ","),
+            StartTime: input.StartTime,
+            EndTime: input.EndTime,
+            EnergyPrice: input.EnergyPrice,
+            DemandPrice: input.DemandPrice,
+        })
+    }
+    if _, err := compileTariff(tariff.Periods); err != nil {
+        return nil, err
+    }
+    return &tariff, nil
+}
+
+func (s *Service) GetAllTariffs() ([]models.Tariff, error) {
+    return s.DB.GetAllTariffs()
+}
+
+func (s *Service) CreateTariff(input CreateTariffInput) error {
+    tariff, err := newTariff(input.Name, input.Currency, input.Periods)
+    if err != nil {
+        return err
+    }
+    return s.DB.CreateTariff(tariff)
+}
+
+func (s *Service) GetTariff(id uint) (*models.Tariff, error) {
+    return s.DB.GetTariffById(id)
+}
+
+// DeleteTariff deletes a tariff, the plants it was attached to are left
+// without any.
+func (s *Service) DeleteTariff(id uint) error {
+    return s.DB.DeleteTariffById(id)
+}
+
+func (s *Service) UpdateTariff(id uint, input UpdateTariffInput) error {
+    tariff, err := newTariff(input.Name, input.Currency, input.Periods)
+    if err != nil {
+        return err
+    }
+    if _, err := s.DB.GetTariffById(id); err != nil {
+        return err
+    }
+    tariff.ID = id
+    return s.DB.UpdateTariff(tariff)
+}
+
+// checkPlantTariff makes sure the tariff attached to a plant, if any, exists.
+func (s *Service) checkPlantTariff(tariff_id *uint) error {
+    if tariff_id == nil {
+        return nil
+    }
+    if _, err := s.DB.GetTariffById(*tariff_id); err == ErrEmptyResult {
+        return ErrPlantTariff
+    } else if err != nil {
+        return err
+    }
+    return nil
+}
+
+// GetPlantCosts prices the load measured on a plant over [from, to) with its
+// tariff, in total, per tariff period and per asset.
+func (s *Service) GetPlantCosts(id uint, from time.Time, to time.Time) (*PlantCosts, error) {
+    plant, err := s.DB.GetPlantById(id)
+    if err != nil {
+        return nil, err
+    }
+    if plant.TariffID == nil {
+        return nil, ErrPlantNoTariff
+    }
+    tariff, err := s.DB.GetTariffById(*plant.TariffID)
+    if err != nil {
+        return nil, err
+    }
+    compiled, err := compileTariff(tariff.Periods)
+    if err != nil {
+        return nil, err
+    }
+
+    // the last reading before from still holds at from
+    measurements, err := s.DB.GetMeasurementsByPlantId(id, from.Add(-measurementMaxHold), to)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    byAsset := map[uint][]models.Measurement{}
+    for _, m := range measurements {
+        if m.AssetID != nil {
+            byAsset[*m.AssetID] = append(byAsset[*m.AssetID], m)
+        }
+    }
+    meter := slotEnergies(plantMeterMeasurements(measurements), from, to)
+    assets := map[uint]map[time.Time]float64{}
+    for asset_id, readings := range byAsset {
+        assets[asset_id] = slotEnergies(readings, from, to)
+    }
+
+    plant_cost, period_costs, asset_costs := computeCosts(compiled, plantLocation(plant), meter, assets, from, to)
+    res := PlantCosts{
+        PlantID: id,
+        TariffID: tariff.ID,
+        Currency: tariff.Currency,
+        From: from,
+        To: to,
+        CostBreakdown: plant_cost,
+        Periods: make([]PeriodCost, 0, len(period_costs)),
+        Assets: make([]AssetCost, 0, len(asset_costs)),
+    }
+    for i, cost := range period_costs {
+        period := compiled[i].period
+        res.Periods = append(res.Periods, PeriodCost{PeriodID: period.ID, Name: period.Name, CostBreakdown: cost})
+    }
+    for asset_id, cost := range asset_costs {
+        res.Assets = append(res.Assets, AssetCost{AssetID: asset_id, CostBreakdown: *cost})
+    }
+    sort.Slice(res.Assets, func(i, j int) bool {
+        return res.Assets[i].AssetID < res.Assets[j].AssetID
+    })
+    return &res, nil
+}
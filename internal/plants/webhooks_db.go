@@ -0,0 +1,145 @@
+package plants
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+)
+
+func (db *PlantsDB) GetAllWebhookSubscriptions() ([]models.WebhookSubscription, error) {
+    var subscriptions []models.WebhookSubscription
+    if err := db.gorm.Order("id").Find(&subscriptions).Error; err != nil {
+        return nil, err
+    }
+    return subscriptions, nil
+}
+
+func (db *PlantsDB) CreateWebhookSubscription(subscription *models.WebhookSubscription) error {
+    result := db.gorm.Create(subscription)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) GetWebhookSubscriptionById(id uint) (*models.WebhookSubscription, error) {
+    var subscription models.WebhookSubscription
+    result := db.gorm.Find(&subscription, id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &subscription, nil
+}
+
+func (db *PlantsDB) DeleteWebhookSubscriptionById(id uint) error {
+    result := db.gorm.Delete(&models.WebhookSubscription{}, id)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) UpdateWebhookSubscription(subscription *models.WebhookSubscription) error {
+    // the event filter can be emptied
+    result := db.gorm.Model(subscription).Select("*").Updates(subscription)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
+
+func (db *PlantsDB) CreateWebhookDeliveries(deliveries []models.WebhookDelivery) error {
+    if len(deliveries) == 0 {
+        return nil
+    }
+    return db.gorm.Create(&deliveries).Error
+}
+
+// GetWebhookDeliveriesBySubscriptionId returns the deliveries of a
+// subscription with the given status, or with any status when it is empty,
+// latest first.
+func (db *PlantsDB) GetWebhookDeliveriesBySubscriptionId(id uint, status string) ([]models.WebhookDelivery, error) {
+    var deliveries []models.WebhookDelivery
+    query := db.gorm.Where("subscription_id = ?", id)
+    if status != "" {
+        query = query.Where("status = ?", status)
+    }
+    result := query.Order("id DESC").Find(&deliveries)
+    if result.Error != nil {
+        return deliveries, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return deliveries, ErrEmptyResult
+    }
+    return deliveries, nil
+}
+
+func (db *PlantsDB) GetWebhookDeliveryBySubscriptionId(subscription_id uint, delivery_id uint) (*models.WebhookDelivery, error) {
+    var delivery models.WebhookDelivery
+    result := db.gorm.Where("subscription_id = ?", subscription_id).Find(&delivery, delivery_id)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrEmptyResult
+    }
+    return &delivery, nil
+}
+
+// ClaimDueWebhookDeliveries returns at most limit pending deliveries whose
+// next attempt is due at `at`, in the order they were created, and leases
+// them until `until` by pushing their next attempt back to then. The
+// deliveries locked by another relay claiming its own are skipped, so each
+// delivery is attempted by one relay at a time, and those of a relay gone
+// before updating them are due again once their lease is over.
+func (db *PlantsDB) ClaimDueWebhookDeliveries(at time.Time, until time.Time, limit int) ([]models.WebhookDelivery, error) {
+    var deliveries []models.WebhookDelivery
+    err := db.gorm.Transaction(func(tx *gorm.DB) error {
+        result := tx.
+            Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+            Where("status = ? AND next_attempt_at <= ?", WebhookDeliveryPending, at).
+            Order("id").
+            Limit(limit).
+            Find(&deliveries)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrEmptyResult
+        }
+        ids := make([]uint, len(deliveries))
+        for i := range deliveries {
+            ids[i] = deliveries[i].ID
+            deliveries[i].NextAttemptAt = until
+        }
+        return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", until).Error
+    })
+    return deliveries, err
+}
+
+func (db *PlantsDB) UpdateWebhookDelivery(delivery *models.WebhookDelivery) error {
+    // DeliveredAt is cleared on redelivery
+    result := db.gorm.Model(delivery).Select("*").Updates(delivery)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return ErrEmptyResult
+    }
+    return nil
+}
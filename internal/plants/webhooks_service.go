@@ -0,0 +1,291 @@
+package plants
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeandeducla/api-plant/internal/dto"
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/webhooks"
+)
+
+const (
+    WebhookDeliveryPending   = "pending"
+    WebhookDeliveryDelivered = "delivered"
+    WebhookDeliveryDead      = "dead"
+)
+
+// maxWebhookAttempts is how many times a webhook is attempted before its
+// delivery is given up on.
+const maxWebhookAttempts = 8
+
+// webhookPollInterval is how often due retries are looked for when no new
+// event wakes the deliveries up.
+const webhookPollInterval = 5 * time.Second
+
+const webhookBatchSize = 100
+
+// webhookDeliveryLease is how long a relay holds the deliveries it claimed,
+// longer than it can take to attempt a batch of them.
+const webhookDeliveryLease = 5 * time.Minute
+
+// webhookBackoff spaces out the attempts of a webhook over about an hour.
+var webhookBackoff = webhooks.Backoff{Base: 30 * time.Second, Max: time.Hour}
+
+var (
+    ErrWebhookURL = errors.New("Webhook URL must be an http or https URL")
+    ErrWebhookEvent = errors.New("Webhook Events must be known event types, like 'plant.updated'")
+    ErrWebhookDeliveryStatus = errors.New("Webhook delivery status must be one of 'pending', 'delivered' or 'dead'")
+)
+
+func isWebhookDeliveryStatus(status string) bool {
+    return status == WebhookDeliveryPending || status == WebhookDeliveryDelivered || status == WebhookDeliveryDead
+}
+
+// subscribedTo tells whether a subscription wants an event type.
+func subscribedTo(subscription models.WebhookSubscription, event_type string) bool {
+    if subscription.Events == "" {
+        return true
+    }
+    for _, t := range strings.Split(subscription.Events, ",") {
+        if t == event_type {
+            return true
+        }
+    }
+    return false
+}
+
+// queueWebhooks publishes an event to the webhooks sink of the outbox: it
+// records its delivery to the webhooks subscribed to it, in the transaction
+// tx of the relay. The deliveries are sent once it is committed.
+func (s *Service) queueWebhooks(tx DB, event *models.Event) error {
+    subscriptions, err := tx.GetAllWebhookSubscriptions()
+    if err != nil {
+        return err
+    }
+    payload, err := json.Marshal(dto.NewEventMessage(event))
+    if err != nil {
+        return err
+    }
+    deliveries := []models.WebhookDelivery{}
+    for _, subscription := range subscriptions {
+        if !subscribedTo(subscription, event.Type) {
+            continue
+        }
+        deliveries = append(deliveries, models.WebhookDelivery{
+            SubscriptionID: subscription.ID,
+            EventID: event.ID,
+            Event: event.Type,
+            Payload: payload,
+            Status: WebhookDeliveryPending,
+            NextAttemptAt: event.CreatedAt,
+        })
+    }
+    return tx.CreateWebhookDeliveries(deliveries)
+}
+
+// wakeWebhooks gets DeliverWebhooks to look for due deliveries right away.
+func (s *Service) wakeWebhooks() {
+    select {
+    case s.webhookWakeup <- struct{}{}:
+    default:
+    }
+}
+
+// DeliverWebhooks sends the pending webhooks until ctx is done: as soon as
+// they are emitted, then whenever their retry is due.
+func (s *Service) DeliverWebhooks(ctx context.Context) {
+    for {
+        s.deliverDueWebhooks(ctx)
+        select {
+        case <-ctx.Done():
+            return
+        case <-s.webhookWakeup:
+        case <-time.After(webhookPollInterval):
+        }
+    }
+}
+
+// deliverDueWebhooks attempts every delivery due now once.
+func (s *Service) deliverDueWebhooks(ctx context.Context) {
+    subscriptions := map[uint]*models.WebhookSubscription{}
+    for ctx.Err() == nil {
+        now := s.now()
+        deliveries, err := s.DB.ClaimDueWebhookDeliveries(now, now.Add(webhookDeliveryLease), webhookBatchSize)
+        if err != nil {
+            if err != ErrEmptyResult {
+                log.Printf("webhook deliveries: %v", err)
+            }
+            return
+        }
+        for i := range deliveries {
+            delivery := &deliveries[i]
+            subscription, ok := subscriptions[delivery.SubscriptionID]
+            if !ok {
+                subscription, err = s.DB.GetWebhookSubscriptionById(delivery.SubscriptionID)
+                if err == ErrEmptyResult {
+                    // the subscription is gone, nothing is left to deliver to
+                    delivery.Status = WebhookDeliveryDead
+                    delivery.LastError = "subscription deleted"
+                    if err := s.DB.UpdateWebhookDelivery(delivery); err != nil {
+                        log.Printf("webhook delivery %d: %v", delivery.ID, err)
+                    }
+                    continue
+                } else if err != nil {
+                    // attempted again once its lease is over
+                    log.Printf("webhook delivery %d: %v", delivery.ID, err)
+                    continue
+                }
+                subscriptions[delivery.SubscriptionID] = subscription
+            }
+            s.attemptWebhookDelivery(ctx, subscription, delivery)
+        }
+        if len(deliveries) < webhookBatchSize {
+            return
+        }
+    }
+}
+
+// attemptWebhookDelivery sends a webhook once. A failed delivery is retried
+// with an exponential backoff, and is dead after maxWebhookAttempts.
+func (s *Service) attemptWebhookDelivery(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+    err := s.Webhooks.Deliver(ctx, webhooks.Request{
+        URL: subscription.URL,
+        Secret: subscription.Secret,
+        Event: delivery.Event,
+        Delivery: strconv.FormatUint(uint64(delivery.ID), 10),
+        Payload: delivery.Payload,
+    })
+    now := s.now()
+    delivery.Attempts++
+    if err == nil {
+        delivery.Status = WebhookDeliveryDelivered
+        delivery.DeliveredAt = &now
+        delivery.LastError = ""
+    } else if delivery.Attempts >= maxWebhookAttempts {
+        delivery.Status = WebhookDeliveryDead
+        delivery.LastError = err.Error()
+    } else {
+        delivery.NextAttemptAt = now.Add(webhookBackoff.Delay(delivery.Attempts))
+        delivery.LastError = err.Error()
+    }
+    if err := s.DB.UpdateWebhookDelivery(delivery); err != nil {
+        log.Printf("webhook delivery %d: %v", delivery.ID, err)
+    }
+}
+
+func (s *Service) GetAllWebhookSubscriptions() ([]models.WebhookSubscription, error) {
+    return s.DB.GetAllWebhookSubscriptions()
+}
+
+// webhookEvents validates the URL and event types of a subscription, and
+// returns the event types as they are stored.
+func webhookEvents(target string, events []string) (string, error) {
+    u, err := url.Parse(target)
+    if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+        return "", ErrWebhookURL
+    }
+    for _, event := range events {
+        if !isEventType(event) {
+            return "", ErrWebhookEvent
+        }
+    }
+    return strings.Join(events, ","), nil
+}
+
+type CreateWebhookSubscriptionInput struct {
+    URL    string   `json:"url"    binding:"required"`
+    Secret string   `json:"secret" binding:"required"`
+    Events []string `json:"events"`
+}
+
+// CreateWebhookSubscription subscribes an URL to some event types, or to
+// every event when none is given. The secret signs the webhooks, it is never
+// rendered back.
+func (s *Service) CreateWebhookSubscription(input CreateWebhookSubscriptionInput) error {
+    events, err := webhookEvents(input.URL, input.Events)
+    if err != nil {
+        return err
+    }
+    return s.DB.CreateWebhookSubscription(&models.WebhookSubscription{
+        URL: input.URL,
+        Secret: input.Secret,
+        Events: events,
+    })
+}
+
+func (s *Service) GetWebhookSubscription(id uint) (*models.WebhookSubscription, error) {
+    return s.DB.GetWebhookSubscriptionById(id)
+}
+
+func (s *Service) DeleteWebhookSubscription(id uint) error {
+    return s.DB.DeleteWebhookSubscriptionById(id)
+}
+
+type UpdateWebhookSubscriptionInput struct {
+    URL    string   `json:"url"    binding:"required"`
+    Secret string   `json:"secret"`
+    Events []string `json:"events"`
+}
+
+// UpdateWebhookSubscription changes the URL and event types of a
+// subscription, and its secret when a new one is given.
+func (s *Service) UpdateWebhookSubscription(id uint, input UpdateWebhookSubscriptionInput) error {
+    subscription, err := s.DB.GetWebhookSubscriptionById(id)
+    if err != nil {
+        return err
+    }
+    events, err := webhookEvents(input.URL, input.Events)
+    if err != nil {
+        return err
+    }
+    subscription.URL = input.URL
+    subscription.Events = events
+    if input.Secret != "" {
+        subscription.Secret = input.Secret
+    }
+    return s.DB.UpdateWebhookSubscription(subscription)
+}
+
+// GetWebhookDeliveries returns the deliveries of a subscription with a
+// status, or with any status when it is empty, latest first. The dead ones
+// make the dead-letter list of the subscription.
+func (s *Service) GetWebhookDeliveries(id uint, status string) ([]models.WebhookDelivery, error) {
+    if status != "" && !isWebhookDeliveryStatus(status) {
+        return nil, ErrWebhookDeliveryStatus
+    }
+    if _, err := s.DB.GetWebhookSubscriptionById(id); err != nil {
+        return nil, err
+    }
+    deliveries, err := s.DB.GetWebhookDeliveriesBySubscriptionId(id, status)
+    if err != nil && err != ErrEmptyResult {
+        return nil, err
+    }
+    return deliveries, nil
+}
+
+// RedeliverWebhook sends a delivery again right away, with a fresh set of
+// attempts. It is meant for dead deliveries, once the receiver is fixed, but
+// delivered ones can be replayed too.
+func (s *Service) RedeliverWebhook(subscription_id uint, delivery_id uint) error {
+    delivery, err := s.DB.GetWebhookDeliveryBySubscriptionId(subscription_id, delivery_id)
+    if err != nil {
+        return err
+    }
+    delivery.Status = WebhookDeliveryPending
+    delivery.Attempts = 0
+    delivery.NextAttemptAt = s.now()
+    delivery.LastError = ""
+    delivery.DeliveredAt = nil
+    if err := s.DB.UpdateWebhookDelivery(delivery); err != nil {
+        return err
+    }
+    s.wakeWebhooks()
+    return nil
+}
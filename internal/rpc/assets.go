@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/plants"
+	"github.com/jeandeducla/api-plant/internal/rpc/plantspb"
+)
+
+func newAsset(asset *models.Asset) *plantspb.Asset {
+    return &plantspb.Asset{
+        Id: uint64(asset.ID),
+        PlantId: uint64(asset.PlantID),
+        Name: asset.Name,
+        Type: asset.Type,
+        Availability: asset.Availability,
+        MaxPowerWatts: asset.MaxPower.Watts(),
+        MinPowerWatts: asset.MinPower.Watts(),
+        RampUpRate: uint32(asset.RampUpRate),
+        RampDownRate: uint32(asset.RampDownRate),
+        MinRunTime: uint32(asset.MinRunTime),
+        MinOffTime: uint32(asset.MinOffTime),
+        MaxEventsPerDay: uint32(asset.MaxEventsPerDay),
+        NoticePeriod: uint32(asset.NoticePeriod),
+        Priority: uint32(asset.Priority),
+        AgentId: optionalUint64(asset.AgentID),
+        GroupId: optionalUint64(asset.GroupID),
+    }
+}
+
+func newUpdateAssetInput(fields *plantspb.AssetFields) (plants.UpdateAssetInput, error) {
+    err := required(
+        field{"asset", fields != nil},
+        field{"name", fields.GetName() != ""},
+        field{"max_power_watts", fields.GetMaxPowerWatts() != 0},
+        field{"type", fields.GetType() != ""},
+    )
+    if err != nil {
+        return plants.UpdateAssetInput{}, err
+    }
+    max_power, err := watts("max_power_watts", fields.MaxPowerWatts)
+    if err != nil {
+        return plants.UpdateAssetInput{}, err
+    }
+    min_power, err := watts("min_power_watts", fields.MinPowerWatts)
+    if err != nil {
+        return plants.UpdateAssetInput{}, err
+    }
+    return plants.UpdateAssetInput{
+        Name: fields.Name,
+        MaxPower: max_power,
+        Type: fields.Type,
+        MinPower: min_power,
+        RampUpRate: uint(fields.RampUpRate),
+        RampDownRate: uint(fields.RampDownRate),
+        MinRunTime: uint(fields.MinRunTime),
+        MinOffTime: uint(fields.MinOffTime),
+        MaxEventsPerDay: uint(fields.MaxEventsPerDay),
+        NoticePeriod: uint(fields.NoticePeriod),
+        Priority: uint(fields.Priority),
+        AgentID: optionalId(fields.AgentId),
+        GroupID: optionalId(fields.GroupId),
+        Availability: fields.Availability,
+    }, nil
+}
+
+func (s *Server) ListAssets(ctx context.Context, req *plantspb.ListAssetsRequest) (*plantspb.ListAssetsResponse, error) {
+    assets, err := s.plantsService.GetPlantAssets(uint(req.PlantId))
+    if err != nil {
+        return nil, statusError(err)
+    }
+    res := &plantspb.ListAssetsResponse{Assets: []*plantspb.Asset{}}
+    for i := range assets {
+        res.Assets = append(res.Assets, newAsset(&assets[i]))
+    }
+    return res, nil
+}
+
+func (s *Server) GetAsset(ctx context.Context, req *plantspb.GetAssetRequest) (*plantspb.Asset, error) {
+    asset, err := s.plantsService.GetPlantAsset(uint(req.PlantId), uint(req.Id))
+    if err != nil {
+        return nil, statusError(err)
+    }
+    return newAsset(asset), nil
+}
+
+func (s *Server) CreateAsset(ctx context.Context, req *plantspb.CreateAssetRequest) (*emptypb.Empty, error) {
+    input, err := newUpdateAssetInput(req.Asset)
+    if err != nil {
+        return nil, statusError(err)
+    }
+    if err := s.plantsService.CreateAsset(uint(req.PlantId), plants.CreateAssetInput(input)); err != nil {
+        return nil, statusError(err)
+    }
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) UpdateAsset(ctx context.Context, req *plantspb.UpdateAssetRequest) (*plantspb.Asset, error) {
+    input, err := newUpdateAssetInput(req.Asset)
+    if err != nil {
+        return nil, statusError(err)
+    }
+    if err := s.plantsService.UpdatePlantAsset(uint(req.PlantId), uint(req.Id), input); err != nil {
+        return nil, statusError(err)
+    }
+    return s.GetAsset(ctx, &plantspb.GetAssetRequest{PlantId: req.PlantId, Id: req.Id})
+}
+
+func (s *Server) DeleteAsset(ctx context.Context, req *plantspb.DeleteAssetRequest) (*emptypb.Empty, error) {
+    if err := s.plantsService.DeletePlantAsset(uint(req.PlantId), uint(req.Id)); err != nil {
+        return nil, statusError(err)
+    }
+    return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/plants"
+	"github.com/jeandeducla/api-plant/internal/rpc/plantspb"
+)
+
+func newEnergyManager(em *models.EnergyManager) *plantspb.EnergyManager {
+    return &plantspb.EnergyManager{
+        Id: uint64(em.ID),
+        Name: em.Name,
+        Surname: em.Surname,
+        Email: em.Email,
+        Phone: em.Phone,
+        Timezone: em.Timezone,
+        Language: em.Language,
+    }
+}
+
+func checkEnergyManagerFields(fields *plantspb.EnergyManagerFields) error {
+    return required(
+        field{"energy_manager", fields != nil},
+        field{"name", fields.GetName() != ""},
+        field{"surname", fields.GetSurname() != ""},
+    )
+}
+
+func (s *Server) ListEnergyManagers(ctx context.Context, req *plantspb.ListEnergyManagersRequest) (*plantspb.ListEnergyManagersResponse, error) {
+    ems, err := s.plantsService.GetAllEnergyManagers()
+    if err != nil && !errors.Is(err, plants.ErrEmptyResult) {
+        return nil, statusError(err)
+    }
+    res := &plantspb.ListEnergyManagersResponse{EnergyManagers: []*plantspb.EnergyManager{}}
+    for i := range ems {
+        res.EnergyManagers = append(res.EnergyManagers, newEnergyManager(&ems[i]))
+    }
+    return res, nil
+}
+
+func (s *Server) GetEnergyManager(ctx context.Context, req *plantspb.GetEnergyManagerRequest) (*plantspb.EnergyManager, error) {
+    em, err := s.plantsService.GetEnergyManager(uint(req.Id))
+    if err != nil {
+        return nil, statusError(err)
+    }
+    return newEnergyManager(em), nil
+}
+
+func (s *Server) CreateEnergyManager(ctx context.Context, req *plantspb.CreateEnergyManagerRequest) (*emptypb.Empty, error) {
+    if err := checkEnergyManagerFields(req.EnergyManager); err != nil {
+        return nil, err
+    }
+    fields := req.EnergyManager
+    err := s.plantsService.CreateEnergyManager(plants.CreateEnergyManagerInput{
+        Name: fields.Name,
+        Surname: fields.Surname,
+        Email: fields.Email,
+        Phone: fields.Phone,
+        Timezone: fields.Timezone,
+        Language: fields.Language,
+    })
+    if err != nil {
+        return nil, statusError(err)
+    }
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) UpdateEnergyManager(ctx context.Context, req *plantspb.UpdateEnergyManagerRequest) (*plantspb.EnergyManager, error) {
+    if err := checkEnergyManagerFields(req.EnergyManager); err != nil {
+        return nil, err
+    }
+    fields := req.EnergyManager
+    err := s.plantsService.UpdateEnergyManager(uint(req.Id), plants.UpdateEnergyManagerInput{
+        Name: fields.Name,
+        Surname: fields.Surname,
+        Email: fields.Email,
+        Phone: fields.Phone,
+        Timezone: fields.Timezone,
+        Language: fields.Language,
+    })
+    if err != nil {
+        return nil, statusError(err)
+    }
+    return s.GetEnergyManager(ctx, &plantspb.GetEnergyManagerRequest{Id: req.Id})
+}
+
+// DeleteEnergyManager lists the plants to reassign in the message of its
+// FAILED_PRECONDITION status.
+func (s *Server) DeleteEnergyManager(ctx context.Context, req *plantspb.DeleteEnergyManagerRequest) (*emptypb.Empty, error) {
+    plants_left, err := s.plantsService.DeleteEnergyManager(uint(req.Id), optionalId(req.ReassignTo))
+    if errors.Is(err, plants.ErrEmPlants) {
+        ids := make([]string, len(plants_left))
+        for i, plant := range plants_left {
+            ids[i] = fmt.Sprint(plant.ID)
+        }
+        return nil, status.Errorf(codes.FailedPrecondition, "%v: %s", err, strings.Join(ids, ", "))
+    } else if err != nil {
+        return nil, statusError(err)
+    }
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListEnergyManagerPlants(ctx context.Context, req *plantspb.ListEnergyManagerPlantsRequest) (*plantspb.ListPlantsResponse, error) {
+    list, err := s.plantsService.GetEnergyManagerPlants(uint(req.Id), req.Role)
+    if err != nil {
+        return nil, statusError(err)
+    }
+    return newPlantsResponse(list), nil
+}
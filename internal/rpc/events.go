@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jeandeducla/api-plant/internal/dto"
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/plants"
+	"github.com/jeandeducla/api-plant/internal/rpc/plantspb"
+)
+
+// watchWait is how long WatchEvents waits for events before checking the
+// stream is still open.
+const watchWait = 15 * time.Second
+
+// newEvent converts an event of the log, its data in the version 2 contract
+// like the webhooks get it.
+func newEvent(event *models.Event) *plantspb.Event {
+    message := dto.NewEventMessage(event)
+    return &plantspb.Event{
+        Id: uint64(message.ID),
+        Type: message.Type,
+        OccurredAt: timestamppb.New(message.OccurredAt),
+        Data: string(message.Data),
+    }
+}
+
+// WatchEvents streams the events of the log until the client goes away. A
+// client resuming with after_id first gets the events it missed, others only
+// get the events to come.
+func (s *Server) WatchEvents(req *plantspb.WatchEventsRequest, stream plantspb.PlantsService_WatchEventsServer) error {
+    filter := plants.EventFilter{
+        PlantID: optionalId(req.PlantId),
+        EnergyManagerID: optionalId(req.EnergyManagerId),
+        EntityTypes: req.EntityTypes,
+    }
+    if err := s.plantsService.CheckEventFilter(filter); err != nil {
+        return statusError(err)
+    }
+
+    var after uint
+    if req.AfterId != nil {
+        after = uint(*req.AfterId)
+    } else {
+        id, err := s.plantsService.LastEventID()
+        if err != nil {
+            return statusError(err)
+        }
+        after = id
+    }
+
+    ctx := stream.Context()
+    for ctx.Err() == nil {
+        events, err := s.plantsService.WaitEvents(ctx, filter, after, watchWait)
+        if err != nil {
+            return statusError(err)
+        }
+        for i := range events {
+            if err := stream.Send(newEvent(&events[i])); err != nil {
+                return err
+            }
+            after = events[i].ID
+        }
+    }
+    return nil
+}
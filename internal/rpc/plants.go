@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/plants"
+	"github.com/jeandeducla/api-plant/internal/rpc/plantspb"
+)
+
+func newPlant(plant *models.Plant) *plantspb.Plant {
+    return &plantspb.Plant{
+        Id: uint64(plant.ID),
+        Name: plant.Name,
+        Address: plant.Address,
+        MaxPowerWatts: plant.MaxPower.Watts(),
+        Latitude: plant.Latitude,
+        Longitude: plant.Longitude,
+        GridZone: plant.GridZone,
+        CountryCode: plant.CountryCode,
+        Timezone: plant.Timezone,
+        TariffId: optionalUint64(plant.TariffID),
+    }
+}
+
+func newPlantsResponse(list []models.Plant) *plantspb.ListPlantsResponse {
+    res := &plantspb.ListPlantsResponse{Plants: []*plantspb.Plant{}}
+    for i := range list {
+        res.Plants = append(res.Plants, newPlant(&list[i]))
+    }
+    return res
+}
+
+func newUpdatePlantInput(fields *plantspb.PlantFields) (plants.UpdatePlantInput, error) {
+    err := required(
+        field{"plant", fields != nil},
+        field{"name", fields.GetName() != ""},
+        field{"address", fields.GetAddress() != ""},
+        field{"max_power_watts", fields.GetMaxPowerWatts() != 0},
+        field{"energy_manager_id", fields.GetEnergyManagerId() != 0},
+    )
+    if err != nil {
+        return plants.UpdatePlantInput{}, err
+    }
+    max_power, err := watts("max_power_watts", fields.MaxPowerWatts)
+    if err != nil {
+        return plants.UpdatePlantInput{}, err
+    }
+    return plants.UpdatePlantInput{
+        Name: fields.Name,
+        Address: fields.Address,
+        MaxPower: max_power,
+        EnergyManagerID: uint(fields.EnergyManagerId),
+        Latitude: fields.Latitude,
+        Longitude: fields.Longitude,
+        GridZone: fields.GridZone,
+        CountryCode: fields.CountryCode,
+        Timezone: fields.Timezone,
+        TariffID: optionalId(fields.TariffId),
+    }, nil
+}
+
+// ListPlants lists the plants, those of a grid zone or country when they are
+// given.
+func (s *Server) ListPlants(ctx context.Context, req *plantspb.ListPlantsRequest) (*plantspb.ListPlantsResponse, error) {
+    var list []models.Plant
+    var err error
+    if req.GridZone == "" && req.CountryCode == "" {
+        list, err = s.plantsService.GetAllPlants()
+    } else {
+        list, err = s.plantsService.SearchPlants(plants.PlantQuery{GridZone: req.GridZone, CountryCode: req.CountryCode})
+    }
+    if err != nil && !errors.Is(err, plants.ErrEmptyResult) {
+        return nil, statusError(err)
+    }
+    return newPlantsResponse(list), nil
+}
+
+func (s *Server) GetPlant(ctx context.Context, req *plantspb.GetPlantRequest) (*plantspb.Plant, error) {
+    plant, err := s.plantsService.GetPlant(uint(req.Id))
+    if err != nil {
+        return nil, statusError(err)
+    }
+    return newPlant(plant), nil
+}
+
+func (s *Server) CreatePlant(ctx context.Context, req *plantspb.CreatePlantRequest) (*emptypb.Empty, error) {
+    input, err := newUpdatePlantInput(req.Plant)
+    if err != nil {
+        return nil, statusError(err)
+    }
+    if err := s.plantsService.CreatePlant(plants.CreatePlantInput(input)); err != nil {
+        return nil, statusError(err)
+    }
+    return &emptypb.Empty{}, nil
+}
+
+func (s *Server) UpdatePlant(ctx context.Context, req *plantspb.UpdatePlantRequest) (*plantspb.Plant, error) {
+    input, err := newUpdatePlantInput(req.Plant)
+    if err != nil {
+        return nil, statusError(err)
+    }
+    if err := s.plantsService.UpdatePlant(uint(req.Id), input); err != nil {
+        return nil, statusError(err)
+    }
+    return s.GetPlant(ctx, &plantspb.GetPlantRequest{Id: req.Id})
+}
+
+func (s *Server) DeletePlant(ctx context.Context, req *plantspb.DeletePlantRequest) (*emptypb.Empty, error) {
+    if err := s.plantsService.DeletePlant(uint(req.Id)); err != nil {
+        return nil, statusError(err)
+    }
+    return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,738 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: plants.proto
+
+package plantspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PlantsService_ListEnergyManagers_FullMethodName      = "/plants.v1.PlantsService/ListEnergyManagers"
+	PlantsService_GetEnergyManager_FullMethodName        = "/plants.v1.PlantsService/GetEnergyManager"
+	PlantsService_CreateEnergyManager_FullMethodName     = "/plants.v1.PlantsService/CreateEnergyManager"
+	PlantsService_UpdateEnergyManager_FullMethodName     = "/plants.v1.PlantsService/UpdateEnergyManager"
+	PlantsService_DeleteEnergyManager_FullMethodName     = "/plants.v1.PlantsService/DeleteEnergyManager"
+	PlantsService_ListEnergyManagerPlants_FullMethodName = "/plants.v1.PlantsService/ListEnergyManagerPlants"
+	PlantsService_ListPlants_FullMethodName              = "/plants.v1.PlantsService/ListPlants"
+	PlantsService_GetPlant_FullMethodName                = "/plants.v1.PlantsService/GetPlant"
+	PlantsService_CreatePlant_FullMethodName             = "/plants.v1.PlantsService/CreatePlant"
+	PlantsService_UpdatePlant_FullMethodName             = "/plants.v1.PlantsService/UpdatePlant"
+	PlantsService_DeletePlant_FullMethodName             = "/plants.v1.PlantsService/DeletePlant"
+	PlantsService_ListAssets_FullMethodName              = "/plants.v1.PlantsService/ListAssets"
+	PlantsService_GetAsset_FullMethodName                = "/plants.v1.PlantsService/GetAsset"
+	PlantsService_CreateAsset_FullMethodName             = "/plants.v1.PlantsService/CreateAsset"
+	PlantsService_UpdateAsset_FullMethodName             = "/plants.v1.PlantsService/UpdateAsset"
+	PlantsService_DeleteAsset_FullMethodName             = "/plants.v1.PlantsService/DeleteAsset"
+	PlantsService_WatchEvents_FullMethodName             = "/plants.v1.PlantsService/WatchEvents"
+)
+
+// PlantsServiceClient is the client API for PlantsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlantsServiceClient interface {
+	ListEnergyManagers(ctx context.Context, in *ListEnergyManagersRequest, opts ...grpc.CallOption) (*ListEnergyManagersResponse, error)
+	GetEnergyManager(ctx context.Context, in *GetEnergyManagerRequest, opts ...grpc.CallOption) (*EnergyManager, error)
+	CreateEnergyManager(ctx context.Context, in *CreateEnergyManagerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateEnergyManager(ctx context.Context, in *UpdateEnergyManagerRequest, opts ...grpc.CallOption) (*EnergyManager, error)
+	// DeleteEnergyManager fails with FAILED_PRECONDITION while the energy
+	// manager is assigned to plants, unless they are reassigned.
+	DeleteEnergyManager(ctx context.Context, in *DeleteEnergyManagerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListEnergyManagerPlants(ctx context.Context, in *ListEnergyManagerPlantsRequest, opts ...grpc.CallOption) (*ListPlantsResponse, error)
+	ListPlants(ctx context.Context, in *ListPlantsRequest, opts ...grpc.CallOption) (*ListPlantsResponse, error)
+	GetPlant(ctx context.Context, in *GetPlantRequest, opts ...grpc.CallOption) (*Plant, error)
+	CreatePlant(ctx context.Context, in *CreatePlantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdatePlant(ctx context.Context, in *UpdatePlantRequest, opts ...grpc.CallOption) (*Plant, error)
+	DeletePlant(ctx context.Context, in *DeletePlantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListAssets(ctx context.Context, in *ListAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error)
+	GetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*Asset, error)
+	CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateAsset(ctx context.Context, in *UpdateAssetRequest, opts ...grpc.CallOption) (*Asset, error)
+	DeleteAsset(ctx context.Context, in *DeleteAssetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// WatchEvents streams the events of the log matching the request, like
+	// GET /events: those after after_id when it is given, then those to come.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (PlantsService_WatchEventsClient, error)
+}
+
+type plantsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlantsServiceClient(cc grpc.ClientConnInterface) PlantsServiceClient {
+	return &plantsServiceClient{cc}
+}
+
+func (c *plantsServiceClient) ListEnergyManagers(ctx context.Context, in *ListEnergyManagersRequest, opts ...grpc.CallOption) (*ListEnergyManagersResponse, error) {
+	out := new(ListEnergyManagersResponse)
+	err := c.cc.Invoke(ctx, PlantsService_ListEnergyManagers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) GetEnergyManager(ctx context.Context, in *GetEnergyManagerRequest, opts ...grpc.CallOption) (*EnergyManager, error) {
+	out := new(EnergyManager)
+	err := c.cc.Invoke(ctx, PlantsService_GetEnergyManager_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) CreateEnergyManager(ctx context.Context, in *CreateEnergyManagerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PlantsService_CreateEnergyManager_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) UpdateEnergyManager(ctx context.Context, in *UpdateEnergyManagerRequest, opts ...grpc.CallOption) (*EnergyManager, error) {
+	out := new(EnergyManager)
+	err := c.cc.Invoke(ctx, PlantsService_UpdateEnergyManager_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) DeleteEnergyManager(ctx context.Context, in *DeleteEnergyManagerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PlantsService_DeleteEnergyManager_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) ListEnergyManagerPlants(ctx context.Context, in *ListEnergyManagerPlantsRequest, opts ...grpc.CallOption) (*ListPlantsResponse, error) {
+	out := new(ListPlantsResponse)
+	err := c.cc.Invoke(ctx, PlantsService_ListEnergyManagerPlants_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) ListPlants(ctx context.Context, in *ListPlantsRequest, opts ...grpc.CallOption) (*ListPlantsResponse, error) {
+	out := new(ListPlantsResponse)
+	err := c.cc.Invoke(ctx, PlantsService_ListPlants_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) GetPlant(ctx context.Context, in *GetPlantRequest, opts ...grpc.CallOption) (*Plant, error) {
+	out := new(Plant)
+	err := c.cc.Invoke(ctx, PlantsService_GetPlant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) CreatePlant(ctx context.Context, in *CreatePlantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PlantsService_CreatePlant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) UpdatePlant(ctx context.Context, in *UpdatePlantRequest, opts ...grpc.CallOption) (*Plant, error) {
+	out := new(Plant)
+	err := c.cc.Invoke(ctx, PlantsService_UpdatePlant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) DeletePlant(ctx context.Context, in *DeletePlantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PlantsService_DeletePlant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) ListAssets(ctx context.Context, in *ListAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error) {
+	out := new(ListAssetsResponse)
+	err := c.cc.Invoke(ctx, PlantsService_ListAssets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) GetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*Asset, error) {
+	out := new(Asset)
+	err := c.cc.Invoke(ctx, PlantsService_GetAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PlantsService_CreateAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) UpdateAsset(ctx context.Context, in *UpdateAssetRequest, opts ...grpc.CallOption) (*Asset, error) {
+	out := new(Asset)
+	err := c.cc.Invoke(ctx, PlantsService_UpdateAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) DeleteAsset(ctx context.Context, in *DeleteAssetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PlantsService_DeleteAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantsServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (PlantsService_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PlantsService_ServiceDesc.Streams[0], PlantsService_WatchEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &plantsServiceWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PlantsService_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type plantsServiceWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *plantsServiceWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PlantsServiceServer is the server API for PlantsService service.
+// All implementations must embed UnimplementedPlantsServiceServer
+// for forward compatibility
+type PlantsServiceServer interface {
+	ListEnergyManagers(context.Context, *ListEnergyManagersRequest) (*ListEnergyManagersResponse, error)
+	GetEnergyManager(context.Context, *GetEnergyManagerRequest) (*EnergyManager, error)
+	CreateEnergyManager(context.Context, *CreateEnergyManagerRequest) (*emptypb.Empty, error)
+	UpdateEnergyManager(context.Context, *UpdateEnergyManagerRequest) (*EnergyManager, error)
+	// DeleteEnergyManager fails with FAILED_PRECONDITION while the energy
+	// manager is assigned to plants, unless they are reassigned.
+	DeleteEnergyManager(context.Context, *DeleteEnergyManagerRequest) (*emptypb.Empty, error)
+	ListEnergyManagerPlants(context.Context, *ListEnergyManagerPlantsRequest) (*ListPlantsResponse, error)
+	ListPlants(context.Context, *ListPlantsRequest) (*ListPlantsResponse, error)
+	GetPlant(context.Context, *GetPlantRequest) (*Plant, error)
+	CreatePlant(context.Context, *CreatePlantRequest) (*emptypb.Empty, error)
+	UpdatePlant(context.Context, *UpdatePlantRequest) (*Plant, error)
+	DeletePlant(context.Context, *DeletePlantRequest) (*emptypb.Empty, error)
+	ListAssets(context.Context, *ListAssetsRequest) (*ListAssetsResponse, error)
+	GetAsset(context.Context, *GetAssetRequest) (*Asset, error)
+	CreateAsset(context.Context, *CreateAssetRequest) (*emptypb.Empty, error)
+	UpdateAsset(context.Context, *UpdateAssetRequest) (*Asset, error)
+	DeleteAsset(context.Context, *DeleteAssetRequest) (*emptypb.Empty, error)
+	// WatchEvents streams the events of the log matching the request, like
+	// GET /events: those after after_id when it is given, then those to come.
+	WatchEvents(*WatchEventsRequest, PlantsService_WatchEventsServer) error
+	mustEmbedUnimplementedPlantsServiceServer()
+}
+
+// UnimplementedPlantsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPlantsServiceServer struct {
+}
+
+func (UnimplementedPlantsServiceServer) ListEnergyManagers(context.Context, *ListEnergyManagersRequest) (*ListEnergyManagersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEnergyManagers not implemented")
+}
+func (UnimplementedPlantsServiceServer) GetEnergyManager(context.Context, *GetEnergyManagerRequest) (*EnergyManager, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEnergyManager not implemented")
+}
+func (UnimplementedPlantsServiceServer) CreateEnergyManager(context.Context, *CreateEnergyManagerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEnergyManager not implemented")
+}
+func (UnimplementedPlantsServiceServer) UpdateEnergyManager(context.Context, *UpdateEnergyManagerRequest) (*EnergyManager, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEnergyManager not implemented")
+}
+func (UnimplementedPlantsServiceServer) DeleteEnergyManager(context.Context, *DeleteEnergyManagerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEnergyManager not implemented")
+}
+func (UnimplementedPlantsServiceServer) ListEnergyManagerPlants(context.Context, *ListEnergyManagerPlantsRequest) (*ListPlantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEnergyManagerPlants not implemented")
+}
+func (UnimplementedPlantsServiceServer) ListPlants(context.Context, *ListPlantsRequest) (*ListPlantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlants not implemented")
+}
+func (UnimplementedPlantsServiceServer) GetPlant(context.Context, *GetPlantRequest) (*Plant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlant not implemented")
+}
+func (UnimplementedPlantsServiceServer) CreatePlant(context.Context, *CreatePlantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlant not implemented")
+}
+func (UnimplementedPlantsServiceServer) UpdatePlant(context.Context, *UpdatePlantRequest) (*Plant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePlant not implemented")
+}
+func (UnimplementedPlantsServiceServer) DeletePlant(context.Context, *DeletePlantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePlant not implemented")
+}
+func (UnimplementedPlantsServiceServer) ListAssets(context.Context, *ListAssetsRequest) (*ListAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAssets not implemented")
+}
+func (UnimplementedPlantsServiceServer) GetAsset(context.Context, *GetAssetRequest) (*Asset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAsset not implemented")
+}
+func (UnimplementedPlantsServiceServer) CreateAsset(context.Context, *CreateAssetRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAsset not implemented")
+}
+func (UnimplementedPlantsServiceServer) UpdateAsset(context.Context, *UpdateAssetRequest) (*Asset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAsset not implemented")
+}
+func (UnimplementedPlantsServiceServer) DeleteAsset(context.Context, *DeleteAssetRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAsset not implemented")
+}
+func (UnimplementedPlantsServiceServer) WatchEvents(*WatchEventsRequest, PlantsService_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedPlantsServiceServer) mustEmbedUnimplementedPlantsServiceServer() {}
+
+// UnsafePlantsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlantsServiceServer will
+// result in compilation errors.
+type UnsafePlantsServiceServer interface {
+	mustEmbedUnimplementedPlantsServiceServer()
+}
+
+func RegisterPlantsServiceServer(s grpc.ServiceRegistrar, srv PlantsServiceServer) {
+	s.RegisterService(&PlantsService_ServiceDesc, srv)
+}
+
+func _PlantsService_ListEnergyManagers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEnergyManagersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).ListEnergyManagers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_ListEnergyManagers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).ListEnergyManagers(ctx, req.(*ListEnergyManagersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_GetEnergyManager_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEnergyManagerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).GetEnergyManager(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_GetEnergyManager_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).GetEnergyManager(ctx, req.(*GetEnergyManagerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_CreateEnergyManager_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEnergyManagerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).CreateEnergyManager(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_CreateEnergyManager_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).CreateEnergyManager(ctx, req.(*CreateEnergyManagerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_UpdateEnergyManager_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEnergyManagerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).UpdateEnergyManager(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_UpdateEnergyManager_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).UpdateEnergyManager(ctx, req.(*UpdateEnergyManagerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_DeleteEnergyManager_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEnergyManagerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).DeleteEnergyManager(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_DeleteEnergyManager_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).DeleteEnergyManager(ctx, req.(*DeleteEnergyManagerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_ListEnergyManagerPlants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEnergyManagerPlantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).ListEnergyManagerPlants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_ListEnergyManagerPlants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).ListEnergyManagerPlants(ctx, req.(*ListEnergyManagerPlantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_ListPlants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPlantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).ListPlants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_ListPlants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).ListPlants(ctx, req.(*ListPlantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_GetPlant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).GetPlant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_GetPlant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).GetPlant(ctx, req.(*GetPlantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_CreatePlant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePlantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).CreatePlant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_CreatePlant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).CreatePlant(ctx, req.(*CreatePlantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_UpdatePlant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePlantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).UpdatePlant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_UpdatePlant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).UpdatePlant(ctx, req.(*UpdatePlantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_DeletePlant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePlantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).DeletePlant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_DeletePlant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).DeletePlant(ctx, req.(*DeletePlantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).ListAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_ListAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).ListAssets(ctx, req.(*ListAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_GetAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).GetAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_GetAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).GetAsset(ctx, req.(*GetAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_CreateAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).CreateAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_CreateAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).CreateAsset(ctx, req.(*CreateAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_UpdateAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).UpdateAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_UpdateAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).UpdateAsset(ctx, req.(*UpdateAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_DeleteAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantsServiceServer).DeleteAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantsService_DeleteAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantsServiceServer).DeleteAsset(ctx, req.(*DeleteAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantsService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlantsServiceServer).WatchEvents(m, &plantsServiceWatchEventsServer{stream})
+}
+
+type PlantsService_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type plantsServiceWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *plantsServiceWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PlantsService_ServiceDesc is the grpc.ServiceDesc for PlantsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlantsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plants.v1.PlantsService",
+	HandlerType: (*PlantsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListEnergyManagers",
+			Handler:    _PlantsService_ListEnergyManagers_Handler,
+		},
+		{
+			MethodName: "GetEnergyManager",
+			Handler:    _PlantsService_GetEnergyManager_Handler,
+		},
+		{
+			MethodName: "CreateEnergyManager",
+			Handler:    _PlantsService_CreateEnergyManager_Handler,
+		},
+		{
+			MethodName: "UpdateEnergyManager",
+			Handler:    _PlantsService_UpdateEnergyManager_Handler,
+		},
+		{
+			MethodName: "DeleteEnergyManager",
+			Handler:    _PlantsService_DeleteEnergyManager_Handler,
+		},
+		{
+			MethodName: "ListEnergyManagerPlants",
+			Handler:    _PlantsService_ListEnergyManagerPlants_Handler,
+		},
+		{
+			MethodName: "ListPlants",
+			Handler:    _PlantsService_ListPlants_Handler,
+		},
+		{
+			MethodName: "GetPlant",
+			Handler:    _PlantsService_GetPlant_Handler,
+		},
+		{
+			MethodName: "CreatePlant",
+			Handler:    _PlantsService_CreatePlant_Handler,
+		},
+		{
+			MethodName: "UpdatePlant",
+			Handler:    _PlantsService_UpdatePlant_Handler,
+		},
+		{
+			MethodName: "DeletePlant",
+			Handler:    _PlantsService_DeletePlant_Handler,
+		},
+		{
+			MethodName: "ListAssets",
+			Handler:    _PlantsService_ListAssets_Handler,
+		},
+		{
+			MethodName: "GetAsset",
+			Handler:    _PlantsService_GetAsset_Handler,
+		},
+		{
+			MethodName: "CreateAsset",
+			Handler:    _PlantsService_CreateAsset_Handler,
+		},
+		{
+			MethodName: "UpdateAsset",
+			Handler:    _PlantsService_UpdateAsset_Handler,
+		},
+		{
+			MethodName: "DeleteAsset",
+			Handler:    _PlantsService_DeleteAsset_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _PlantsService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "plants.proto",
+}
@@ -0,0 +1,127 @@
+// Package rpc serves the operations of the plants service over gRPC, next to
+// the HTTP API. The service is defined in plantspb/plants.proto.
+package rpc
+
+//go:generate protoc --go_out=plantspb --go_opt=paths=source_relative --go-grpc_out=plantspb --go-grpc_opt=paths=source_relative -I plantspb plants.proto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/plants"
+	"github.com/jeandeducla/api-plant/internal/rpc/plantspb"
+)
+
+type Server struct {
+    plantspb.UnimplementedPlantsServiceServer
+    plantsService *plants.Service
+}
+
+func NewServer(plantsService *plants.Service) *Server {
+    return &Server{plantsService: plantsService}
+}
+
+// GRPCServer returns a gRPC server the plants service is registered on.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+    g := grpc.NewServer(opts...)
+    plantspb.RegisterPlantsServiceServer(g, s)
+    return g
+}
+
+// invalidArguments are the errors of the service telling that a request is
+// wrong, like the 400 of the HTTP API.
+var invalidArguments = []error{
+    plants.ErrAssetPower,
+    plants.ErrAssetType,
+    plants.ErrAssetMinPower,
+    plants.ErrAssetAgent,
+    plants.ErrAssetAvailability,
+    plants.ErrAssetGroup,
+    plants.ErrAssetGroupPower,
+    plants.ErrNewEmDoesNotExist,
+    plants.ErrEmReassign,
+    plants.ErrEmEmail,
+    plants.ErrEmPhone,
+    plants.ErrEmTimezone,
+    plants.ErrEmLanguage,
+    plants.ErrPlantLocation,
+    plants.ErrPlantCountry,
+    plants.ErrPlantTimezone,
+    plants.ErrPlantTariff,
+    plants.ErrPlantQuery,
+    plants.ErrAssignmentRole,
+    plants.ErrEventEntityType,
+    models.ErrPowerValue,
+}
+
+// statusError converts an error of the service to a gRPC status: NotFound
+// for ErrEmptyResult, FailedPrecondition for the energy managers still
+// assigned to plants, InvalidArgument for the wrong requests and Internal
+// otherwise.
+func statusError(err error) error {
+    if _, ok := status.FromError(err); ok {
+        return err
+    }
+    if errors.Is(err, plants.ErrEmptyResult) {
+        return status.Error(codes.NotFound, err.Error())
+    }
+    if errors.Is(err, plants.ErrEmPlants) {
+        return status.Error(codes.FailedPrecondition, err.Error())
+    }
+    for _, invalid := range invalidArguments {
+        if errors.Is(err, invalid) {
+            return status.Error(codes.InvalidArgument, err.Error())
+        }
+    }
+    return status.Error(codes.Internal, err.Error())
+}
+
+// field is a field of a request and whether it is set.
+type field struct {
+    name string
+    set  bool
+}
+
+// required makes sure the fields a request cannot do without are set, like
+// the `binding:"required"` of the HTTP inputs.
+func required(fields ...field) error {
+    missing := []string{}
+    for _, f := range fields {
+        if !f.set {
+            missing = append(missing, f.name)
+        }
+    }
+    if len(missing) > 0 {
+        return status.Errorf(codes.InvalidArgument, "%s required", strings.Join(missing, ", "))
+    }
+    return nil
+}
+
+func watts(name string, value int64) (models.Power, error) {
+    if value < 0 {
+        return models.Power{}, fmt.Errorf("%w: %s", models.ErrPowerValue, name)
+    }
+    return models.Watts(value), nil
+}
+
+func optionalId(id *uint64) *uint {
+    if id == nil {
+        return nil
+    }
+    res := uint(*id)
+    return &res
+}
+
+func optionalUint64(id *uint) *uint64 {
+    if id == nil {
+        return nil
+    }
+    res := uint64(*id)
+    return &res
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+const (
+    defaultCommandsWait = 30 * time.Second
+    maxCommandsWait     = 60 * time.Second
+)
+
+func (s *Server) handleGetAgents(ctx *gin.Context) {
+    res, err := s.plantsService.GetAllAgents()
+    if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostAgent(ctx *gin.Context) {
+    var input plants.CreateAgentInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err := s.plantsService.CreateAgent(input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetAgent(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetAgent(id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handleDeleteAgent(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    err = s.plantsService.DeleteAgent(id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+// handleGetAgentCommands is long-polled by edge agents: it answers as soon
+// as commands are queued for the agent, or with an empty list once the
+// `wait` query parameter (in seconds) is elapsed.
+func (s *Server) handleGetAgentCommands(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    wait := defaultCommandsWait
+    if param := ctx.Query("wait"); param != "" {
+        seconds, err := strconv.ParseUint(param, 10, 64)
+        if err != nil {
+            ctx.String(http.StatusBadRequest, "")
+            return
+        }
+        wait = time.Duration(seconds) * time.Second
+    }
+    if wait > maxCommandsWait {
+        wait = maxCommandsWait
+    }
+
+    res, err := s.plantsService.FetchAgentCommands(ctx.Request.Context(), id, wait)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostAgentCommandAck(ctx *gin.Context) {
+    agent_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    command_id, err := parseId(ctx, "command_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    err = s.plantsService.AcknowledgeAgentCommand(agent_id, command_id)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrCommandStatus) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handlePostAgentCommandReject(ctx *gin.Context) {
+    agent_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    command_id, err := parseId(ctx, "command_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.RejectCommandInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.RejectAgentCommand(agent_id, command_id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrCommandStatus) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
@@ -22,7 +22,7 @@ func (s *Server) handleGetPlantAssets(ctx *gin.Context) {
         ctx.AbortWithStatus(status)
         return
     }
-    ctx.JSON(http.StatusOK, res)
+    renderResource(ctx, res)
 }
 
 func (s *Server) handlePostAsset(ctx *gin.Context) {
@@ -48,6 +48,21 @@ func (s *Server) handlePostAsset(ctx *gin.Context) {
     } else if errors.Is(err, plants.ErrAssetPower) {
         ctx.AbortWithStatus(400)
         return
+    } else if errors.Is(err, plants.ErrAssetMinPower) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetAgent) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetAvailability) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetGroup) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetGroupPower) {
+        ctx.AbortWithStatus(400)
+        return
     } else if err != nil {
         ctx.AbortWithStatus(500)
         return
@@ -73,7 +88,7 @@ func (s *Server) handleGetPlantAsset(ctx *gin.Context) {
         ctx.AbortWithStatus(status)
         return
     }
-    ctx.JSON(http.StatusOK, res)
+    renderResource(ctx, res)
 }
 
 func (s *Server) handleDeletePlantAsset(ctx *gin.Context) {
@@ -125,9 +140,86 @@ func (s *Server) handlePutPlantAsset(ctx *gin.Context) {
     } else if errors.Is(err, plants.ErrAssetPower) {
         ctx.AbortWithStatus(400)
         return
+    } else if errors.Is(err, plants.ErrAssetMinPower) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetAgent) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetAvailability) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetGroup) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetGroupPower) {
+        ctx.AbortWithStatus(400)
+        return
     } else if err != nil {
         ctx.AbortWithStatus(500)
         return
     }
     ctx.String(http.StatusOK, "")
 }
+
+func (s *Server) handlePostPlantAssetMove(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.MoveAssetInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.MovePlantAsset(plant_id, asset_id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrAssetMoveDestination) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetPower) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetGroup) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetGroupPower) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetPlantAssetMoves(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantAssetMoves(plant_id, asset_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
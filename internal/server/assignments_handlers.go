@@ -0,0 +1,139 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetPlantAssignments(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantAssignments(id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostAssignment(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.CreateAssignmentInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.CreateAssignment(id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrAssignmentRole) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssignmentWindow) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssignmentOverlap) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetPlantAssignment(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    assignment_id, err := parseId(ctx, "assignment_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantAssignment(plant_id, assignment_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handleDeletePlantAssignment(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    assignment_id, err := parseId(ctx, "assignment_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    err = s.plantsService.DeletePlantAssignment(plant_id, assignment_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handlePutPlantAssignment(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    assignment_id, err := parseId(ctx, "assignment_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.UpdateAssignmentInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.UpdatePlantAssignment(plant_id, assignment_id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrAssignmentRole) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssignmentWindow) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssignmentOverlap) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetPlantAssetCommands(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetAssetCommands(plant_id, asset_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostPlantAssetCommand(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.CreateCommandInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.CreateAssetCommand(plant_id, asset_id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrCommandSetpoint) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetNoAgent) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if errors.Is(err, plants.ErrAssetUnavailable) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetPlantAssetCommand(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    command_id, err := parseId(ctx, "command_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetAssetCommand(plant_id, asset_id, command_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostPlantDispatchPlanCommands(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    plan_id, err := parseId(ctx, "plan_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    err = s.plantsService.QueueDispatchPlanCommands(plant_id, plan_id)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrCommandSetpoint) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrAssetNoAgent) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if errors.Is(err, plants.ErrAssetUnavailable) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetPlantConnections(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantConnections(id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostConnection(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.CreateConnectionInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.CreateConnection(id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrConnectionVoltage) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrConnectionWindow) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrConnectionOverlap) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetPlantConnection(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    connection_id, err := parseId(ctx, "connection_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantConnection(plant_id, connection_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handleDeletePlantConnection(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    connection_id, err := parseId(ctx, "connection_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    err = s.plantsService.DeletePlantConnection(plant_id, connection_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handlePutPlantConnection(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    connection_id, err := parseId(ctx, "connection_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.UpdateConnectionInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.UpdatePlantConnection(plant_id, connection_id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrConnectionVoltage) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrConnectionWindow) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrConnectionOverlap) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+// handleGetPlantCompliance checks the plant against its grid connection,
+// including the load measured between from and to (the last 24 hours by
+// default).
+func (s *Server) handleGetPlantCompliance(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetPlantCompliance(id, from, to)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/dto"
+)
+
+// The contracts of the responses, chosen with the Accept header. Version 1
+// renders the frozen response bodies of the dto/v1 package, with the
+// PascalCase keys of the models; version 2 renders those of the dto package,
+// with snake_case keys and sparse fieldsets.
+const (
+    mediaTypeV1 = "application/vnd.api-plant.v1+json"
+    mediaTypeV2 = "application/vnd.api-plant.v2+json"
+    mediaTypePrefix = "application/vnd.api-plant."
+)
+
+var (
+    ErrMediaType = errors.New("Accept must allow one of the media types of the API")
+)
+
+// apiVersionKey is the key of the version of the contract a route group is
+// pinned to, in the context of its requests.
+const apiVersionKey = "api_version"
+
+// Version 1 is deprecated since v1Deprecation, and to be removed at v1Sunset.
+var (
+    v1Deprecation = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+    v1Sunset = time.Date(2027, time.October, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// pinAPIVersion serves the requests of a route group in a version of the
+// contract, whatever their Accept header.
+func pinAPIVersion(version int) gin.HandlerFunc {
+    return func(ctx *gin.Context) {
+        ctx.Set(apiVersionKey, version)
+    }
+}
+
+// deprecateV1 tells the clients of version 1 when it goes away, with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and where the route
+// is in version 2.
+func deprecateV1(ctx *gin.Context) {
+    path := strings.TrimPrefix(ctx.Request.URL.Path, "/v1")
+    ctx.Header("Deprecation", fmt.Sprintf("@%d", v1Deprecation.Unix()))
+    ctx.Header("Sunset", v1Sunset.Format(http.TimeFormat))
+    ctx.Header("Link", fmt.Sprintf("</v2%s>; rel=\"successor-version\"", path))
+}
+
+// apiVersion returns the version of the contract a request is served in: the
+// one of its route group, or else the one its Accept header asks for, 1 when
+// it does not ask for one.
+func apiVersion(ctx *gin.Context) (int, error) {
+    if version, ok := ctx.Get(apiVersionKey); ok {
+        return version.(int), nil
+    }
+    header := ctx.GetHeader("Accept")
+    if header == "" {
+        return 1, nil
+    }
+    vendor_only := true
+    for _, accepted := range strings.Split(header, ",") {
+        media_type, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+        if err != nil {
+            continue
+        }
+        switch media_type {
+        case mediaTypeV2:
+            return 2, nil
+        case mediaTypeV1:
+            return 1, nil
+        }
+        vendor_only = vendor_only && strings.HasPrefix(media_type, mediaTypePrefix)
+    }
+    if vendor_only {
+        return 0, ErrMediaType
+    }
+    return 1, nil
+}
+
+// renderResource responds the result of a handler in the contract the
+// request accepts. Version 2 keeps the fields query parameter, like
+// `fields=name,max_power`, and the ids of the resources.
+func renderResource(ctx *gin.Context, res interface{}) {
+    ctx.Header("Vary", "Accept")
+    version, err := apiVersion(ctx)
+    if err != nil {
+        ctx.String(http.StatusNotAcceptable, "")
+        return
+    }
+    fields, err := dto.ParseFields(ctx.Query("fields"))
+    if err != nil || (version == 1 && fields != nil) {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+    if version == 1 {
+        renderJSON(ctx, newBody(1, res))
+        return
+    }
+
+    body := newBody(2, res)
+    if !renderPowerIn(ctx, body) {
+        return
+    }
+    selected, err := dto.Select(body, fields)
+    if errors.Is(err, dto.ErrFields) {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.Header("Content-Type", mediaTypeV2)
+    ctx.JSON(http.StatusOK, selected)
+}
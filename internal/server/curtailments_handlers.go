@@ -0,0 +1,159 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetPlantCurtailments(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantCurtailments(id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostCurtailment(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.CreateCurtailmentInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.CreateCurtailment(id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrCurtailmentWindow) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrCurtailmentPower) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetPlantCurtailment(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    curtailment_id, err := parseId(ctx, "curtailment_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantCurtailment(plant_id, curtailment_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handleDeletePlantCurtailment(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    curtailment_id, err := parseId(ctx, "curtailment_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    err = s.plantsService.DeletePlantCurtailment(plant_id, curtailment_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handlePutPlantCurtailmentStatus(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    curtailment_id, err := parseId(ctx, "curtailment_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.UpdateCurtailmentStatusInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.UpdatePlantCurtailmentStatus(plant_id, curtailment_id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrCurtailmentStatus) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrCurtailmentTransition) {
+        ctx.AbortWithStatus(409)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetPlantCurtailmentEvaluation(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    curtailment_id, err := parseId(ctx, "curtailment_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.EvaluatePlantCurtailment(plant_id, curtailment_id)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrNotEnoughMeasurements) {
+        ctx.AbortWithStatus(422)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    renderResource(ctx, res)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetPlantDispatchPlans(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantDispatchPlans(id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+// handlePostDispatchPlan answers with the computed plan, which is only
+// saved when the dry_run query parameter is not set.
+func (s *Server) handlePostDispatchPlan(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    dryRun := false
+    if param := ctx.Query("dry_run"); param != "" {
+        dryRun, err = strconv.ParseBool(param)
+        if err != nil {
+            ctx.String(http.StatusBadRequest, "")
+            return
+        }
+    }
+
+    var input plants.CreateDispatchPlanInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.CreateDispatchPlan(id, input, dryRun)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrDispatchTarget) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrCurtailmentWindow) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrDispatchFlexibility) {
+        ctx.AbortWithStatus(422)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handleGetPlantDispatchPlan(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    plan_id, err := parseId(ctx, "plan_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetPlantDispatchPlan(plant_id, plan_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetEmissionFactors(ctx *gin.Context) {
+    res, err := s.plantsService.GetStaticEmissionFactors()
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePutEmissionFactor(ctx *gin.Context) {
+    var input plants.SetEmissionFactorInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err := s.plantsService.SetStaticEmissionFactor(ctx.Param("zone"), input)
+    if errors.Is(err, plants.ErrEmissionZone) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrEmissionFactor) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleDeleteEmissionFactor(ctx *gin.Context) {
+    err := s.plantsService.DeleteStaticEmissionFactor(ctx.Param("zone"))
+    if errors.Is(err, plants.ErrEmissionZone) {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetHourlyEmissionFactors(ctx *gin.Context) {
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetHourlyEmissionFactors(ctx.Param("zone"), from, to)
+    if errors.Is(err, plants.ErrEmissionZone) {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+// handlePostHourlyEmissionFactors imports the CSV time series sent as the
+// request body.
+func (s *Server) handlePostHourlyEmissionFactors(ctx *gin.Context) {
+    err := s.plantsService.ImportEmissionFactors(ctx.Param("zone"), ctx.Request.Body)
+    if errors.Is(err, plants.ErrEmissionZone) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrEmissionFactor) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrEmissionFactorsCSV) {
+        ctx.String(http.StatusBadRequest, err.Error())
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleGetPlantEmissions(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetPlantEmissions(id, from, to)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+// handleGetEnergyManagerEmissions rolls up the emissions of the plants an
+// energy manager is the primary manager of, or has the role given by the
+// role query parameter on.
+func (s *Server) handleGetEnergyManagerEmissions(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetEnergyManagerEmissions(id, ctx.DefaultQuery("role", plants.AssignmentPrimary), from, to)
+    if errors.Is(err, plants.ErrAssignmentRole) {
+        ctx.AbortWithStatus(400)
+        return
+    }
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
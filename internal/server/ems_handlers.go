@@ -1,8 +1,10 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -16,7 +18,7 @@ func (s *Server) handleGetEnergyManagers(ctx *gin.Context) {
         return
     }
     fmt.Println(res)
-    ctx.JSON(http.StatusOK, res)
+    renderResource(ctx, res)
 }
 
 func (s *Server) handleGetEnergyManager(ctx *gin.Context) {
@@ -26,13 +28,22 @@ func (s *Server) handleGetEnergyManager(ctx *gin.Context) {
         return
     }
     
-    res, err := s.plantsService.GetEnergyManager(id)
+    include, err := plants.ParseInclude(ctx.Query("include"))
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+    res, err := s.plantsService.GetEnergyManagerIncluding(id, include)
+    if isIncludeError(err) {
+        ctx.AbortWithStatus(400)
+        return
+    }
     status, err := matchError(err)
     if err != nil {
         ctx.AbortWithStatus(status)
         return
     }
-    ctx.JSON(http.StatusOK, res)
+    renderResource(ctx, res)
 }
 
 func (s *Server) handleDeleteEnergyManager(ctx *gin.Context) {
@@ -42,7 +53,36 @@ func (s *Server) handleDeleteEnergyManager(ctx *gin.Context) {
         return
     }
 
-    err = s.plantsService.DeleteEnergyManager(id)
+    var reassign_to *uint
+    if param := ctx.Query("reassign_to"); param != "" {
+        em_id, err := strconv.ParseUint(param, 10, 64)
+        if err != nil {
+            ctx.String(http.StatusBadRequest, "")
+            return
+        }
+        reassign_to = new(uint)
+        *reassign_to = uint(em_id)
+    }
+
+    plants_left, err := s.plantsService.DeleteEnergyManager(id, reassign_to)
+    if errors.Is(err, plants.ErrEmPlants) {
+        // the plants to reassign are listed, in the contract the request
+        // accepts
+        ctx.Header("Vary", "Accept")
+        version, err := apiVersion(ctx)
+        if err != nil {
+            ctx.String(http.StatusNotAcceptable, "")
+            return
+        }
+        if version == 2 {
+            ctx.Header("Content-Type", mediaTypeV2)
+        }
+        ctx.JSON(http.StatusConflict, gin.H{"plants": newBody(version, plants_left)})
+        return
+    } else if errors.Is(err, plants.ErrEmReassign) {
+        ctx.AbortWithStatus(400)
+        return
+    }
     status, err := matchError(err)
     if err != nil {
         ctx.AbortWithStatus(status)
@@ -59,6 +99,10 @@ func (s *Server) handlePostEnergyManager(ctx *gin.Context) {
     }
 
     err := s.plantsService.CreateEnergyManager(input)
+    if isContactDetailsError(err) {
+        ctx.AbortWithStatus(400)
+        return
+    }
     status, err := matchError(err)
     if err != nil {
         ctx.AbortWithStatus(status)
@@ -81,6 +125,10 @@ func (s *Server) handlePutEnergyManager(ctx *gin.Context) {
     }
 
     err = s.plantsService.UpdateEnergyManager(id, input)
+    if isContactDetailsError(err) {
+        ctx.AbortWithStatus(400)
+        return
+    }
     status, err := matchError(err)
     if err != nil {
         ctx.AbortWithStatus(status)
@@ -89,6 +137,9 @@ func (s *Server) handlePutEnergyManager(ctx *gin.Context) {
     ctx.String(http.StatusOK, "")
 }
 
+// handleGetEnergyManagerPlants lists the plants an energy manager is
+// currently assigned to, whatever the role unless the role query parameter
+// is set.
 func (s *Server) handleGetEnergyManagerPlants(ctx *gin.Context) {
     id, err := parseId(ctx, "id")
     if err != nil {
@@ -96,12 +147,69 @@ func (s *Server) handleGetEnergyManagerPlants(ctx *gin.Context) {
         return
     }
 
-    res, err := s.plantsService.GetEnergyManagerPlants(id)
+    res, err := s.plantsService.GetEnergyManagerPlants(id, ctx.Query("role"))
+    if errors.Is(err, plants.ErrAssignmentRole) {
+        ctx.AbortWithStatus(400)
+        return
+    }
     status, err := matchError(err)
     if err != nil {
         ctx.AbortWithStatus(status)
         return
     }
-    ctx.JSON(http.StatusOK, res)
+    renderResource(ctx, res)
 }
 
+
+func isContactDetailsError(err error) bool {
+    return errors.Is(err, plants.ErrEmEmail) ||
+        errors.Is(err, plants.ErrEmPhone) ||
+        errors.Is(err, plants.ErrEmTimezone) ||
+        errors.Is(err, plants.ErrEmLanguage)
+}
+
+func (s *Server) handleGetEnergyManagerNotifications(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetEnergyManagerNotificationPreferences(id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePutEnergyManagerNotifications(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.UpdateNotificationPreferencesInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.UpdateEnergyManagerNotificationPreferences(id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrNotificationChannel) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrNotificationTarget) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
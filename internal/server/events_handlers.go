@@ -0,0 +1,109 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+// eventsHeartbeat is how often a comment is sent on an idle event stream, so
+// that proxies keep it open.
+const eventsHeartbeat = 15 * time.Second
+
+// parseEventFilter reads the `plant_id`, `em_id` and `type` (comma separated
+// entity types) query parameters.
+func parseEventFilter(ctx *gin.Context) (plants.EventFilter, error) {
+    var filter plants.EventFilter
+    for name, id := range map[string]**uint{"plant_id": &filter.PlantID, "em_id": &filter.EnergyManagerID} {
+        if param := ctx.Query(name); param != "" {
+            value, err := strconv.ParseUint(param, 10, 64)
+            if err != nil {
+                return filter, err
+            }
+            *id = new(uint)
+            **id = uint(value)
+        }
+    }
+    if param := ctx.Query("type"); param != "" {
+        filter.EntityTypes = strings.Split(param, ",")
+    }
+    return filter, nil
+}
+
+// handleGetEvents streams the events of the log as Server-Sent Events. A
+// client reconnecting with a Last-Event-ID header first gets the events it
+// missed, others only get the events to come. The data of the events is in
+// the contract the request accepts, as renderResource would respond it.
+func (s *Server) handleGetEvents(ctx *gin.Context) {
+    ctx.Header("Vary", "Accept")
+    version, err := apiVersion(ctx)
+    if err != nil {
+        ctx.String(http.StatusNotAcceptable, "")
+        return
+    }
+    filter, err := parseEventFilter(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+    err = s.plantsService.CheckEventFilter(filter)
+    if errors.Is(err, plants.ErrEventEntityType) {
+        ctx.AbortWithStatus(400)
+        return
+    }
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+
+    var after uint
+    if header := ctx.GetHeader("Last-Event-ID"); header != "" {
+        id, err := strconv.ParseUint(header, 10, 64)
+        if err != nil {
+            ctx.String(http.StatusBadRequest, "")
+            return
+        }
+        after = uint(id)
+    } else {
+        after, err = s.plantsService.LastEventID()
+        if err != nil {
+            ctx.AbortWithStatus(500)
+            return
+        }
+    }
+
+    ctx.Header("Content-Type", "text/event-stream")
+    ctx.Header("Cache-Control", "no-cache")
+    ctx.Header("X-Accel-Buffering", "no")
+    ctx.Status(http.StatusOK)
+    ctx.Writer.Flush()
+    ctx.Stream(func(w io.Writer) bool {
+        events, err := s.plantsService.WaitEvents(ctx.Request.Context(), filter, after, eventsHeartbeat)
+        if err != nil || ctx.Request.Context().Err() != nil {
+            return false
+        }
+        if len(events) == 0 {
+            fmt.Fprint(w, ": heartbeat\n\n")
+            return true
+        }
+        for i, event := range events {
+            sse.Encode(w, sse.Event{
+                Id: strconv.FormatUint(uint64(event.ID), 10),
+                Event: event.Type,
+                Data: newBody(version, &events[i]),
+            })
+            after = event.ID
+        }
+        return true
+    })
+}
@@ -0,0 +1,659 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/jeandeducla/api-plant/internal/models"
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+const (
+    // graphqlPageSize is the number of items of a list when `first` is not
+    // given, graphqlMaxPageSize the most a list can have.
+    graphqlPageSize = 50
+    graphqlMaxPageSize = 200
+    // graphqlMaxDepth bounds how deep a query can follow relationships.
+    graphqlMaxDepth = 8
+)
+
+var (
+    ErrGraphQLID = errors.New("ID must be a positive integer")
+    ErrGraphQLFirst = errors.New("first must be between 0 and 200")
+)
+
+// graphqlSchema exposes energy managers, plants and assets and their
+// relationships. Powers are numbers of kW. Lists are ordered by id and paged
+// with `first` and `after`, the id of the last item of the previous page.
+const graphqlSchema = `
+schema {
+    query: Query
+    mutation: Mutation
+}
+
+type Query {
+    energyManager(id: ID!): EnergyManager
+    energyManagers(first: Int, after: ID): [EnergyManager!]!
+    plant(id: ID!): Plant
+    plants(gridZone: String, countryCode: String, first: Int, after: ID): [Plant!]!
+}
+
+type Mutation {
+    createEnergyManager(input: EnergyManagerInput!): Boolean!
+    updateEnergyManager(id: ID!, input: EnergyManagerInput!): EnergyManager!
+    deleteEnergyManager(id: ID!, reassignTo: ID): Boolean!
+    createPlant(input: PlantInput!): Boolean!
+    updatePlant(id: ID!, input: PlantInput!): Plant!
+    deletePlant(id: ID!): Boolean!
+    createAsset(plantId: ID!, input: AssetInput!): Boolean!
+    updateAsset(plantId: ID!, id: ID!, input: AssetInput!): Asset!
+    deleteAsset(plantId: ID!, id: ID!): Boolean!
+}
+
+type EnergyManager {
+    id: ID!
+    name: String!
+    surname: String!
+    email: String!
+    phone: String!
+    timezone: String!
+    language: String!
+    plants(role: String, first: Int, after: ID): [Plant!]!
+}
+
+type Plant {
+    id: ID!
+    name: String!
+    address: String!
+    maxPower: Float!
+    latitude: Float
+    longitude: Float
+    gridZone: String!
+    countryCode: String!
+    timezone: String!
+    tariffId: ID
+    energyManagers(role: String): [EnergyManager!]!
+    assets(type: String, availability: String, first: Int, after: ID): [Asset!]!
+}
+
+type Asset {
+    id: ID!
+    name: String!
+    type: String!
+    availability: String!
+    maxPower: Float!
+    minPower: Float!
+    priority: Int!
+    plantId: ID!
+    groupId: ID
+    agentId: ID
+}
+
+input EnergyManagerInput {
+    name: String!
+    surname: String!
+    email: String
+    phone: String
+    timezone: String
+    language: String
+}
+
+input PlantInput {
+    name: String!
+    address: String!
+    maxPower: Float!
+    energyManagerId: ID!
+    latitude: Float
+    longitude: Float
+    gridZone: String
+    countryCode: String
+    timezone: String
+    tariffId: ID
+}
+
+input AssetInput {
+    name: String!
+    type: String!
+    maxPower: Float!
+    minPower: Float
+    rampUpRate: Int
+    rampDownRate: Int
+    minRunTime: Int
+    minOffTime: Int
+    maxEventsPerDay: Int
+    noticePeriod: Int
+    priority: Int
+    agentId: ID
+    groupId: ID
+    availability: String
+}
+`
+
+func newGraphQLSchema(service *plants.Service) *graphql.Schema {
+    return graphql.MustParseSchema(graphqlSchema, &graphqlResolver{service: service}, graphql.MaxDepth(graphqlMaxDepth))
+}
+
+type loadersKey struct{}
+
+// loaders returns the loaders of the request, which batch the reads of the
+// relationships.
+func loaders(ctx context.Context) *plants.Loaders {
+    return ctx.Value(loadersKey{}).(*plants.Loaders)
+}
+
+func parseGraphQLID(id graphql.ID) (uint, error) {
+    value, err := strconv.ParseUint(string(id), 10, 64)
+    if err != nil || value == 0 {
+        return 0, ErrGraphQLID
+    }
+    return uint(value), nil
+}
+
+func parseOptionalGraphQLID(id *graphql.ID) (*uint, error) {
+    if id == nil {
+        return nil, nil
+    }
+    value, err := parseGraphQLID(*id)
+    if err != nil {
+        return nil, err
+    }
+    return &value, nil
+}
+
+func graphqlID(id uint) graphql.ID {
+    return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func optionalGraphQLID(id *uint) *graphql.ID {
+    if id == nil {
+        return nil
+    }
+    value := graphqlID(*id)
+    return &value
+}
+
+func optionalString(s *string) string {
+    if s == nil {
+        return ""
+    }
+    return *s
+}
+
+func optionalUint(i *int32) uint {
+    if i == nil || *i < 0 {
+        return 0
+    }
+    return uint(*i)
+}
+
+// graphqlPower reads a number of kW, to the watt.
+func graphqlPower(kw *float64) (models.Power, error) {
+    if kw == nil {
+        return models.Power{}, nil
+    }
+    return models.NewPower(strconv.FormatFloat(*kw, 'f', -1, 64), models.KW)
+}
+
+// page returns the `first` items of a list ordered by id after the item
+// `after`.
+func page[T any](items []T, id func(T) uint, first *int32, after *graphql.ID) ([]T, error) {
+    size := graphqlPageSize
+    if first != nil {
+        if *first < 0 || *first > graphqlMaxPageSize {
+            return nil, ErrGraphQLFirst
+        }
+        size = int(*first)
+    }
+    start := 0
+    if after != nil {
+        after_id, err := parseGraphQLID(*after)
+        if err != nil {
+            return nil, err
+        }
+        for start < len(items) && id(items[start]) <= after_id {
+            start++
+        }
+    }
+    items = items[start:]
+    if len(items) > size {
+        items = items[:size]
+    }
+    return items, nil
+}
+
+type graphqlResolver struct {
+    service *plants.Service
+}
+
+func (r *graphqlResolver) EnergyManager(ctx context.Context, args struct{ ID graphql.ID }) (*emResolver, error) {
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return nil, err
+    }
+    em, err := r.service.GetEnergyManager(id)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        return nil, nil
+    } else if err != nil {
+        return nil, err
+    }
+    return newEmResolvers(ctx, []models.EnergyManager{*em})[0], nil
+}
+
+func (r *graphqlResolver) EnergyManagers(ctx context.Context, args struct {
+    First *int32
+    After *graphql.ID
+}) ([]*emResolver, error) {
+    ems, err := r.service.GetAllEnergyManagers()
+    if err != nil && !errors.Is(err, plants.ErrEmptyResult) {
+        return nil, err
+    }
+    ems, err = page(ems, func(em models.EnergyManager) uint { return em.ID }, args.First, args.After)
+    if err != nil {
+        return nil, err
+    }
+    return newEmResolvers(ctx, ems), nil
+}
+
+func (r *graphqlResolver) Plant(ctx context.Context, args struct{ ID graphql.ID }) (*plantResolver, error) {
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return nil, err
+    }
+    plant, err := r.service.GetPlant(id)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        return nil, nil
+    } else if err != nil {
+        return nil, err
+    }
+    return newPlantResolvers(ctx, []models.Plant{*plant})[0], nil
+}
+
+func (r *graphqlResolver) Plants(ctx context.Context, args struct {
+    GridZone    *string
+    CountryCode *string
+    First       *int32
+    After       *graphql.ID
+}) ([]*plantResolver, error) {
+    list, err := r.service.SearchPlants(plants.PlantQuery{
+        GridZone: optionalString(args.GridZone),
+        CountryCode: optionalString(args.CountryCode),
+    })
+    if err != nil && !errors.Is(err, plants.ErrEmptyResult) {
+        return nil, err
+    }
+    list, err = page(list, func(plant models.Plant) uint { return plant.ID }, args.First, args.After)
+    if err != nil {
+        return nil, err
+    }
+    return newPlantResolvers(ctx, list), nil
+}
+
+type emResolver struct {
+    em models.EnergyManager
+}
+
+// newEmResolvers resolves a list of energy managers, whose relationships are
+// then read together.
+func newEmResolvers(ctx context.Context, ems []models.EnergyManager) []*emResolver {
+    loaders(ctx).AddEnergyManagers(ems)
+    res := make([]*emResolver, len(ems))
+    for i := range ems {
+        res[i] = &emResolver{em: ems[i]}
+    }
+    return res
+}
+
+func (r *emResolver) ID() graphql.ID   { return graphqlID(r.em.ID) }
+func (r *emResolver) Name() string     { return r.em.Name }
+func (r *emResolver) Surname() string  { return r.em.Surname }
+func (r *emResolver) Email() string    { return r.em.Email }
+func (r *emResolver) Phone() string    { return r.em.Phone }
+func (r *emResolver) Timezone() string { return r.em.Timezone }
+func (r *emResolver) Language() string { return r.em.Language }
+
+func (r *emResolver) Plants(ctx context.Context, args struct {
+    Role  *string
+    First *int32
+    After *graphql.ID
+}) ([]*plantResolver, error) {
+    list, err := loaders(ctx).EnergyManagerPlants(r.em.ID, optionalString(args.Role))
+    if err != nil {
+        return nil, err
+    }
+    list, err = page(list, func(plant models.Plant) uint { return plant.ID }, args.First, args.After)
+    if err != nil {
+        return nil, err
+    }
+    return newPlantResolvers(ctx, list), nil
+}
+
+type plantResolver struct {
+    plant models.Plant
+}
+
+// newPlantResolvers resolves a list of plants, whose relationships are then
+// read together.
+func newPlantResolvers(ctx context.Context, list []models.Plant) []*plantResolver {
+    loaders(ctx).AddPlants(list)
+    res := make([]*plantResolver, len(list))
+    for i := range list {
+        res[i] = &plantResolver{plant: list[i]}
+    }
+    return res
+}
+
+func (r *plantResolver) ID() graphql.ID          { return graphqlID(r.plant.ID) }
+func (r *plantResolver) Name() string            { return r.plant.Name }
+func (r *plantResolver) Address() string         { return r.plant.Address }
+func (r *plantResolver) MaxPower() float64       { return r.plant.MaxPower.Kilowatts() }
+func (r *plantResolver) Latitude() *float64      { return r.plant.Latitude }
+func (r *plantResolver) Longitude() *float64     { return r.plant.Longitude }
+func (r *plantResolver) GridZone() string        { return r.plant.GridZone }
+func (r *plantResolver) CountryCode() string     { return r.plant.CountryCode }
+func (r *plantResolver) Timezone() string        { return r.plant.Timezone }
+func (r *plantResolver) TariffId() *graphql.ID   { return optionalGraphQLID(r.plant.TariffID) }
+
+func (r *plantResolver) EnergyManagers(ctx context.Context, args struct{ Role *string }) ([]*emResolver, error) {
+    ems, err := loaders(ctx).PlantEnergyManagers(r.plant.ID, optionalString(args.Role))
+    if err != nil {
+        return nil, err
+    }
+    return newEmResolvers(ctx, ems), nil
+}
+
+func (r *plantResolver) Assets(ctx context.Context, args struct {
+    Type         *string
+    Availability *string
+    First        *int32
+    After        *graphql.ID
+}) ([]*assetResolver, error) {
+    assets, err := loaders(ctx).PlantAssets(r.plant.ID)
+    if err != nil {
+        return nil, err
+    }
+    matching := []models.Asset{}
+    for _, asset := range assets {
+        if args.Type != nil && asset.Type != *args.Type {
+            continue
+        }
+        if args.Availability != nil && asset.Availability != *args.Availability {
+            continue
+        }
+        matching = append(matching, asset)
+    }
+    matching, err = page(matching, func(asset models.Asset) uint { return asset.ID }, args.First, args.After)
+    if err != nil {
+        return nil, err
+    }
+    res := make([]*assetResolver, len(matching))
+    for i := range matching {
+        res[i] = &assetResolver{asset: matching[i]}
+    }
+    return res, nil
+}
+
+type assetResolver struct {
+    asset models.Asset
+}
+
+func (r *assetResolver) ID() graphql.ID         { return graphqlID(r.asset.ID) }
+func (r *assetResolver) Name() string           { return r.asset.Name }
+func (r *assetResolver) Type() string           { return r.asset.Type }
+func (r *assetResolver) Availability() string   { return r.asset.Availability }
+func (r *assetResolver) MaxPower() float64      { return r.asset.MaxPower.Kilowatts() }
+func (r *assetResolver) MinPower() float64      { return r.asset.MinPower.Kilowatts() }
+func (r *assetResolver) Priority() int32        { return int32(r.asset.Priority) }
+func (r *assetResolver) PlantId() graphql.ID    { return graphqlID(r.asset.PlantID) }
+func (r *assetResolver) GroupId() *graphql.ID   { return optionalGraphQLID(r.asset.GroupID) }
+func (r *assetResolver) AgentId() *graphql.ID   { return optionalGraphQLID(r.asset.AgentID) }
+
+// the mutations go through the same service methods as the REST routes
+
+type energyManagerInput struct {
+    Name     string
+    Surname  string
+    Email    *string
+    Phone    *string
+    Timezone *string
+    Language *string
+}
+
+func (r *graphqlResolver) CreateEnergyManager(args struct{ Input energyManagerInput }) (bool, error) {
+    err := r.service.CreateEnergyManager(plants.CreateEnergyManagerInput{
+        Name: args.Input.Name,
+        Surname: args.Input.Surname,
+        Email: optionalString(args.Input.Email),
+        Phone: optionalString(args.Input.Phone),
+        Timezone: optionalString(args.Input.Timezone),
+        Language: optionalString(args.Input.Language),
+    })
+    return err == nil, err
+}
+
+func (r *graphqlResolver) UpdateEnergyManager(ctx context.Context, args struct {
+    ID    graphql.ID
+    Input energyManagerInput
+}) (*emResolver, error) {
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return nil, err
+    }
+    err = r.service.UpdateEnergyManager(id, plants.UpdateEnergyManagerInput{
+        Name: args.Input.Name,
+        Surname: args.Input.Surname,
+        Email: optionalString(args.Input.Email),
+        Phone: optionalString(args.Input.Phone),
+        Timezone: optionalString(args.Input.Timezone),
+        Language: optionalString(args.Input.Language),
+    })
+    if err != nil {
+        return nil, err
+    }
+    return r.EnergyManager(ctx, struct{ ID graphql.ID }{args.ID})
+}
+
+func (r *graphqlResolver) DeleteEnergyManager(args struct {
+    ID         graphql.ID
+    ReassignTo *graphql.ID
+}) (bool, error) {
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return false, err
+    }
+    reassign_to, err := parseOptionalGraphQLID(args.ReassignTo)
+    if err != nil {
+        return false, err
+    }
+    _, err = r.service.DeleteEnergyManager(id, reassign_to)
+    return err == nil, err
+}
+
+type plantInput struct {
+    Name            string
+    Address         string
+    MaxPower        float64
+    EnergyManagerId graphql.ID
+    Latitude        *float64
+    Longitude       *float64
+    GridZone        *string
+    CountryCode     *string
+    Timezone        *string
+    TariffId        *graphql.ID
+}
+
+func (r *graphqlResolver) CreatePlant(args struct{ Input plantInput }) (bool, error) {
+    input, err := args.Input.updatePlantInput()
+    if err != nil {
+        return false, err
+    }
+    err = r.service.CreatePlant(plants.CreatePlantInput(input))
+    return err == nil, err
+}
+
+func (r *graphqlResolver) UpdatePlant(ctx context.Context, args struct {
+    ID    graphql.ID
+    Input plantInput
+}) (*plantResolver, error) {
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return nil, err
+    }
+    input, err := args.Input.updatePlantInput()
+    if err != nil {
+        return nil, err
+    }
+    if err := r.service.UpdatePlant(id, input); err != nil {
+        return nil, err
+    }
+    return r.Plant(ctx, struct{ ID graphql.ID }{args.ID})
+}
+
+func (i plantInput) updatePlantInput() (plants.UpdatePlantInput, error) {
+    max_power, err := graphqlPower(&i.MaxPower)
+    if err != nil {
+        return plants.UpdatePlantInput{}, err
+    }
+    em_id, err := parseGraphQLID(i.EnergyManagerId)
+    if err != nil {
+        return plants.UpdatePlantInput{}, err
+    }
+    tariff_id, err := parseOptionalGraphQLID(i.TariffId)
+    if err != nil {
+        return plants.UpdatePlantInput{}, err
+    }
+    return plants.UpdatePlantInput{
+        Name: i.Name,
+        Address: i.Address,
+        MaxPower: max_power,
+        EnergyManagerID: em_id,
+        Latitude: i.Latitude,
+        Longitude: i.Longitude,
+        GridZone: optionalString(i.GridZone),
+        CountryCode: optionalString(i.CountryCode),
+        Timezone: optionalString(i.Timezone),
+        TariffID: tariff_id,
+    }, nil
+}
+
+func (r *graphqlResolver) DeletePlant(args struct{ ID graphql.ID }) (bool, error) {
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return false, err
+    }
+    err = r.service.DeletePlant(id)
+    return err == nil, err
+}
+
+type assetInput struct {
+    Name            string
+    Type            string
+    MaxPower        float64
+    MinPower        *float64
+    RampUpRate      *int32
+    RampDownRate    *int32
+    MinRunTime      *int32
+    MinOffTime      *int32
+    MaxEventsPerDay *int32
+    NoticePeriod    *int32
+    Priority        *int32
+    AgentId         *graphql.ID
+    GroupId         *graphql.ID
+    Availability    *string
+}
+
+func (i assetInput) updateAssetInput() (plants.UpdateAssetInput, error) {
+    max_power, err := graphqlPower(&i.MaxPower)
+    if err != nil {
+        return plants.UpdateAssetInput{}, err
+    }
+    min_power, err := graphqlPower(i.MinPower)
+    if err != nil {
+        return plants.UpdateAssetInput{}, err
+    }
+    agent_id, err := parseOptionalGraphQLID(i.AgentId)
+    if err != nil {
+        return plants.UpdateAssetInput{}, err
+    }
+    group_id, err := parseOptionalGraphQLID(i.GroupId)
+    if err != nil {
+        return plants.UpdateAssetInput{}, err
+    }
+    return plants.UpdateAssetInput{
+        Name: i.Name,
+        MaxPower: max_power,
+        Type: i.Type,
+        MinPower: min_power,
+        RampUpRate: optionalUint(i.RampUpRate),
+        RampDownRate: optionalUint(i.RampDownRate),
+        MinRunTime: optionalUint(i.MinRunTime),
+        MinOffTime: optionalUint(i.MinOffTime),
+        MaxEventsPerDay: optionalUint(i.MaxEventsPerDay),
+        NoticePeriod: optionalUint(i.NoticePeriod),
+        Priority: optionalUint(i.Priority),
+        AgentID: agent_id,
+        GroupID: group_id,
+        Availability: optionalString(i.Availability),
+    }, nil
+}
+
+func (r *graphqlResolver) CreateAsset(args struct {
+    PlantId graphql.ID
+    Input   assetInput
+}) (bool, error) {
+    plant_id, err := parseGraphQLID(args.PlantId)
+    if err != nil {
+        return false, err
+    }
+    input, err := args.Input.updateAssetInput()
+    if err != nil {
+        return false, err
+    }
+    err = r.service.CreateAsset(plant_id, plants.CreateAssetInput(input))
+    return err == nil, err
+}
+
+func (r *graphqlResolver) UpdateAsset(args struct {
+    PlantId graphql.ID
+    ID      graphql.ID
+    Input   assetInput
+}) (*assetResolver, error) {
+    plant_id, err := parseGraphQLID(args.PlantId)
+    if err != nil {
+        return nil, err
+    }
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return nil, err
+    }
+    input, err := args.Input.updateAssetInput()
+    if err != nil {
+        return nil, err
+    }
+    if err := r.service.UpdatePlantAsset(plant_id, id, input); err != nil {
+        return nil, err
+    }
+    asset, err := r.service.GetPlantAsset(plant_id, id)
+    if err != nil {
+        return nil, err
+    }
+    return &assetResolver{asset: *asset}, nil
+}
+
+func (r *graphqlResolver) DeleteAsset(args struct {
+    PlantId graphql.ID
+    ID      graphql.ID
+}) (bool, error) {
+    plant_id, err := parseGraphQLID(args.PlantId)
+    if err != nil {
+        return false, err
+    }
+    id, err := parseGraphQLID(args.ID)
+    if err != nil {
+        return false, err
+    }
+    err = r.service.DeletePlantAsset(plant_id, id)
+    return err == nil, err
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type graphqlRequest struct {
+    Query         string                 `json:"query"         binding:"required"`
+    OperationName string                 `json:"operationName"`
+    Variables     map[string]interface{} `json:"variables"`
+}
+
+// handlePostGraphQL runs a GraphQL query or mutation. Like any GraphQL
+// server, it answers 200 along with the errors of the fields which failed.
+func (s *Server) handlePostGraphQL(ctx *gin.Context) {
+    var input graphqlRequest
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    request_ctx := context.WithValue(ctx.Request.Context(), loadersKey{}, s.plantsService.NewLoaders())
+    res := s.graphql.Exec(request_ctx, input.Query, input.OperationName, input.Variables)
+    ctx.JSON(http.StatusOK, res)
+}
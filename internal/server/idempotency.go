@@ -0,0 +1,113 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header.
+const maxIdempotencyKeyLength = 255
+
+// responseRecorder keeps a copy of the body written to a response.
+type responseRecorder struct {
+    gin.ResponseWriter
+    body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+    w.body.Write(data)
+    return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+    w.body.WriteString(data)
+    return w.ResponseWriter.WriteString(data)
+}
+
+// requestHash tells apart the requests made with the same idempotency key:
+// it hashes their method, URL and body.
+func requestHash(ctx *gin.Context, body []byte) string {
+    hash := sha256.New()
+    hash.Write([]byte(ctx.Request.Method + " " + ctx.Request.URL.RequestURI() + "\n"))
+    hash.Write(body)
+    return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotent handles a request made with an Idempotency-Key header once:
+// retries of the request get the response it got, with an
+// Idempotent-Replayed header. A key used for another request is answered
+// with 422, one used by a request still being handled with 409. Requests
+// failing with a 5xx, or whose handler panics, are forgotten, for their
+// retries to be handled again. Every POST route takes it, the other methods
+// being idempotent already.
+func (s *Server) idempotent(ctx *gin.Context) {
+    key := ctx.GetHeader("Idempotency-Key")
+    if key == "" {
+        return
+    }
+    if len(key) > maxIdempotencyKeyLength {
+        ctx.AbortWithStatus(400)
+        return
+    }
+
+    body, err := io.ReadAll(ctx.Request.Body)
+    if err != nil {
+        ctx.AbortWithStatus(400)
+        return
+    }
+    ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+    record, err := s.plantsService.StartIdempotentRequest(key, requestHash(ctx, body))
+    if errors.Is(err, plants.ErrIdempotencyKeyReuse) {
+        ctx.AbortWithStatus(http.StatusUnprocessableEntity)
+        return
+    } else if errors.Is(err, plants.ErrIdempotencyKeyInProgress) {
+        ctx.AbortWithStatus(http.StatusConflict)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    if record.Status != 0 {
+        ctx.Header("Idempotent-Replayed", "true")
+        ctx.Abort()
+        ctx.Data(record.Status, record.ContentType, record.Body)
+        return
+    }
+
+    // the key is released unless the response is stored, when the handler
+    // panics too: the panic goes on to the recovery middleware
+    completed := false
+    defer func() {
+        if completed {
+            return
+        }
+        if err := s.plantsService.ReleaseIdempotencyKey(record); err != nil {
+            log.Printf("idempotency key %q: %v", key, err)
+        }
+    }()
+
+    recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+    ctx.Writer = recorder
+    ctx.Next()
+
+    status := recorder.Status()
+    if status >= 500 {
+        return
+    }
+    err = s.plantsService.CompleteIdempotentRequest(record, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+    if err != nil {
+        log.Printf("idempotency key %q: %v", key, err)
+        return
+    }
+    completed = true
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetPlantAssetMaintenance(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    res, err := s.plantsService.GetAssetMaintenanceWindows(plant_id, asset_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostPlantAssetMaintenance(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.CreateMaintenanceWindowInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.CreateAssetMaintenanceWindow(plant_id, asset_id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrMaintenanceWindow) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrMaintenanceRRule) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+func (s *Server) handleDeletePlantAssetMaintenance(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    maintenance_id, err := parseId(ctx, "maintenance_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    err = s.plantsService.DeleteAssetMaintenanceWindow(plant_id, asset_id, maintenance_id)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+// handleGetPlantAssetAvailability tells if the asset can be used between
+// `from` and `to`, right now when they are not given.
+func (s *Server) handleGetPlantAssetAvailability(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    from := time.Now()
+    to := from.Add(time.Second)
+    if ctx.Query("from") != "" || ctx.Query("to") != "" {
+        from, to, err = parseTimeRange(ctx)
+        if err != nil {
+            ctx.String(http.StatusBadRequest, "")
+            return
+        }
+    }
+
+    res, err := s.plantsService.GetAssetAvailability(plant_id, asset_id, from, to)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handleGetPlantAssetMaintenanceSchedule(ctx *gin.Context) {
+    plant_id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    asset_id, err := parseId(ctx, "asset_id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetAssetMaintenanceSchedule(plant_id, asset_id, from, to)
+    if errors.Is(err, plants.ErrMaintenanceOccurrences) {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
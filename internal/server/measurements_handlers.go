@@ -0,0 +1,85 @@
+package server
+
+import (
+    "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+func (s *Server) handleGetPlantMeasurements(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetPlantMeasurements(id, from, to)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
+
+func (s *Server) handlePostMeasurements(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+
+    var input plants.CreateMeasurementsInput
+    if err := ctx.ShouldBindJSON(&input); err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    err = s.plantsService.CreateMeasurements(id, input)
+    if errors.Is(err, plants.ErrEmptyResult) {
+        ctx.AbortWithStatus(404)
+        return
+    } else if errors.Is(err, plants.ErrMeasurementAsset) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
+        ctx.AbortWithStatus(500)
+        return
+    }
+    ctx.String(http.StatusOK, "")
+}
+
+// handleGetPlantLoadCurve buckets the plant's load by local hour, day (the
+// default), week or month, following the interval query parameter.
+func (s *Server) handleGetPlantLoadCurve(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetPlantLoadCurve(id, from, to, ctx.DefaultQuery("interval", plants.IntervalDay))
+    if errors.Is(err, plants.ErrInterval) {
+        ctx.AbortWithStatus(400)
+        return
+    }
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
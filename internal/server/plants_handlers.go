@@ -3,19 +3,79 @@ package server
 import (
     "errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/jeandeducla/api-plant/internal/geo"
 	"github.com/jeandeducla/api-plant/internal/plants"
 )
 
+// handleGetPlants lists the plants, optionally filtered by location:
+// ?near=latitude,longitude&radius_km= for the plants around a point,
+// closest first, ?bbox=min_longitude,min_latitude,max_longitude,max_latitude
+// for the plants in a rectangle, ?grid_zone= and ?country_code=.
 func (s *Server) handleGetPlants(ctx *gin.Context) {
-    res, err := s.plantsService.GetAllPlants()
+    query, filtered, err := parsePlantQuery(ctx)
     if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+    if !filtered {
+        res, err := s.plantsService.GetAllPlants()
+        if err != nil {
+            ctx.AbortWithStatus(500)
+            return
+        }
+        renderResource(ctx, res)
+        return
+    }
+
+    res, err := s.plantsService.SearchPlants(query)
+    if errors.Is(err, plants.ErrPlantQuery) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrPlantCountry) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if err != nil {
         ctx.AbortWithStatus(500)
         return
     }
-    ctx.JSON(http.StatusOK, res)
+    renderResource(ctx, res)
+}
+
+func parsePlantQuery(ctx *gin.Context) (plants.PlantQuery, bool, error) {
+    var query plants.PlantQuery
+    filtered := false
+    if param := ctx.Query("near"); param != "" {
+        near, err := geo.ParsePoint(param)
+        if err != nil {
+            return query, true, err
+        }
+        query.Near = &near
+        filtered = true
+    }
+    if param := ctx.Query("radius_km"); param != "" {
+        radius, err := strconv.ParseFloat(param, 64)
+        if err != nil {
+            return query, true, err
+        }
+        query.RadiusKm = radius
+        filtered = true
+    }
+    if param := ctx.Query("bbox"); param != "" {
+        bbox, err := geo.ParseBBox(param)
+        if err != nil {
+            return query, true, err
+        }
+        query.BBox = &bbox
+        filtered = true
+    }
+    query.GridZone = ctx.Query("grid_zone")
+    query.CountryCode = ctx.Query("country_code")
+    filtered = filtered || query.GridZone != "" || query.CountryCode != ""
+    return query, filtered, nil
 }
 
 func (s *Server) handlePostPlant(ctx *gin.Context) {
@@ -29,6 +89,18 @@ func (s *Server) handlePostPlant(ctx *gin.Context) {
     if errors.Is(err, plants.ErrEmptyResult) {
         ctx.AbortWithStatus(400)
         return
+    } else if errors.Is(err, plants.ErrPlantLocation) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrPlantCountry) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrPlantTimezone) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrPlantTariff) {
+        ctx.AbortWithStatus(400)
+        return
     } else if err != nil {
         ctx.AbortWithStatus(500)
         return
@@ -43,13 +115,22 @@ func (s *Server) handleGetPlant(ctx *gin.Context) {
         return
     }
     
-    res, err := s.plantsService.GetPlant(id)
+    include, err := plants.ParseInclude(ctx.Query("include"))
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+    res, err := s.plantsService.GetPlantIncluding(id, include)
+    if isIncludeError(err) {
+        ctx.AbortWithStatus(400)
+        return
+    }
     status, err := matchError(err)
     if err != nil {
         ctx.AbortWithStatus(status)
         return
     }
-    ctx.JSON(http.StatusOK, res)
+    renderResource(ctx, res)
 }
 
 func (s *Server) handleDeletePlant(ctx *gin.Context) {
@@ -88,9 +169,46 @@ func (s *Server) handlePutPlant(ctx *gin.Context) {
     } else if errors.Is(err, plants.ErrNewEmDoesNotExist) {
         ctx.AbortWithStatus(400)
         return
+    } else if errors.Is(err, plants.ErrPlantLocation) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrPlantCountry) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrPlantTimezone) {
+        ctx.AbortWithStatus(400)
+        return
+    } else if errors.Is(err, plants.ErrPlantTariff) {
+        ctx.AbortWithStatus(400)
+        return
     } else if err != nil {
         ctx.AbortWithStatus(500)
         return
     }
     ctx.String(http.StatusOK, "")
 }
+
+func (s *Server) handleGetPlantFlexibility(ctx *gin.Context) {
+    id, err := parseId(ctx, "id")
+    if err != nil {
+        ctx.AbortWithStatus(404)
+        return
+    }
+    if ctx.Query("from") == "" || ctx.Query("to") == "" {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+    from, to, err := parseTimeRange(ctx)
+    if err != nil {
+        ctx.String(http.StatusBadRequest, "")
+        return
+    }
+
+    res, err := s.plantsService.GetPlantFlexibility(id, from, to)
+    status, err := matchError(err)
+    if err != nil {
+        ctx.AbortWithStatus(status)
+        return
+    }
+    renderResource(ctx, res)
+}
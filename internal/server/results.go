@@ -0,0 +1,281 @@
+package server
+
+import (
+	"github.com/jeandeducla/api-plant/internal/dto"
+	"github.com/jeandeducla/api-plant/internal/dto/v1"
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+// newBody converts the result of a handler to its response body in a version
+// of the contract. The resources are converted by the dto packages, the
+// results the service computes here, the dto packages not knowing of the
+// service.
+func newBody(version int, res interface{}) interface{} {
+    if version == 1 {
+        return newBodyV1(res)
+    }
+    switch res := res.(type) {
+    case *plants.ConnectionCompliance:
+        body := newConnectionCompliance(res)
+        return &body
+    case *plants.PlantFlexibility:
+        body := newPlantFlexibility(res)
+        return &body
+    case *plants.PlantTree:
+        body := newPlantTree(res)
+        return &body
+    case []plants.MaintenanceOccurrence:
+        return newMaintenanceOccurrences(res)
+    case *plants.AssetAvailability:
+        body := newAssetAvailability(res)
+        return &body
+    case *plants.CurtailmentEvaluation:
+        body := newCurtailmentEvaluation(res)
+        return &body
+    case *plants.LoadCurve:
+        body := newLoadCurve(res)
+        return &body
+    case *plants.PlantCosts:
+        body := newPlantCosts(res)
+        return &body
+    case *plants.PlantEmissions:
+        body := newPlantEmissions(res)
+        return &body
+    case *plants.EnergyManagerEmissions:
+        body := newEnergyManagerEmissions(res)
+        return &body
+    }
+    return dto.New(res)
+}
+
+func newBodyV1(res interface{}) interface{} {
+    switch res := res.(type) {
+    case *plants.ConnectionCompliance:
+        body := newConnectionComplianceV1(res)
+        return &body
+    case *plants.PlantFlexibility:
+        body := newPlantFlexibilityV1(res)
+        return &body
+    case *plants.PlantTree:
+        body := newPlantTreeV1(res)
+        return &body
+    case []plants.MaintenanceOccurrence:
+        return newMaintenanceOccurrencesV1(res)
+    case *plants.AssetAvailability:
+        body := newAssetAvailabilityV1(res)
+        return &body
+    case *plants.CurtailmentEvaluation:
+        body := newCurtailmentEvaluationV1(res)
+        return &body
+    case *plants.LoadCurve:
+        body := newLoadCurveV1(res)
+        return &body
+    case *plants.PlantCosts:
+        body := newPlantCostsV1(res)
+        return &body
+    case *plants.PlantEmissions:
+        body := newPlantEmissionsV1(res)
+        return &body
+    case *plants.EnergyManagerEmissions:
+        body := newEnergyManagerEmissionsV1(res)
+        return &body
+    }
+    return v1.New(res)
+}
+
+func newConnectionCompliance(compliance *plants.ConnectionCompliance) dto.ConnectionCompliance {
+    issues := make([]dto.ComplianceIssue, len(compliance.Issues))
+    for i, issue := range compliance.Issues {
+        issues[i] = dto.ComplianceIssue{
+            Level: issue.Level,
+            Code: issue.Code,
+            ConnectionID: issue.ConnectionID,
+            At: issue.At,
+            Value: issue.Value,
+            Limit: issue.Limit,
+        }
+    }
+    res := dto.ConnectionCompliance{
+        PlantID: compliance.PlantID,
+        From: compliance.From,
+        To: compliance.To,
+        AssetPower: compliance.AssetPower,
+        Compliant: compliance.Compliant,
+        Issues: issues,
+    }
+    if compliance.Connection != nil {
+        connection := dto.NewGridConnection(compliance.Connection)
+        res.Connection = &connection
+    }
+    return res
+}
+
+func newCurtailmentEvaluation(evaluation *plants.CurtailmentEvaluation) dto.CurtailmentEvaluation {
+    return dto.CurtailmentEvaluation{
+        CurtailmentID: evaluation.CurtailmentID,
+        RequestedPower: evaluation.RequestedPower,
+        BaselinePower: evaluation.BaselinePower,
+        MeasuredPower: evaluation.MeasuredPower,
+        DeliveredPower: evaluation.DeliveredPower,
+        DeliveryRatio: evaluation.DeliveryRatio,
+        Met: evaluation.Met,
+    }
+}
+
+func newEmissionsBreakdown(breakdown plants.EmissionsBreakdown) dto.EmissionsBreakdown {
+    return dto.EmissionsBreakdown{
+        Energy: breakdown.Energy,
+        Emissions: breakdown.Emissions,
+        UnknownEnergy: breakdown.UnknownEnergy,
+    }
+}
+
+func newPlantEmissions(emissions *plants.PlantEmissions) dto.PlantEmissions {
+    assets := make([]dto.AssetEmissions, len(emissions.Assets))
+    for i, asset := range emissions.Assets {
+        assets[i] = dto.AssetEmissions{
+            AssetID: asset.AssetID,
+            EmissionsBreakdown: newEmissionsBreakdown(asset.EmissionsBreakdown),
+        }
+    }
+    return dto.PlantEmissions{
+        PlantID: emissions.PlantID,
+        From: emissions.From,
+        To: emissions.To,
+        EmissionsBreakdown: newEmissionsBreakdown(emissions.EmissionsBreakdown),
+        Assets: assets,
+    }
+}
+
+func newEnergyManagerEmissions(emissions *plants.EnergyManagerEmissions) dto.EnergyManagerEmissions {
+    res := dto.EnergyManagerEmissions{
+        EnergyManagerID: emissions.EnergyManagerID,
+        From: emissions.From,
+        To: emissions.To,
+        EmissionsBreakdown: newEmissionsBreakdown(emissions.EmissionsBreakdown),
+        Plants: make([]dto.PlantEmissions, len(emissions.Plants)),
+    }
+    for i := range emissions.Plants {
+        res.Plants[i] = newPlantEmissions(&emissions.Plants[i])
+    }
+    return res
+}
+
+func newPlantFlexibility(flexibility *plants.PlantFlexibility) dto.PlantFlexibility {
+    assets := make([]dto.AssetFlexibility, len(flexibility.Assets))
+    for i, asset := range flexibility.Assets {
+        assets[i] = dto.AssetFlexibility{
+            AssetID: asset.AssetID,
+            Upward: asset.Upward,
+            Downward: asset.Downward,
+            Available: asset.Available,
+            Reason: asset.Reason,
+        }
+    }
+    return dto.PlantFlexibility{
+        PlantID: flexibility.PlantID,
+        From: flexibility.From,
+        To: flexibility.To,
+        Upward: flexibility.Upward,
+        Downward: flexibility.Downward,
+        Assets: assets,
+    }
+}
+
+func newGroupNodes(nodes []plants.GroupNode) []dto.GroupNode {
+    res := make([]dto.GroupNode, len(nodes))
+    for i := range nodes {
+        res[i] = dto.GroupNode{
+            AssetGroup: dto.NewAssetGroup(&nodes[i].AssetGroup),
+            Assets: dto.NewAssets(nodes[i].Assets),
+            Children: newGroupNodes(nodes[i].Children),
+        }
+    }
+    return res
+}
+
+func newPlantTree(tree *plants.PlantTree) dto.PlantTree {
+    return dto.PlantTree{
+        PlantID: tree.PlantID,
+        Assets: dto.NewAssets(tree.Assets),
+        Groups: newGroupNodes(tree.Groups),
+    }
+}
+
+func newMaintenanceOccurrences(occurrences []plants.MaintenanceOccurrence) []dto.MaintenanceOccurrence {
+    res := make([]dto.MaintenanceOccurrence, len(occurrences))
+    for i, occurrence := range occurrences {
+        res[i] = dto.MaintenanceOccurrence{
+            MaintenanceID: occurrence.MaintenanceID,
+            StartsAt: occurrence.StartsAt,
+            EndsAt: occurrence.EndsAt,
+        }
+    }
+    return res
+}
+
+func newAssetAvailability(availability *plants.AssetAvailability) dto.AssetAvailability {
+    return dto.AssetAvailability{
+        AssetID: availability.AssetID,
+        From: availability.From,
+        To: availability.To,
+        Available: availability.Available,
+        Reason: availability.Reason,
+    }
+}
+
+func newLoadCurve(curve *plants.LoadCurve) dto.LoadCurve {
+    buckets := make([]dto.LoadBucket, len(curve.Buckets))
+    for i, bucket := range curve.Buckets {
+        buckets[i] = dto.LoadBucket{
+            StartsAt: bucket.StartsAt,
+            EndsAt: bucket.EndsAt,
+            Readings: bucket.Readings,
+            AveragePower: bucket.AveragePower,
+            Energy: bucket.Energy,
+        }
+    }
+    return dto.LoadCurve{
+        PlantID: curve.PlantID,
+        Timezone: curve.Timezone,
+        Interval: curve.Interval,
+        Buckets: buckets,
+    }
+}
+
+func newCostBreakdown(breakdown plants.CostBreakdown) dto.CostBreakdown {
+    return dto.CostBreakdown{
+        Energy: breakdown.Energy,
+        EnergyCost: breakdown.EnergyCost,
+        DemandCost: breakdown.DemandCost,
+        TotalCost: breakdown.TotalCost,
+    }
+}
+
+func newPlantCosts(costs *plants.PlantCosts) dto.PlantCosts {
+    periods := make([]dto.PeriodCost, len(costs.Periods))
+    for i, period := range costs.Periods {
+        periods[i] = dto.PeriodCost{
+            PeriodID: period.PeriodID,
+            Name: period.Name,
+            CostBreakdown: newCostBreakdown(period.CostBreakdown),
+        }
+    }
+    assets := make([]dto.AssetCost, len(costs.Assets))
+    for i, asset := range costs.Assets {
+        assets[i] = dto.AssetCost{
+            AssetID: asset.AssetID,
+            CostBreakdown: newCostBreakdown(asset.CostBreakdown),
+        }
+    }
+    return dto.PlantCosts{
+        PlantID: costs.PlantID,
+        TariffID: costs.TariffID,
+        Currency: costs.Currency,
+        From: costs.From,
+        To: costs.To,
+        CostBreakdown: newCostBreakdown(costs.CostBreakdown),
+        Periods: periods,
+        Assets: assets,
+    }
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"github.com/jeandeducla/api-plant/internal/dto/v1"
+	"github.com/jeandeducla/api-plant/internal/plants"
+)
+
+// The results the service computes, in the frozen contract of version 1.
+
+func newConnectionComplianceV1(compliance *plants.ConnectionCompliance) v1.ConnectionCompliance {
+    issues := make([]v1.ComplianceIssue, len(compliance.Issues))
+    for i, issue := range compliance.Issues {
+        issues[i] = v1.ComplianceIssue{
+            Level: issue.Level,
+            Code: issue.Code,
+            ConnectionID: issue.ConnectionID,
+            At: issue.At,
+            Value: issue.Value,
+            Limit: issue.Limit,
+        }
+    }
+    res := v1.ConnectionCompliance{
+        PlantID: compliance.PlantID,
+        From: compliance.From,
+        To: compliance.To,
+        AssetPower: compliance.AssetPower,
+        Compliant: compliance.Compliant,
+        Issues: issues,
+    }
+    if compliance.Connection != nil {
+        connection := v1.NewGridConnection(compliance.Connection)
+        res.Connection = &connection
+    }
+    return res
+}
+
+func newCurtailmentEvaluationV1(evaluation *plants.CurtailmentEvaluation) v1.CurtailmentEvaluation {
+    return v1.CurtailmentEvaluation{
+        CurtailmentID: evaluation.CurtailmentID,
+        RequestedPower: v1.Kilowatts(evaluation.RequestedPower),
+        BaselinePower: evaluation.BaselinePower,
+        MeasuredPower: evaluation.MeasuredPower,
+        DeliveredPower: evaluation.DeliveredPower,
+        DeliveryRatio: evaluation.DeliveryRatio,
+        Met: evaluation.Met,
+    }
+}
+
+func newEmissionsBreakdownV1(breakdown plants.EmissionsBreakdown) v1.EmissionsBreakdown {
+    return v1.EmissionsBreakdown{
+        Energy: breakdown.Energy,
+        Emissions: breakdown.Emissions,
+        UnknownEnergy: breakdown.UnknownEnergy,
+    }
+}
+
+func newPlantEmissionsV1(emissions *plants.PlantEmissions) v1.PlantEmissions {
+    assets := make([]v1.AssetEmissions, len(emissions.Assets))
+    for i, asset := range emissions.Assets {
+        assets[i] = v1.AssetEmissions{
+            AssetID: asset.AssetID,
+            EmissionsBreakdown: newEmissionsBreakdownV1(asset.EmissionsBreakdown),
+        }
+    }
+    return v1.PlantEmissions{
+        PlantID: emissions.PlantID,
+        From: emissions.From,
+        To: emissions.To,
+        EmissionsBreakdown: newEmissionsBreakdownV1(emissions.EmissionsBreakdown),
+        Assets: assets,
+    }
+}
+
+func newEnergyManagerEmissionsV1(emissions *plants.EnergyManagerEmissions) v1.EnergyManagerEmissions {
+    res := v1.EnergyManagerEmissions{
+        EnergyManagerID: emissions.EnergyManagerID,
+        From: emissions.From,
+        To: emissions.To,
+        EmissionsBreakdown: newEmissionsBreakdownV1(emissions.EmissionsBreakdown),
+        Plants: make([]v1.PlantEmissions, len(emissions.Plants)),
+    }
+    for i := range emissions.Plants {
+        res.Plants[i] = newPlantEmissionsV1(&emissions.Plants[i])
+    }
+    return res
+}
+
+func newPlantFlexibilityV1(flexibility *plants.PlantFlexibility) v1.PlantFlexibility {
+    assets := make([]v1.AssetFlexibility, len(flexibility.Assets))
+    for i, asset := range flexibility.Assets {
+        assets[i] = v1.AssetFlexibility{
+            AssetID: asset.AssetID,
+            Upward: asset.Upward,
+            Downward: asset.Downward,
+            Available: asset.Available,
+            Reason: asset.Reason,
+        }
+    }
+    return v1.PlantFlexibility{
+        PlantID: flexibility.PlantID,
+        From: flexibility.From,
+        To: flexibility.To,
+        Upward: flexibility.Upward,
+        Downward: flexibility.Downward,
+        Assets: assets,
+    }
+}
+
+func newGroupNodesV1(nodes []plants.GroupNode) []v1.GroupNode {
+    if nodes == nil {
+        return nil
+    }
+    res := make([]v1.GroupNode, len(nodes))
+    for i := range nodes {
+        res[i] = v1.GroupNode{
+            AssetGroup: v1.NewAssetGroup(&nodes[i].AssetGroup),
+            Assets: v1.NewAssets(nodes[i].Assets),
+            Children: newGroupNodesV1(nodes[i].Children),
+        }
+    }
+    return res
+}
+
+func newPlantTreeV1(tree *plants.PlantTree) v1.PlantTree {
+    return v1.PlantTree{
+        PlantID: tree.PlantID,
+        Assets: v1.NewAssets(tree.Assets),
+        Groups: newGroupNodesV1(tree.Groups),
+    }
+}
+
+func newMaintenanceOccurrencesV1(occurrences []plants.MaintenanceOccurrence) []v1.MaintenanceOccurrence {
+    res := make([]v1.MaintenanceOccurrence, len(occurrences))
+    for i, occurrence := range occurrences {
+        res[i] = v1.MaintenanceOccurrence{
+            MaintenanceID: occurrence.MaintenanceID,
+            StartsAt: occurrence.StartsAt,
+            EndsAt: occurrence.EndsAt,
+        }
+    }
+    return res
+}
+
+func newAssetAvailabilityV1(availability *plants.AssetAvailability) v1.AssetAvailability {
+    return v1.AssetAvailability{
+        AssetID: availability.AssetID,
+        From: availability.From,
+        To: availability.To,
+        Available: availability.Available,
+        Reason: availability.Reason,
+    }
+}
+
+func newLoadCurveV1(curve *plants.LoadCurve) v1.LoadCurve {
+    buckets := make([]v1.LoadBucket, len(curve.Buckets))
+    for i, bucket := range curve.Buckets {
+        buckets[i] = v1.LoadBucket{
+            StartsAt: bucket.StartsAt,
+            EndsAt: bucket.EndsAt,
+            Readings: bucket.Readings,
+            AveragePower: bucket.AveragePower,
+            Energy: bucket.Energy,
+        }
+    }
+    return v1.LoadCurve{
+        PlantID: curve.PlantID,
+        Timezone: curve.Timezone,
+        Interval: curve.Interval,
+        Buckets: buckets,
+    }
+}
+
+func newCostBreakdownV1(breakdown plants.CostBreakdown) v1.CostBreakdown {
+    return v1.CostBreakdown{
+        Energy: breakdown.Energy,
+        EnergyCost: breakdown.EnergyCost,
+        DemandCost: breakdown.DemandCost,
+        TotalCost: breakdown.TotalCost,
+    }
+}
+
+func newPlantCostsV1(costs *plants.PlantCosts) v1.PlantCosts {
+    periods := make([]v1.PeriodCost, len(costs.Periods))
+    for i, period := range costs.Periods {
+        periods[i] = v1.PeriodCost{
+            PeriodID: period.PeriodID,
+            Name: period.Name,
+            CostBreakdown: newCostBreakdownV1(period.CostBreakdown),
+        }
+    }
+    assets := make([]v1.AssetCost, len(costs.Assets))
+    for i, asset := range costs.Assets {
+        assets[i] = v1.AssetCost{
+            AssetID: asset.AssetID,
+            CostBreakdown: newCostBreakdownV1(asset.CostBreakdown),
+        }
+    }
+    return v1.PlantCosts{
+        PlantID: costs.PlantID,
+        TariffID: costs.TariffID,
+        Currency: costs.Currency,
+        From: costs.From,
+        To: costs.To,
+        CostBreakdown: newCostBreakdownV1(costs.CostBreakdown),
+        Periods: periods,
+        Assets: assets,
+    }
+}
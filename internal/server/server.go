@@ -2,47 +2,162 @@ package server
 
 import (
 	"errors"
+	"net/http"
+	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	graphql "github.com/graph-gophers/graphql-go"
 
+	"github.com/jeandeducla/api-plant/internal/models"
 	"github.com/jeandeducla/api-plant/internal/plants"
 )
 
 type Server struct {
     plantsService *plants.Service
+    graphql *graphql.Schema
 }
 
 func NewServer(plantsService *plants.Service) (*Server, error) {
+    // lets binding:"required" reject a zero power
+    if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+        v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+            return field.Interface().(models.Power).Watts()
+        }, models.Power{})
+    }
     return &Server{
         plantsService: plantsService,
+        graphql: newGraphQLSchema(plantsService),
     }, nil
 }
 
+// Router serves the API under /v1, the contract of the models frozen and
+// deprecated, and under /v2, the contract of the dto package. The routes
+// without a version are those of /v1, choosing their contract with the Accept
+// header, and are deprecated too.
 func (s *Server) Router() *gin.Engine {
     router := gin.Default()
 
+    s.routes(router.Group("", deprecateV1))
+    s.routes(router.Group("/v1", pinAPIVersion(1), deprecateV1))
+    s.routes(router.Group("/v2", pinAPIVersion(2)))
+
+    return router
+}
+
+// routes registers the routes of a version of the API.
+func (s *Server) routes(router *gin.RouterGroup) {
     router.GET("/ems", s.handleGetEnergyManagers)
-    router.POST("/ems", s.handlePostEnergyManager)
+    router.POST("/ems", s.idempotent, s.handlePostEnergyManager)
     router.GET("/ems/:id", s.handleGetEnergyManager)
     router.DELETE("/ems/:id", s.handleDeleteEnergyManager)
     router.PUT("/ems/:id", s.handlePutEnergyManager)
 
     router.GET("/ems/:id/plants", s.handleGetEnergyManagerPlants)
+    router.GET("/ems/:id/notifications", s.handleGetEnergyManagerNotifications)
+    router.PUT("/ems/:id/notifications", s.handlePutEnergyManagerNotifications)
+    router.GET("/ems/:id/emissions", s.handleGetEnergyManagerEmissions)
+
+    router.GET("/events", s.handleGetEvents)
+
+    router.POST("/graphql", s.idempotent, s.handlePostGraphQL)
+
+    router.GET("/webhooks", s.handleGetWebhooks)
+    router.POST("/webhooks", s.idempotent, s.handlePostWebhook)
+    router.GET("/webhooks/:id", s.handleGetWebhook)
+    router.DELETE("/webhooks/:id", s.handleDeleteWebhook)
+    router.PUT("/webhooks/:id", s.handlePutWebhook)
+    router.GET("/webhooks/:id/deliveries", s.handleGetWebhookDeliveries)
+    router.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", s.idempotent, s.handlePostWebhookRedelivery)
+
+    router.GET("/tariffs", s.handleGetTariffs)
+    router.POST("/tariffs", s.idempotent, s.handlePostTariff)
+    router.GET("/tariffs/:id", s.handleGetTariff)
+    router.DELETE("/tariffs/:id", s.handleDeleteTariff)
+    router.PUT("/tariffs/:id", s.handlePutTariff)
+
+    router.GET("/emission-factors", s.handleGetEmissionFactors)
+    router.PUT("/emission-factors/:zone", s.handlePutEmissionFactor)
+    router.DELETE("/emission-factors/:zone", s.handleDeleteEmissionFactor)
+    router.GET("/emission-factors/:zone/hourly", s.handleGetHourlyEmissionFactors)
+    router.POST("/emission-factors/:zone/hourly", s.idempotent, s.handlePostHourlyEmissionFactors)
 
     router.GET("/plants", s.handleGetPlants)
-    router.POST("/plants", s.handlePostPlant)
+    router.POST("/plants", s.idempotent, s.handlePostPlant)
     router.GET("/plants/:id", s.handleGetPlant)
     router.DELETE("/plants/:id", s.handleDeletePlant)
     router.PUT("/plants/:id", s.handlePutPlant)
 
+    router.GET("/plants/:id/assignments", s.handleGetPlantAssignments)
+    router.POST("/plants/:id/assignments", s.idempotent, s.handlePostAssignment)
+    router.GET("/plants/:id/assignments/:assignment_id", s.handleGetPlantAssignment)
+    router.DELETE("/plants/:id/assignments/:assignment_id", s.handleDeletePlantAssignment)
+    router.PUT("/plants/:id/assignments/:assignment_id", s.handlePutPlantAssignment)
+
+    router.GET("/plants/:id/connections", s.handleGetPlantConnections)
+    router.POST("/plants/:id/connections", s.idempotent, s.handlePostConnection)
+    router.GET("/plants/:id/connections/:connection_id", s.handleGetPlantConnection)
+    router.DELETE("/plants/:id/connections/:connection_id", s.handleDeletePlantConnection)
+    router.PUT("/plants/:id/connections/:connection_id", s.handlePutPlantConnection)
+    router.GET("/plants/:id/compliance", s.handleGetPlantCompliance)
+
+    router.GET("/plants/:id/flexibility", s.handleGetPlantFlexibility)
+
+    router.GET("/plants/:id/tree", s.handleGetPlantTree)
+    router.GET("/plants/:id/groups", s.handleGetPlantGroups)
+    router.POST("/plants/:id/groups", s.idempotent, s.handlePostGroup)
+    router.GET("/plants/:id/groups/:group_id", s.handleGetPlantGroup)
+    router.DELETE("/plants/:id/groups/:group_id", s.handleDeletePlantGroup)
+    router.PUT("/plants/:id/groups/:group_id", s.handlePutPlantGroup)
+    router.GET("/plants/:id/groups/:group_id/assets", s.handleGetPlantGroupAssets)
+
     router.GET("/plants/:id/assets", s.handleGetPlantAssets)
-    router.POST("/plants/:id/assets", s.handlePostAsset)
+    router.POST("/plants/:id/assets", s.idempotent, s.handlePostAsset)
     router.GET("/plants/:id/assets/:asset_id", s.handleGetPlantAsset)
     router.DELETE("/plants/:id/assets/:asset_id", s.handleDeletePlantAsset)
     router.PUT("/plants/:id/assets/:asset_id", s.handlePutPlantAsset)
+    router.POST("/plants/:id/assets/:asset_id/move", s.idempotent, s.handlePostPlantAssetMove)
+    router.GET("/plants/:id/assets/:asset_id/moves", s.handleGetPlantAssetMoves)
 
-    return router
+    router.GET("/plants/:id/assets/:asset_id/commands", s.handleGetPlantAssetCommands)
+    router.POST("/plants/:id/assets/:asset_id/commands", s.idempotent, s.handlePostPlantAssetCommand)
+    router.GET("/plants/:id/assets/:asset_id/commands/:command_id", s.handleGetPlantAssetCommand)
+
+    router.GET("/plants/:id/assets/:asset_id/availability", s.handleGetPlantAssetAvailability)
+    router.GET("/plants/:id/assets/:asset_id/maintenance", s.handleGetPlantAssetMaintenance)
+    router.POST("/plants/:id/assets/:asset_id/maintenance", s.idempotent, s.handlePostPlantAssetMaintenance)
+    router.GET("/plants/:id/assets/:asset_id/maintenance/schedule", s.handleGetPlantAssetMaintenanceSchedule)
+    router.DELETE("/plants/:id/assets/:asset_id/maintenance/:maintenance_id", s.handleDeletePlantAssetMaintenance)
+
+    router.GET("/plants/:id/curtailments", s.handleGetPlantCurtailments)
+    router.POST("/plants/:id/curtailments", s.idempotent, s.handlePostCurtailment)
+    router.GET("/plants/:id/curtailments/:curtailment_id", s.handleGetPlantCurtailment)
+    router.DELETE("/plants/:id/curtailments/:curtailment_id", s.handleDeletePlantCurtailment)
+    router.PUT("/plants/:id/curtailments/:curtailment_id/status", s.handlePutPlantCurtailmentStatus)
+    router.GET("/plants/:id/curtailments/:curtailment_id/evaluation", s.handleGetPlantCurtailmentEvaluation)
+
+    router.GET("/plants/:id/measurements", s.handleGetPlantMeasurements)
+    router.POST("/plants/:id/measurements", s.idempotent, s.handlePostMeasurements)
+    router.GET("/plants/:id/load", s.handleGetPlantLoadCurve)
+    router.GET("/plants/:id/costs", s.handleGetPlantCosts)
+    router.GET("/plants/:id/emissions", s.handleGetPlantEmissions)
+
+    router.GET("/plants/:id/dispatch-plans", s.handleGetPlantDispatchPlans)
+    router.POST("/plants/:id/dispatch-plans", s.idempotent, s.handlePostDispatchPlan)
+    router.GET("/plants/:id/dispatch-plans/:plan_id", s.handleGetPlantDispatchPlan)
+    router.POST("/plants/:id/dispatch-plans/:plan_id/commands", s.idempotent, s.handlePostPlantDispatchPlanCommands)
+
+    router.GET("/agents", s.handleGetAgents)
+    router.POST("/agents", s.idempotent, s.handlePostAgent)
+    router.GET("/agents/:id", s.handleGetAgent)
+    router.DELETE("/agents/:id", s.handleDeleteAgent)
+
+    router.GET("/agents/:id/commands", s.handleGetAgentCommands)
+    router.POST("/agents/:id/commands/:command_id/ack", s.idempotent, s.handlePostAgentCommandAck)
+    router.POST("/agents/:id/commands/:command_id/reject", s.idempotent, s.handlePostAgentCommandReject)
 }
 
 func parseId(ctx *gin.Context, idName string) (uint, error) {
@@ -51,6 +166,62 @@ func parseId(ctx *gin.Context, idName string) (uint, error) {
     return uint(id), err
 }
 
+// parseTimeRange reads the RFC 3339 `from` and `to` query parameters. They
+// default to the last 24 hours.
+func parseTimeRange(ctx *gin.Context) (time.Time, time.Time, error) {
+    to := time.Now()
+    if param := ctx.Query("to"); param != "" {
+        t, err := time.Parse(time.RFC3339, param)
+        if err != nil {
+            return time.Time{}, time.Time{}, err
+        }
+        to = t
+    }
+    from := to.Add(-24 * time.Hour)
+    if param := ctx.Query("from"); param != "" {
+        t, err := time.Parse(time.RFC3339, param)
+        if err != nil {
+            return time.Time{}, time.Time{}, err
+        }
+        from = t
+    }
+    if !from.Before(to) {
+        return time.Time{}, time.Time{}, errors.New("from must be before to")
+    }
+    return from, to, nil
+}
+
+// renderJSON responds res with its powers in the unit query parameter, kW
+// by default.
+func renderJSON(ctx *gin.Context, res interface{}) {
+    if !renderPowerIn(ctx, res) {
+        return
+    }
+    ctx.JSON(http.StatusOK, res)
+}
+
+// renderPowerIn sets the unit the powers of res are rendered in from the unit
+// query parameter. It responds a 400 and returns false when it is unknown.
+func renderPowerIn(ctx *gin.Context, res interface{}) bool {
+    if param := ctx.Query("unit"); param != "" {
+        unit, err := models.ParsePowerUnit(param)
+        if err != nil {
+            ctx.String(http.StatusBadRequest, "")
+            return false
+        }
+        models.RenderPowerIn(res, unit)
+    }
+    return true
+}
+
+// isIncludeError tells whether the relationships a read includes are unknown
+// or too many.
+func isIncludeError(err error) bool {
+    return errors.Is(err, plants.ErrInclude) ||
+        errors.Is(err, plants.ErrIncludeDepth) ||
+        errors.Is(err, plants.ErrIncludeSize)
+}
+
 func matchError(err error) (int, error) {
     if errors.Is(err, plants.ErrEmptyResult) {
         return 404, err
@@ -1,12 +1,18 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 
@@ -40,9 +46,52 @@ func (t *MainTestSuite) SetupTest() {
 }
 
 func (t *MainTestSuite) TearDownTest() {
+    t.db.Migrator().DropTable(&models.IdempotencyKey{})
+    t.db.Migrator().DropTable(&models.WebhookDelivery{})
+    t.db.Migrator().DropTable(&models.WebhookSubscription{})
+    t.db.Migrator().DropTable(&models.OutboxMessage{})
+    t.db.Migrator().DropTable(&models.Event{})
+    t.db.Migrator().DropTable(&models.AssetMove{})
+    t.db.Migrator().DropTable(&models.GridConnection{})
+    t.db.Migrator().DropTable(&models.NotificationPreference{})
+    t.db.Migrator().DropTable(&models.PlantAssignment{})
+    t.db.Migrator().DropTable(&models.AssetGroup{})
+    t.db.Migrator().DropTable(&models.MaintenanceWindow{})
+    t.db.Migrator().DropTable(&models.CommandTransition{})
+    t.db.Migrator().DropTable(&models.Command{})
+    t.db.Migrator().DropTable(&models.Agent{})
+    t.db.Migrator().DropTable(&models.DispatchSetpoint{})
+    t.db.Migrator().DropTable(&models.DispatchPlan{})
+    t.db.Migrator().DropTable(&models.Measurement{})
+    t.db.Migrator().DropTable(&models.CurtailmentEvent{})
     t.db.Migrator().DropTable(&models.Asset{})
     t.db.Migrator().DropTable(&models.Plant{})
     t.db.Migrator().DropTable(&models.EnergyManager{})
+    t.db.Migrator().DropTable(&models.TariffPeriod{})
+    t.db.Migrator().DropTable(&models.Tariff{})
+    t.db.Migrator().DropTable(&models.EmissionFactor{})
+}
+
+// serve sends a request to the router, with body as its JSON body when it is
+// not empty.
+func (t *MainTestSuite) serve(method string, url string, body string) *httptest.ResponseRecorder {
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest(method, url, strings.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    return w
+}
+
+// createPlant creates an energy manager and a plant of 1000 kW managed by
+// it, then the assets of the given max powers in kW.
+func (t *MainTestSuite) createPlant(assets ...uint) {
+    w := t.serve("POST", "/ems", `{"name": "Gerard", "surname": "Depardieu"}`)
+    t.Require().Equal(200, w.Code)
+    w = t.serve("POST", "/plants", `{"name": "plant", "address": "17 rue truc", "max_power": 1000, "energy_manager_id": 1}`)
+    t.Require().Equal(200, w.Code)
+    for _, max_power := range assets {
+        w = t.serve("POST", "/plants/1/assets", fmt.Sprintf(`{"name": "furnace", "type": "furnace", "max_power": %d}`, max_power))
+        t.Require().Equal(200, w.Code)
+    }
 }
 
 func (t *MainTestSuite) TestGetAllEnergyManagers() {
@@ -216,6 +265,66 @@ func (t *MainTestSuite) TestDeleteEnergyManager() {
     req, _ = http.NewRequest("GET", "/ems/1", nil)
     t.server.Router().ServeHTTP(w, req)
     t.Equal(404, w.Code)
+
+    // em with a plant can only be deleted once the plant is reassigned
+    for i := 0; i < 2; i++ {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("POST", "/ems", bytes.NewReader(body))
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(200, w.Code)
+    }
+    body = []byte(`
+        {
+            "name": "Gerard",
+            "address": "187 rue triuy",
+            "max_power": 189,
+            "energy_manager_id": 2
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("DELETE", "/ems/2", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(409, w.Code)
+    var res struct {
+        Plants []models.Plant `json:"plants"`
+    }
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+    t.Require().Len(res.Plants, 1)
+    t.Equal(uint(1), res.Plants[0].ID)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("DELETE", "/v2/ems/2", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(409, w.Code)
+    var res_v2 struct {
+        Plants []map[string]interface{} `json:"plants"`
+    }
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res_v2))
+    t.Require().Len(res_v2.Plants, 1)
+    t.Equal(float64(1), res_v2.Plants[0]["id"])
+    t.Equal(float64(2), res_v2.Plants[0]["energy_manager_id"])
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("DELETE", "/ems/2?reassign_to=x", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("DELETE", "/ems/2?reassign_to=42", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("DELETE", "/ems/2?reassign_to=3", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/ems/3/plants", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    var plants []models.Plant
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&plants))
+    t.Require().Len(plants, 1)
 }
 
 func (t *MainTestSuite) TestPutEnergyManager() {
@@ -925,3 +1034,1222 @@ func (t *MainTestSuite) TestPutPlantAsset() {
     t.server.Router().ServeHTTP(w, req)
     t.Equal(400, w.Code)
 }
+
+func (t *MainTestSuite) TestPostCurtailment() {
+    // id is not parsable
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/plants/joel/curtailments", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(404, w.Code)
+
+    // plant does not exist
+    body := []byte(`
+        {
+            "target_power": 10,
+            "starts_at": "2022-05-02T14:00:00Z",
+            "ends_at": "2022-05-02T16:00:00Z"
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants/1/curtailments", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(404, w.Code)
+
+    body = []byte(`
+        {
+            "name": "Eric",
+            "surname": "judor"
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/ems", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    body = []byte(`
+        {
+            "name": "Plant",
+            "address": "187 rue triuy",
+            "max_power": 189,
+            "energy_manager_id": 1
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    body = []byte(`
+        {
+            "name": "asset",
+            "max_power": 10,
+            "type": "furnace"
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants/1/assets", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+
+    // target is above the assets capacity
+    body = []byte(`
+        {
+            "target_power": 11,
+            "starts_at": "2022-05-02T14:00:00Z",
+            "ends_at": "2022-05-02T16:00:00Z"
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants/1/curtailments", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+
+    // works
+    body = []byte(`
+        {
+            "target_power": 10,
+            "starts_at": "2022-05-02T14:00:00Z",
+            "ends_at": "2022-05-02T16:00:00Z"
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants/1/curtailments", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1/curtailments/1", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    var res models.CurtailmentEvent
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+    t.Equal(res.Status, "planned")
+
+    // planned events cannot be completed directly
+    body = []byte(`{"status": "completed"}`)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("PUT", "/plants/1/curtailments/1/status", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(409, w.Code)
+
+    // nothing was measured yet
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1/curtailments/1/evaluation", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(422, w.Code)
+}
+
+func (t *MainTestSuite) TestPowerUnits() {
+    body := []byte(`{"name": "Gerard", "surname": "Depardieu"}`)
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/ems", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+
+    // powers can be given with a unit
+    body = []byte(`
+        {
+            "name": "Gerard",
+            "address": "187 rue triuy",
+            "max_power": "2.5 MW",
+            "energy_manager_id": 1
+        }
+    `)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    for _, max_power := range []string{`{"value": 1.25, "unit": "MW"}`, `"1249.999 kW"`, `0.001`} {
+        body = []byte(`{"name": "chiller", "type": "chiller", "max_power": ` + max_power + `}`)
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("POST", "/plants/1/assets", bytes.NewReader(body))
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(200, w.Code)
+    }
+    // the plant is exactly full
+    body = []byte(`{"name": "chiller", "type": "chiller", "max_power": "1 W"}`)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants/1/assets", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+
+    // below a watt, unknown unit or missing
+    for _, max_power := range []string{`"0.5 W"`, `"2 TW"`, `0`} {
+        body = []byte(`{"name": "chiller", "type": "chiller", "max_power": ` + max_power + `}`)
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("POST", "/plants/1/assets", bytes.NewReader(body))
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(400, w.Code)
+    }
+
+    // powers are rendered in kW unless asked otherwise
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Equal(2500.0, res["MaxPower"])
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/v2/plants/1/assets?unit=MW", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res []map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Require().Len(res, 3)
+        t.Equal(1.25, res[0]["max_power"])
+        t.Equal(1.249999, res[1]["max_power"])
+        t.Equal(0.000001, res[2]["max_power"])
+    }
+    // version 1 keeps the whole kW it always had
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/v1/plants/1/assets", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res []map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Require().Len(res, 3)
+        t.Equal(1250.0, res[0]["MaxPower"])
+        t.Equal(1250.0, res[1]["MaxPower"])
+        t.Equal(0.0, res[2]["MaxPower"])
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1/assets?unit=TW", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+}
+
+func (t *MainTestSuite) TestWebhooks() {
+    // invalid url, unknown event or missing secret
+    for _, body := range []string{
+        `{"url": "localhost/hook", "secret": "s3cr3t"}`,
+        `{"url": "https://example.com/hook", "secret": "s3cr3t", "events": ["alarm.closed"]}`,
+        `{"url": "https://example.com/hook"}`,
+    } {
+        w := httptest.NewRecorder()
+        req, _ := http.NewRequest("POST", "/webhooks", bytes.NewReader([]byte(body)))
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(400, w.Code)
+    }
+
+    body := []byte(`{"url": "https://example.com/hook", "secret": "s3cr3t", "events": ["plant.updated", "asset.deleted"]}`)
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+
+    // the secret is never rendered
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/webhooks/1", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    t.NotContains(w.Body.String(), "s3cr3t")
+    var res models.WebhookSubscription
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+    t.Equal("plant.updated,asset.deleted", res.Events)
+
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/webhooks/1/deliveries?status=dead", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/webhooks/1/deliveries?status=lost", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/webhooks/1/deliveries/1/redeliver", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(404, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/webhooks/2/deliveries", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(404, w.Code)
+}
+
+func (t *MainTestSuite) TestEvents() {
+    for _, path := range []string{"/events?type=alarm", "/events?plant_id=abc"} {
+        w := httptest.NewRecorder()
+        req, _ := http.NewRequest("GET", path, nil)
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(400, w.Code)
+    }
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("GET", "/events?plant_id=1", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(404, w.Code)
+
+    body := []byte(`{"name": "Gerard", "surname": "Depardieu"}`)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/ems", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+
+    // resuming from the start of the log
+    server := httptest.NewServer(t.server.Router())
+    defer server.Close()
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    req, _ = http.NewRequestWithContext(ctx, "GET", server.URL+"/events?type=em", nil)
+    req.Header.Set("Last-Event-ID", "0")
+    res, err := http.DefaultClient.Do(req)
+    t.Require().NoError(err)
+    defer res.Body.Close()
+    t.Equal(200, res.StatusCode)
+    t.Equal("text/event-stream", res.Header.Get("Content-Type"))
+
+    lines := []string{}
+    scanner := bufio.NewScanner(res.Body)
+    for scanner.Scan() && len(lines) < 3 {
+        if line := scanner.Text(); line != "" {
+            lines = append(lines, line)
+        }
+    }
+    t.Require().Len(lines, 3)
+    t.Equal("id:1", lines[0])
+    t.Equal("event:em.created", lines[1])
+    t.True(strings.HasPrefix(lines[2], "data:"))
+    t.Contains(lines[2], `"type":"em.created"`)
+
+    // the data of the events is in the contract of the route
+    data := func(path string) map[string]interface{} {
+        req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+path, nil)
+        req.Header.Set("Last-Event-ID", "0")
+        res, err := http.DefaultClient.Do(req)
+        t.Require().NoError(err)
+        defer res.Body.Close()
+        scanner := bufio.NewScanner(res.Body)
+        for scanner.Scan() {
+            if line := scanner.Text(); strings.HasPrefix(line, "data:") {
+                var message struct {
+                    Type string                 `json:"type"`
+                    Data map[string]interface{} `json:"data"`
+                }
+                t.Require().NoError(json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &message))
+                t.Equal("em.created", message.Type)
+                return message.Data
+            }
+        }
+        return nil
+    }
+    data_v1 := data("/v1/events?type=em")
+    t.Equal("Gerard", data_v1["Name"])
+    t.NotContains(data_v1, "name")
+    data_v2 := data("/v2/events?type=em")
+    t.Equal("Gerard", data_v2["name"])
+    t.NotContains(data_v2, "Name")
+}
+
+func (t *MainTestSuite) TestGraphQL() {
+    post := func(query string) map[string]interface{} {
+        body, _ := json.Marshal(map[string]interface{}{"query": query})
+        w := httptest.NewRecorder()
+        req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+        t.server.Router().ServeHTTP(w, req)
+        t.Require().Equal(200, w.Code)
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        return res
+    }
+
+    // the mutations go through the business rules
+    res := post(`mutation { createEnergyManager(input: {name: "Gerard", surname: "Depardieu"}) }`)
+    t.Nil(res["errors"])
+    res = post(`mutation { createPlant(input: {name: "plant1", address: "17 rue truc", maxPower: 1000, energyManagerId: "1"}) }`)
+    t.Nil(res["errors"])
+    res = post(`mutation { createAsset(plantId: "1", input: {name: "furnace", type: "furnace", maxPower: 300}) }`)
+    t.Nil(res["errors"])
+    res = post(`mutation { createAsset(plantId: "1", input: {name: "oven", type: "oven", maxPower: 300}) }`)
+    t.NotNil(res["errors"])
+    res = post(`mutation { createAsset(plantId: "1", input: {name: "chiller", type: "chiller", maxPower: 800}) }`)
+    t.NotNil(res["errors"])
+
+    // an energy manager with its plants and their assets in one request
+    res = post(`{ energyManagers { name plants { name maxPower assets(type: "furnace") { name maxPower } } } }`)
+    t.Nil(res["errors"])
+    expected := `{"energyManagers": [{"name": "Gerard", "plants": [{"name": "plant1", "maxPower": 1000, "assets": [{"name": "furnace", "maxPower": 300}]}]}]}`
+    data, _ := json.Marshal(res["data"])
+    t.JSONEq(expected, string(data))
+
+    res = post(`mutation { updateAsset(plantId: "1", id: "1", input: {name: "furnace", type: "furnace", maxPower: 400}) { maxPower } }`)
+    t.Nil(res["errors"])
+    res = post(`{ plant(id: "1") { energyManagers(role: "primary") { name } assets(first: 1, after: "1") { id } } }`)
+    t.Nil(res["errors"])
+    data, _ = json.Marshal(res["data"])
+    t.JSONEq(`{"plant": {"energyManagers": [{"name": "Gerard"}], "assets": []}}`, string(data))
+    res = post(`{ plant(id: "2") { name } }`)
+    t.Nil(res["errors"])
+    data, _ = json.Marshal(res["data"])
+    t.JSONEq(`{"plant": null}`, string(data))
+    res = post(`{ plants(first: 500) { name } }`)
+    t.NotNil(res["errors"])
+
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader([]byte(`{}`)))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+}
+
+func (t *MainTestSuite) TestIncludes() {
+    body := []byte(`{"name": "Gerard", "surname": "Depardieu"}`)
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/ems", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    body = []byte(`{"name": "plant1", "address": "17 rue truc", "max_power": 1000, "energy_manager_id": 1}`)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    body = []byte(`{"name": "furnace", "type": "furnace", "max_power": 300}`)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants/1/assets", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+
+    // relationships are only embedded when included
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/ems/1", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.NotContains(res, "Plants")
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/ems/1?include=plants.assets", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res models.EnergyManager
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Require().Len(res.Plants, 1)
+        t.Require().Len(res.Plants[0].Assets, 1)
+        t.Equal("furnace", res.Plants[0].Assets[0].Name)
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1?include=assets,energy_manager", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res models.Plant
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Len(res.Assets, 1)
+        t.Require().NotNil(res.EnergyManager)
+        t.Equal("Gerard", res.EnergyManager.Name)
+    }
+
+    // unknown or too deep relationships
+    for _, url := range []string{"/plants/1?include=plants", "/plants/1?include=energy_manager.plants.assets", "/ems/1?include=plants,"} {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("GET", url, nil)
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(400, w.Code)
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/2?include=assets", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(404, w.Code)
+}
+
+func (t *MainTestSuite) TestContracts() {
+    body := []byte(`{"name": "Gerard", "surname": "Depardieu"}`)
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/ems", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    body = []byte(`{"name": "plant1", "address": "17 rue truc", "max_power": 1000, "energy_manager_id": 1}`)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+
+    // the models are rendered as they are by default
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Equal(1000.0, res["MaxPower"])
+        t.Contains(res, "DeletedAt")
+        t.Equal(1.0, res["EnergyManagerID"])
+    }
+
+    // version 2 has snake_case keys and sparse fieldsets
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1?include=energy_manager&unit=MW", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v2+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    t.Equal("application/vnd.api-plant.v2+json", w.Header().Get("Content-Type"))
+    {
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Equal(1.0, res["max_power"])
+        t.NotContains(res, "DeletedAt")
+        t.Equal(1.0, res["energy_manager_id"])
+        t.Equal("Gerard", res["energy_manager"].(map[string]interface{})["name"])
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants?fields=name,max_power", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v2+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    t.JSONEq(`[{"id": 1, "name": "plant1", "max_power": 1000}]`, w.Body.String())
+
+    // so do the other resources
+    w = t.serve("POST", "/plants/1/connections", `{"connection_point_id": "30001234567890", "voltage_level": "hv", "import_capacity": 400}`)
+    t.Equal(200, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1/connections/1?unit=MW", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v2+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Equal("30001234567890", res["connection_point_id"])
+        t.Equal(0.4, res["import_capacity"])
+        t.Contains(res, "valid_to")
+        t.NotContains(res, "ImportCapacity")
+        t.NotContains(res, "DeletedAt")
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1/connections?fields=voltage_level", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v2+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    t.JSONEq(`[{"id": 1, "voltage_level": "hv"}]`, w.Body.String())
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/plants/1/compliance?fields=compliant,connection", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v2+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    {
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        t.Len(res, 2)
+        t.Equal("hv", res["connection"].(map[string]interface{})["voltage_level"])
+    }
+
+    // unknown fields, fields of version 1 and unknown versions
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/ems/1?fields=max_power", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v2+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/ems/1?fields=name", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/ems/1", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v3+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(406, w.Code)
+}
+
+func (t *MainTestSuite) TestVersions() {
+    body := []byte(`{"name": "Gerard", "surname": "Depardieu"}`)
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/v2/ems", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    t.Empty(w.Header().Get("Deprecation"))
+    t.Empty(w.Header().Get("Sunset"))
+
+    // version 1 is frozen and deprecated, with or without its prefix
+    for _, url := range []string{"/ems/1", "/v1/ems/1"} {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("GET", url, nil)
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(200, w.Code)
+        t.Equal("@1792368000", w.Header().Get("Deprecation"))
+        t.Equal("Tue, 19 Oct 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+        t.Equal(`</v2/ems/1>; rel="successor-version"`, w.Header().Get("Link"))
+        var res map[string]interface{}
+        t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+        keys := []string{}
+        for key := range res {
+            keys = append(keys, key)
+        }
+        t.ElementsMatch([]string{
+            "ID", "CreatedAt", "UpdatedAt", "DeletedAt",
+            "Name", "Surname", "Email", "Phone", "Timezone", "Language",
+        }, keys)
+        t.Equal("Gerard", res["Name"])
+    }
+    for _, url := range []string{"/ems", "/v1/ems", "/v1/tariffs"} {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("GET", url, nil)
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal("@1792368000", w.Header().Get("Deprecation"), url)
+        t.Equal("Tue, 19 Oct 2027 00:00:00 GMT", w.Header().Get("Sunset"), url)
+    }
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/v1/ems/1", nil)
+    req.Header.Set("Accept", "application/vnd.api-plant.v2+json")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    t.Contains(w.Body.String(), `"Name"`)
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/v1/ems/1?fields=name", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(400, w.Code)
+
+    // version 2 has the contract of the dto package, whatever the Accept header
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/v2/ems/1?fields=name", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+    t.Empty(w.Header().Get("Deprecation"))
+    t.Empty(w.Header().Get("Sunset"))
+    t.Empty(w.Header().Get("Link"))
+    t.JSONEq(`{"id": 1, "name": "Gerard"}`, w.Body.String())
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("GET", "/v2/tariffs", nil)
+    t.server.Router().ServeHTTP(w, req)
+    t.Empty(w.Header().Get("Deprecation"))
+    t.Empty(w.Header().Get("Sunset"))
+}
+
+func (t *MainTestSuite) TestIdempotency() {
+    body := []byte(`{"name": "Gerard", "surname": "Depardieu"}`)
+    w := httptest.NewRecorder()
+    req, _ := http.NewRequest("POST", "/ems", bytes.NewReader(body))
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(200, w.Code)
+
+    body = []byte(`{"name": "plant", "address": "17 rue truc", "max_power": 1000, "energy_manager_id": 1}`)
+    for i := 0; i < 2; i++ {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("POST", "/plants", bytes.NewReader(body))
+        req.Header.Set("Idempotency-Key", "plant-1")
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(200, w.Code)
+        if i == 0 {
+            t.Empty(w.Header().Get("Idempotent-Replayed"))
+        } else {
+            t.Equal("true", w.Header().Get("Idempotent-Replayed"))
+        }
+    }
+    res, err := t.service.GetAllPlants()
+    t.Require().NoError(err)
+    t.Len(res, 1)
+
+    // a key cannot be reused for another request
+    w = httptest.NewRecorder()
+    req, _ = http.NewRequest("POST", "/plants", bytes.NewReader([]byte(`{"name": "other", "address": "17 rue truc", "max_power": 1000, "energy_manager_id": 1}`)))
+    req.Header.Set("Idempotency-Key", "plant-1")
+    t.server.Router().ServeHTTP(w, req)
+    t.Equal(422, w.Code)
+
+    // failures are replayed too
+    asset := []byte(`{"name": "furnace", "type": "furnace", "max_power": 2000}`)
+    for i := 0; i < 2; i++ {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("POST", "/plants/1/assets", bytes.NewReader(asset))
+        req.Header.Set("Idempotency-Key", "asset-1")
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(400, w.Code)
+    }
+    t.Equal("true", w.Header().Get("Idempotent-Replayed"))
+
+    // requests without a key are not deduplicated
+    for i := 0; i < 2; i++ {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("POST", "/plants", bytes.NewReader(body))
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(200, w.Code)
+    }
+    res, err = t.service.GetAllPlants()
+    t.Require().NoError(err)
+    t.Len(res, 3)
+
+    // every POST route takes a key
+    group := []byte(`{"name": "hall", "kind": "building"}`)
+    for i := 0; i < 2; i++ {
+        w = httptest.NewRecorder()
+        req, _ = http.NewRequest("POST", "/plants/1/groups", bytes.NewReader(group))
+        req.Header.Set("Idempotency-Key", "group-1")
+        t.server.Router().ServeHTTP(w, req)
+        t.Equal(200, w.Code)
+    }
+    t.Equal("true", w.Header().Get("Idempotent-Replayed"))
+    groups, err := t.service.GetPlantGroups(1)
+    t.Require().NoError(err)
+    t.Len(groups, 1)
+}
+
+func (t *MainTestSuite) TestIdempotencyPanic() {
+    router := gin.New()
+    router.Use(gin.Recovery())
+    calls := 0
+    router.POST("/panic", t.server.idempotent, func(ctx *gin.Context) {
+        calls++
+        panic("boom")
+    })
+
+    // the key of a request whose handler panics is released
+    for i := 0; i < 2; i++ {
+        w := httptest.NewRecorder()
+        req, _ := http.NewRequest("POST", "/panic", strings.NewReader("{}"))
+        req.Header.Set("Idempotency-Key", "panic-1")
+        router.ServeHTTP(w, req)
+        t.Equal(500, w.Code)
+    }
+    t.Equal(2, calls)
+}
+
+func (t *MainTestSuite) TestAssetMaintenance() {
+    // asset does not exist
+    w := t.serve("GET", "/plants/1/assets/1/maintenance", "")
+    t.Equal(404, w.Code)
+    t.createPlant(100)
+
+    // availability is either available or unavailable
+    w = t.serve("POST", "/plants/1/assets", `{"name": "mill", "type": "rolling mill", "max_power": 10, "availability": "sometimes"}`)
+    t.Equal(400, w.Code)
+
+    // invalid windows
+    w = t.serve("POST", "/plants/1/assets/1/maintenance", `{"starts_at": "2022-05-02T10:00:00Z"}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants/1/assets/1/maintenance", `{"starts_at": "2022-05-02T10:00:00Z", "ends_at": "2022-05-02T09:00:00Z"}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants/1/assets/1/maintenance", `{"starts_at": "2022-05-02T10:00:00Z", "ends_at": "2022-05-02T12:00:00Z", "rrule": "FREQ=SOMETIMES"}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants/1/assets/2/maintenance", `{"starts_at": "2022-05-02T10:00:00Z", "ends_at": "2022-05-02T12:00:00Z"}`)
+    t.Equal(404, w.Code)
+
+    // serviced every day from 10:00 to 12:00, three times
+    w = t.serve("POST", "/plants/1/assets/1/maintenance", `{"starts_at": "2022-05-02T10:00:00Z", "ends_at": "2022-05-02T12:00:00Z", "rrule": "FREQ=DAILY;COUNT=3", "reason": "cleaning"}`)
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/plants/1/assets/1/maintenance", "")
+    t.Equal(200, w.Code)
+    var windows []models.MaintenanceWindow
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&windows))
+    t.Require().Len(windows, 1)
+    t.Equal("cleaning", windows[0].Reason)
+
+    w = t.serve("GET", "/plants/1/assets/1/availability?from=2022-05-03T11:00:00Z&to=2022-05-03T13:00:00Z", "")
+    t.Equal(200, w.Code)
+    var availability plants.AssetAvailability
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&availability))
+    t.False(availability.Available)
+    t.Equal(plants.UnavailableMaintenance, availability.Reason)
+    w = t.serve("GET", "/plants/1/assets/1/availability?from=2022-05-05T11:00:00Z&to=2022-05-05T13:00:00Z", "")
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&availability))
+    t.True(availability.Available)
+    w = t.serve("GET", "/plants/1/assets/1/availability?from=yesterday", "")
+    t.Equal(400, w.Code)
+
+    w = t.serve("GET", "/plants/1/assets/1/maintenance/schedule?from=2022-05-01T00:00:00Z&to=2022-05-10T00:00:00Z", "")
+    t.Equal(200, w.Code)
+    var schedule []plants.MaintenanceOccurrence
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&schedule))
+    t.Require().Len(schedule, 3)
+    t.True(schedule[2].StartsAt.Equal(time.Date(2022, 5, 4, 10, 0, 0, 0, time.UTC)))
+
+    w = t.serve("DELETE", "/plants/1/assets/1/maintenance/1", "")
+    t.Equal(200, w.Code)
+    w = t.serve("DELETE", "/plants/1/assets/1/maintenance/1", "")
+    t.Equal(404, w.Code)
+}
+
+func (t *MainTestSuite) TestAssetGroups() {
+    // plant does not exist
+    w := t.serve("POST", "/plants/1/groups", `{"name": "B1", "kind": "building"}`)
+    t.Equal(404, w.Code)
+    t.createPlant(100, 50)
+
+    // invalid groups
+    w = t.serve("POST", "/plants/1/groups", `{"kind": "building"}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants/1/groups", `{"name": "B1", "kind": "castle"}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants/1/groups", `{"name": "L1", "kind": "line", "parent_id": 42}`)
+    t.Equal(400, w.Code)
+
+    w = t.serve("POST", "/plants/1/groups", `{"name": "B1", "kind": "building", "max_power": 120}`)
+    t.Equal(200, w.Code)
+    w = t.serve("POST", "/plants/1/groups", `{"name": "L1", "kind": "line", "parent_id": 1}`)
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/plants/1/groups/3", "")
+    t.Equal(404, w.Code)
+
+    // the building cannot draw more than 120 kW
+    w = t.serve("PUT", "/plants/1/assets/1", `{"name": "furnace", "type": "furnace", "max_power": 100, "group_id": 2}`)
+    t.Equal(200, w.Code)
+    w = t.serve("PUT", "/plants/1/assets/2", `{"name": "furnace", "type": "furnace", "max_power": 50, "group_id": 2}`)
+    t.Equal(400, w.Code)
+    w = t.serve("PUT", "/plants/1/groups/1", `{"name": "B1", "kind": "building", "max_power": 90}`)
+    t.Equal(400, w.Code)
+
+    // a group cannot be moved under its own subtree
+    w = t.serve("PUT", "/plants/1/groups/1", `{"name": "B1", "kind": "building", "parent_id": 2}`)
+    t.Equal(400, w.Code)
+
+    w = t.serve("GET", "/plants/1/groups/1/assets", "")
+    t.Equal(200, w.Code)
+    t.JSONEq(`[]`, w.Body.String())
+    w = t.serve("GET", "/plants/1/groups/1/assets?recursive=true", "")
+    t.Equal(200, w.Code)
+    var assets []models.Asset
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&assets))
+    t.Require().Len(assets, 1)
+    t.Equal(uint(1), assets[0].ID)
+    w = t.serve("GET", "/plants/1/groups/1/assets?recursive=maybe", "")
+    t.Equal(400, w.Code)
+
+    w = t.serve("GET", "/plants/1/tree", "")
+    t.Equal(200, w.Code)
+    var tree plants.PlantTree
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&tree))
+    t.Require().Len(tree.Assets, 1)
+    t.Equal(uint(2), tree.Assets[0].ID)
+    t.Require().Len(tree.Groups, 1)
+    t.Equal("B1", tree.Groups[0].Name)
+    t.Require().Len(tree.Groups[0].Children, 1)
+    t.Len(tree.Groups[0].Children[0].Assets, 1)
+
+    // deleting a group deletes its subtree and detaches its assets
+    w = t.serve("DELETE", "/plants/1/groups/1", "")
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/plants/1/groups/2", "")
+    t.Equal(404, w.Code)
+    w = t.serve("GET", "/plants/1/tree", "")
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&tree))
+    t.Len(tree.Groups, 0)
+    t.Len(tree.Assets, 2)
+}
+
+func (t *MainTestSuite) TestEnergyManagerContactDetails() {
+    // contact details are validated
+    invalid := []string{
+        `{"name": "Gerard", "surname": "Depardieu", "email": "gerard"}`,
+        `{"name": "Gerard", "surname": "Depardieu", "phone": "06 12 34 56 78"}`,
+        `{"name": "Gerard", "surname": "Depardieu", "timezone": "Europe/Nowhere"}`,
+        `{"name": "Gerard", "surname": "Depardieu", "language": "not a language"}`,
+    }
+    for _, body := range invalid {
+        w := t.serve("POST", "/ems", body)
+        t.Equal(400, w.Code, body)
+    }
+
+    w := t.serve("POST", "/ems", `{"name": "Gerard", "surname": "Depardieu", "email": "gerard@depardieu.fr", "phone": "+33612345678", "timezone": "Europe/Paris", "language": "fr-fr"}`)
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/ems/1", "")
+    t.Equal(200, w.Code)
+    var em models.EnergyManager
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&em))
+    t.Equal("gerard@depardieu.fr", em.Email)
+    t.Equal("+33612345678", em.Phone)
+    t.Equal("Europe/Paris", em.Timezone)
+    t.Equal("fr-FR", em.Language)
+    w = t.serve("PUT", "/ems/1", `{"name": "Gerard", "surname": "Depardieu", "phone": "0612345678"}`)
+    t.Equal(400, w.Code)
+
+    // notification preferences
+    w = t.serve("GET", "/ems/42/notifications", "")
+    t.Equal(404, w.Code)
+    w = t.serve("PUT", "/ems/42/notifications", `{"preferences": []}`)
+    t.Equal(404, w.Code)
+    w = t.serve("PUT", "/ems/1/notifications", `{"preferences": [{"enabled": true}]}`)
+    t.Equal(400, w.Code)
+    w = t.serve("PUT", "/ems/1/notifications", `{"preferences": [{"channel": "sms", "enabled": true}]}`)
+    t.Equal(400, w.Code)
+    w = t.serve("PUT", "/ems/1/notifications", `{"preferences": [{"channel": "webhook", "enabled": true, "target": "ftp://example.com"}]}`)
+    t.Equal(400, w.Code)
+    w = t.serve("PUT", "/ems/1/notifications", `{"preferences": [{"channel": "email", "enabled": true}, {"channel": "email", "enabled": false}]}`)
+    t.Equal(400, w.Code)
+
+    w = t.serve("PUT", "/ems/1/notifications", `{"preferences": [{"channel": "email", "enabled": true}, {"channel": "webhook", "enabled": true, "target": "https://example.com/hook"}]}`)
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/ems/1/notifications", "")
+    t.Equal(200, w.Code)
+    var preferences []models.NotificationPreference
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&preferences))
+    t.Require().Len(preferences, 2)
+    t.Equal("email", preferences[0].Channel)
+    t.Equal("https://example.com/hook", preferences[1].Target)
+
+    // preferences are replaced as a whole
+    w = t.serve("PUT", "/ems/1/notifications", `{"preferences": [{"channel": "webhook", "enabled": false, "target": "https://example.com/hook"}]}`)
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/ems/1/notifications", "")
+    preferences = nil
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&preferences))
+    t.Require().Len(preferences, 1)
+    t.False(preferences[0].Enabled)
+}
+
+func (t *MainTestSuite) TestSearchPlants() {
+    w := t.serve("POST", "/ems", `{"name": "Gerard", "surname": "Depardieu"}`)
+    t.Require().Equal(200, w.Code)
+
+    // invalid locations
+    w = t.serve("POST", "/plants", `{"name": "p", "address": "a", "max_power": 100, "energy_manager_id": 1, "latitude": 48.9}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants", `{"name": "p", "address": "a", "max_power": 100, "energy_manager_id": 1, "latitude": 98.9, "longitude": 2.5}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants", `{"name": "p", "address": "a", "max_power": 100, "energy_manager_id": 1, "country_code": "FRA"}`)
+    t.Equal(400, w.Code)
+
+    for _, body := range []string{
+        `{"name": "saint-denis", "address": "a", "max_power": 100, "energy_manager_id": 1, "latitude": 48.9, "longitude": 2.5, "grid_zone": "FR", "country_code": "fr"}`,
+        `{"name": "paris", "address": "a", "max_power": 100, "energy_manager_id": 1, "latitude": 48.8566, "longitude": 2.3522, "grid_zone": "FR"}`,
+        `{"name": "lyon", "address": "a", "max_power": 100, "energy_manager_id": 1, "latitude": 45.764, "longitude": 4.8357}`,
+        `{"name": "nowhere", "address": "a", "max_power": 100, "energy_manager_id": 1}`,
+    } {
+        w = t.serve("POST", "/plants", body)
+        t.Require().Equal(200, w.Code)
+    }
+
+    // closest first
+    w = t.serve("GET", "/plants?near=48.85,2.35&radius_km=50", "")
+    t.Equal(200, w.Code)
+    var res []models.Plant
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+    t.Require().Len(res, 2)
+    t.Equal("paris", res[0].Name)
+    t.Equal("saint-denis", res[1].Name)
+    t.Equal("FR", res[1].CountryCode)
+
+    w = t.serve("GET", "/plants?bbox=2,48,3,49&grid_zone=FR", "")
+    t.Equal(200, w.Code)
+    res = nil
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+    t.Len(res, 2)
+    w = t.serve("GET", "/plants?country_code=fr", "")
+    t.Equal(200, w.Code)
+    res = nil
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+    t.Len(res, 1)
+    w = t.serve("GET", "/plants", "")
+    res = nil
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&res))
+    t.Len(res, 4)
+
+    // invalid queries
+    for _, query := range []string{
+        "near=48.85,2.35",
+        "near=paris&radius_km=50",
+        "near=48.85,2.35&radius_km=fifty",
+        "radius_km=50",
+        "near=48.85,2.35&radius_km=50&bbox=2,48,3,49",
+        "bbox=2,48,3",
+        "country_code=FRA",
+    } {
+        w = t.serve("GET", "/plants?" + query, "")
+        t.Equal(400, w.Code, query)
+    }
+}
+
+func (t *MainTestSuite) TestPlantLoadCurve() {
+    w := t.serve("GET", "/plants/1/load?from=2022-03-26T12:00:00Z&to=2022-03-27T12:00:00Z", "")
+    t.Equal(404, w.Code)
+
+    w = t.serve("POST", "/ems", `{"name": "Gerard", "surname": "Depardieu"}`)
+    t.Require().Equal(200, w.Code)
+    w = t.serve("POST", "/plants", `{"name": "plant", "address": "a", "max_power": 1000, "energy_manager_id": 1, "timezone": "Europe/Nowhere"}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants", `{"name": "plant", "address": "a", "max_power": 1000, "energy_manager_id": 1, "timezone": "Europe/Paris"}`)
+    t.Require().Equal(200, w.Code)
+
+    // a constant 100 kW, read every hour around the change of 27 March 2022
+    readings := []string{}
+    start := time.Date(2022, 3, 25, 23, 0, 0, 0, time.UTC)
+    for h := 0; h < 72; h++ {
+        readings = append(readings, fmt.Sprintf(`{"timestamp": "%s", "power": 100}`, start.Add(time.Duration(h) * time.Hour).Format(time.RFC3339)))
+    }
+    w = t.serve("POST", "/plants/1/measurements", `{"measurements": [` + strings.Join(readings, ",") + `]}`)
+    t.Require().Equal(200, w.Code)
+
+    // the interval is validated
+    for _, query := range []string{
+        "interval=fortnight",
+        "interval=HOUR",
+        "interval=day&from=yesterday",
+        "interval=day&to=2022-03-27",
+    } {
+        w = t.serve("GET", "/plants/1/load?" + query, "")
+        t.Equal(400, w.Code, query)
+    }
+
+    // days are local, the one clocks go forward on lasts 23 hours
+    w = t.serve("GET", "/plants/1/load?from=2022-03-26T12:00:00Z&to=2022-03-27T12:00:00Z", "")
+    t.Equal(200, w.Code)
+    var curve plants.LoadCurve
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&curve))
+    t.Equal("Europe/Paris", curve.Timezone)
+    t.Equal(plants.IntervalDay, curve.Interval)
+    t.Require().Len(curve.Buckets, 2)
+    t.True(curve.Buckets[1].StartsAt.Equal(time.Date(2022, 3, 26, 23, 0, 0, 0, time.UTC)))
+    t.True(curve.Buckets[1].EndsAt.Equal(time.Date(2022, 3, 27, 22, 0, 0, 0, time.UTC)))
+    t.Equal(23, curve.Buckets[1].Readings)
+    t.Require().NotNil(curve.Buckets[1].Energy)
+    t.InDelta(2300, *curve.Buckets[1].Energy, 1e-9)
+
+    // and 02:00 does not happen
+    w = t.serve("GET", "/plants/1/load?interval=hour&from=2022-03-27T00:30:00Z&to=2022-03-27T02:00:00Z", "")
+    t.Equal(200, w.Code)
+    curve = plants.LoadCurve{}
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&curve))
+    t.Require().Len(curve.Buckets, 2)
+    paris, err := time.LoadLocation("Europe/Paris")
+    t.Require().NoError(err)
+    t.Equal(1, curve.Buckets[0].StartsAt.In(paris).Hour())
+    t.Equal(3, curve.Buckets[1].StartsAt.In(paris).Hour())
+}
+
+func (t *MainTestSuite) TestTariffs() {
+    periods := `[
+        {"name": "peak", "days": ["mon", "tue", "wed", "thu", "fri"], "start_time": "08:00", "end_time": "20:00", "energy_price": 0.2, "demand_price": 10},
+        {"name": "off-peak", "days": ["mon", "tue", "wed", "thu", "fri"], "start_time": "20:00", "end_time": "08:00", "energy_price": 0.1},
+        {"name": "week-end", "days": ["sat", "sun"], "start_time": "00:00", "end_time": "24:00", "energy_price": 0.1}
+    ]`
+
+    // invalid tariffs
+    for _, body := range []string{
+        `{"name": "green"}`,
+        `{"name": "green", "periods": []}`,
+        `{"name": "green", "periods": [{"name": "peak", "start_time": "08:00", "end_time": "20:00"}]}`,
+        `{"name": "green", "currency": "euro", "periods": ` + periods + `}`,
+        `{"name": "green", "periods": [{"name": "all", "days": ["someday"], "start_time": "00:00", "end_time": "24:00"}]}`,
+        `{"name": "green", "periods": [{"name": "all", "start_time": "00:07", "end_time": "24:00"}]}`,
+        `{"name": "green", "periods": [{"name": "all", "start_time": "00:00", "end_time": "24:00", "energy_price": -1}]}`,
+    } {
+        w := t.serve("POST", "/tariffs", body)
+        t.Equal(400, w.Code, body)
+    }
+
+    w := t.serve("POST", "/tariffs", `{"name": "green", "periods": ` + periods + `}`)
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/tariffs/1", "")
+    t.Equal(200, w.Code)
+    var tariff models.Tariff
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&tariff))
+    t.Equal("EUR", tariff.Currency)
+    t.Len(tariff.Periods, 3)
+    w = t.serve("GET", "/tariffs/2", "")
+    t.Equal(404, w.Code)
+    w = t.serve("PUT", "/tariffs/2", `{"name": "green", "periods": ` + periods + `}`)
+    t.Equal(404, w.Code)
+    w = t.serve("PUT", "/tariffs/1", `{"name": "green", "periods": [{"name": "peak", "start_time": "08:00", "end_time": "20:00"}]}`)
+    t.Equal(400, w.Code)
+
+    // a plant cannot use a tariff that does not exist
+    w = t.serve("POST", "/ems", `{"name": "Gerard", "surname": "Depardieu"}`)
+    t.Require().Equal(200, w.Code)
+    w = t.serve("POST", "/plants", `{"name": "plant", "address": "a", "max_power": 1000, "energy_manager_id": 1, "timezone": "Europe/Paris", "tariff_id": 2}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants", `{"name": "plant", "address": "a", "max_power": 1000, "energy_manager_id": 1, "timezone": "Europe/Paris"}`)
+    t.Require().Equal(200, w.Code)
+
+    // Monday 2 May 2022, from 07:00 to 10:00 local time
+    costs_url := "/plants/1/costs?from=2022-05-02T05:00:00Z&to=2022-05-02T08:00:00Z"
+    w = t.serve("GET", costs_url, "")
+    t.Equal(409, w.Code)
+    w = t.serve("GET", "/plants/2/costs", "")
+    t.Equal(404, w.Code)
+    w = t.serve("GET", "/plants/1/costs?from=monday", "")
+    t.Equal(400, w.Code)
+
+    w = t.serve("PUT", "/plants/1", `{"name": "plant", "address": "a", "max_power": 1000, "energy_manager_id": 1, "timezone": "Europe/Paris", "tariff_id": 1}`)
+    t.Require().Equal(200, w.Code)
+    w = t.serve("POST", "/plants/1/measurements", `{"measurements": [
+        {"timestamp": "2022-05-02T05:00:00Z", "power": 100},
+        {"timestamp": "2022-05-02T06:00:00Z", "power": 200},
+        {"timestamp": "2022-05-02T07:00:00Z", "power": 300}
+    ]}`)
+    t.Require().Equal(200, w.Code)
+    w = t.serve("GET", costs_url, "")
+    t.Equal(200, w.Code)
+    var costs plants.PlantCosts
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&costs))
+    t.Equal("EUR", costs.Currency)
+    t.InDelta(600, costs.Energy, 1e-9)
+    // 100 kWh off-peak then 500 kWh at peak
+    t.InDelta(110, costs.EnergyCost, 1e-9)
+    t.Require().Len(costs.Periods, 3)
+    t.Equal("peak", costs.Periods[0].Name)
+
+    // deleting the tariff detaches it from the plant
+    w = t.serve("DELETE", "/tariffs/1", "")
+    t.Equal(200, w.Code)
+    w = t.serve("DELETE", "/tariffs/1", "")
+    t.Equal(404, w.Code)
+    w = t.serve("GET", costs_url, "")
+    t.Equal(409, w.Code)
+}
+
+func (t *MainTestSuite) TestEmissions() {
+    // invalid factors
+    w := t.serve("PUT", "/emission-factors/FR", `{}`)
+    t.Equal(400, w.Code)
+    w = t.serve("PUT", "/emission-factors/FR", `{"factor": -1}`)
+    t.Equal(400, w.Code)
+    w = t.serve("PUT", "/emission-factors/%20", `{"factor": 100}`)
+    t.Equal(400, w.Code)
+
+    w = t.serve("PUT", "/emission-factors/FR", `{"factor": 100}`)
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/emission-factors", "")
+    t.Equal(200, w.Code)
+    var factors []models.EmissionFactor
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&factors))
+    t.Require().Len(factors, 1)
+    t.Equal(100.0, factors[0].Factor)
+
+    // hourly factors are imported from CSV, on whole hours
+    w = t.serve("POST", "/emission-factors/FR/hourly", "timestamp,factor\n2022-05-02T14:30:00Z,50\n")
+    t.Equal(400, w.Code)
+    t.Contains(w.Body.String(), "whole hours")
+    w = t.serve("POST", "/emission-factors/FR/hourly", "timestamp,factor\n2022-05-02T14:00:00Z,-50\n")
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/emission-factors/FR/hourly", "timestamp,factor\n2022-05-02T14:00:00Z,50\n2022-05-02T15:00:00Z,40\n")
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/emission-factors/FR/hourly?from=2022-05-02T00:00:00Z&to=2022-05-03T00:00:00Z", "")
+    t.Equal(200, w.Code)
+    var hourly []models.EmissionFactor
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&hourly))
+    t.Require().Len(hourly, 2)
+    t.Equal(40.0, hourly[1].Factor)
+    w = t.serve("GET", "/emission-factors/FR/hourly?from=today", "")
+    t.Equal(400, w.Code)
+
+    // 100 kW from 13:00 to 16:00
+    t.createPlant()
+    w = t.serve("PUT", "/plants/1", `{"name": "plant", "address": "17 rue truc", "max_power": 1000, "energy_manager_id": 1, "grid_zone": "FR"}`)
+    t.Require().Equal(200, w.Code)
+    w = t.serve("POST", "/plants/1/measurements", `{"measurements": [
+        {"timestamp": "2022-05-02T13:00:00Z", "power": 100},
+        {"timestamp": "2022-05-02T14:00:00Z", "power": 100},
+        {"timestamp": "2022-05-02T15:00:00Z", "power": 100}
+    ]}`)
+    t.Require().Equal(200, w.Code)
+
+    emissions_url := "/plants/1/emissions?from=2022-05-02T13:00:00Z&to=2022-05-02T16:00:00Z"
+    w = t.serve("GET", emissions_url, "")
+    t.Equal(200, w.Code)
+    var emissions plants.PlantEmissions
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&emissions))
+    t.InDelta(300, emissions.Energy, 1e-9)
+    // the static factor at 13:00, then the hourly ones
+    t.InDelta((100*100+100*50+100*40)/1000.0, emissions.Emissions, 1e-9)
+    w = t.serve("GET", "/plants/2/emissions", "")
+    t.Equal(404, w.Code)
+    w = t.serve("GET", "/plants/1/emissions?to=today", "")
+    t.Equal(400, w.Code)
+
+    w = t.serve("GET", "/ems/1/emissions?from=2022-05-02T13:00:00Z&to=2022-05-02T16:00:00Z", "")
+    t.Equal(200, w.Code)
+    var rollup plants.EnergyManagerEmissions
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&rollup))
+    t.Require().Len(rollup.Plants, 1)
+    t.InDelta(emissions.Emissions, rollup.Emissions, 1e-9)
+    w = t.serve("GET", "/ems/1/emissions?role=boss", "")
+    t.Equal(400, w.Code)
+    w = t.serve("GET", "/ems/2/emissions", "")
+    t.Equal(404, w.Code)
+
+    // without a factor, the energy is unknown
+    w = t.serve("DELETE", "/emission-factors/FR", "")
+    t.Equal(200, w.Code)
+    w = t.serve("DELETE", "/emission-factors/FR", "")
+    t.Equal(404, w.Code)
+    w = t.serve("GET", emissions_url, "")
+    emissions = plants.PlantEmissions{}
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&emissions))
+    t.InDelta(100, emissions.UnknownEnergy, 1e-9)
+}
+
+func (t *MainTestSuite) TestGridConnections() {
+    w := t.serve("GET", "/plants/1/connections", "")
+    t.Equal(404, w.Code)
+    w = t.serve("POST", "/plants/1/connections", `{"connection_point_id": "30001234567890", "voltage_level": "hv", "import_capacity": 400}`)
+    t.Equal(404, w.Code)
+
+    t.createPlant(600)
+
+    // a plant without any connection only gets a warning
+    w = t.serve("GET", "/plants/1/compliance", "")
+    t.Equal(200, w.Code)
+    var compliance plants.ConnectionCompliance
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&compliance))
+    t.True(compliance.Compliant)
+    t.Require().Len(compliance.Issues, 1)
+    t.Equal(plants.IssueNoConnection, compliance.Issues[0].Code)
+
+    // invalid connections
+    w = t.serve("POST", "/plants/1/connections", `{"voltage_level": "hv", "import_capacity": 400}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants/1/connections", `{"connection_point_id": "30001234567890", "voltage_level": "xv", "import_capacity": 400}`)
+    t.Equal(400, w.Code)
+    w = t.serve("POST", "/plants/1/connections", `{"connection_point_id": "30001234567890", "voltage_level": "hv", "import_capacity": 400,
+        "valid_from": "2022-05-01T00:00:00Z", "valid_to": "2022-01-01T00:00:00Z"}`)
+    t.Equal(400, w.Code)
+
+    w = t.serve("POST", "/plants/1/connections", `{"connection_point_id": "30001234567890", "voltage_level": "hv", "import_capacity": 400,
+        "valid_from": "2022-01-01T00:00:00Z"}`)
+    t.Equal(200, w.Code)
+    w = t.serve("POST", "/plants/1/connections", `{"connection_point_id": "30001234567890", "voltage_level": "mv", "import_capacity": 800,
+        "valid_from": "2022-05-01T00:00:00Z"}`)
+    t.Equal(409, w.Code)
+
+    // the contract was upgraded on 1 May 2022
+    w = t.serve("PUT", "/plants/1/connections/1", `{"connection_point_id": "30001234567890", "voltage_level": "hv", "import_capacity": 400,
+        "valid_from": "2022-01-01T00:00:00Z", "valid_to": "2022-05-01T00:00:00Z"}`)
+    t.Equal(200, w.Code)
+    w = t.serve("PUT", "/plants/1/connections/1", `{"connection_point_id": "30001234567890", "voltage_level": "hv", "import_capacity": 400}`)
+    t.Equal(400, w.Code)
+    w = t.serve("PUT", "/plants/1/connections/2", `{"connection_point_id": "30001234567890", "voltage_level": "hv", "import_capacity": 400,
+        "valid_from": "2022-01-01T00:00:00Z"}`)
+    t.Equal(404, w.Code)
+    w = t.serve("POST", "/plants/1/connections", `{"connection_point_id": "30001234567890", "voltage_level": "mv", "import_capacity": 800,
+        "valid_from": "2022-05-01T00:00:00Z"}`)
+    t.Equal(200, w.Code)
+
+    w = t.serve("GET", "/plants/1/connections", "")
+    t.Equal(200, w.Code)
+    var connections []models.GridConnection
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&connections))
+    t.Require().Len(connections, 2)
+    t.Equal("hv", connections[0].VoltageLevel)
+    t.Equal(models.Kilowatts(800), connections[1].ImportCapacity)
+    w = t.serve("GET", "/plants/1/connections/2", "")
+    t.Equal(200, w.Code)
+    w = t.serve("GET", "/plants/1/connections/3", "")
+    t.Equal(404, w.Code)
+
+    // 500 kW drawn under the old contract, 700 kW under the new one
+    w = t.serve("POST", "/plants/1/measurements", `{"measurements": [
+        {"timestamp": "2022-04-30T12:00:00Z", "power": 500},
+        {"timestamp": "2022-05-02T12:00:00Z", "power": 700}
+    ]}`)
+    t.Require().Equal(200, w.Code)
+    w = t.serve("GET", "/plants/1/compliance?from=2022-04-01T00:00:00Z&to=2022-06-01T00:00:00Z", "")
+    t.Equal(200, w.Code)
+    compliance = plants.ConnectionCompliance{}
+    t.Require().NoError(json.NewDecoder(w.Result().Body).Decode(&compliance))
+    t.False(compliance.Compliant)
+    t.Require().NotNil(compliance.Connection)
+    t.Equal(uint(2), compliance.Connection.ID)
+    codes := []string{}
+    for _, issue := range compliance.Issues {
+        codes = append(codes, issue.Code)
+    }
+    t.ElementsMatch([]string{plants.IssuePlantPowerAboveImport, plants.IssueImportPeakAboveCapacity}, codes)
+    w = t.serve("GET", "/plants/1/compliance?from=today", "")
+    t.Equal(400, w.Code)
+    w = t.serve("GET", "/plants/2/compliance", "")
+    t.Equal(404, w.Code)
+
+    w = t.serve("DELETE", "/plants/1/connections/1", "")
+    t.Equal(200, w.Code)
+    w = t.serve("DELETE", "/plants/1/connections/1", "")
+    t.Equal(404, w.Code)
+}
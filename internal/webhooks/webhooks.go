@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+    SignatureHeader = "X-Webhook-Signature"
+    EventHeader     = "X-Webhook-Event"
+    DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var (
+    ErrSignature = errors.New("Webhook signature is missing or invalid")
+    ErrSignatureExpired = errors.New("Webhook signature is too old")
+)
+
+// Sign computes the signature header of a payload sent at t, in the form
+// 't=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<payload>">'. Signing
+// the time along with the payload lets receivers reject replayed requests.
+func Sign(secret string, t time.Time, payload []byte) string {
+    timestamp := strconv.FormatInt(t.Unix(), 10)
+    return "t=" + timestamp + ",v1=" + signature(secret, timestamp, payload)
+}
+
+func signature(secret string, timestamp string, payload []byte) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(timestamp))
+    mac.Write([]byte("."))
+    mac.Write(payload)
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature header of a payload received at now. A
+// signature older than tolerance is rejected, unless tolerance is zero.
+func Verify(secret string, header string, payload []byte, now time.Time, tolerance time.Duration) error {
+    var timestamp, v1 string
+    for _, part := range strings.Split(header, ",") {
+        key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+        switch key {
+        case "t":
+            timestamp = value
+        case "v1":
+            v1 = value
+        }
+    }
+    unix, err := strconv.ParseInt(timestamp, 10, 64)
+    if err != nil || v1 == "" {
+        return ErrSignature
+    }
+    if !hmac.Equal([]byte(v1), []byte(signature(secret, timestamp, payload))) {
+        return ErrSignature
+    }
+    if tolerance > 0 && now.Sub(time.Unix(unix, 0)) > tolerance {
+        return ErrSignatureExpired
+    }
+    return nil
+}
+
+// Backoff spaces out the attempts to deliver a webhook: Base after the first
+// failure, then twice as long after each new one, up to Max.
+type Backoff struct {
+    Base time.Duration
+    Max  time.Duration
+}
+
+// Delay is how long to wait after a number of failed attempts.
+func (b Backoff) Delay(attempts uint) time.Duration {
+    delay := b.Base
+    for i := uint(1); i < attempts && delay < b.Max; i++ {
+        delay *= 2
+    }
+    if delay > b.Max {
+        return b.Max
+    }
+    return delay
+}
+
+// Request is a webhook to deliver.
+type Request struct {
+    URL      string
+    Secret   string
+    Event    string
+    Delivery string
+    Payload  []byte
+}
+
+// Client posts signed webhooks.
+type Client struct {
+    HTTP *http.Client
+    now  func() time.Time
+}
+
+func NewClient() *Client {
+    return &Client{HTTP: &http.Client{Timeout: 10 * time.Second}, now: time.Now}
+}
+
+// Deliver posts the payload of r as JSON to its URL. Any answer but a 2xx
+// is an error.
+func (c *Client) Deliver(ctx context.Context, r Request) error {
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Payload))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set(EventHeader, r.Event)
+    req.Header.Set(DeliveryHeader, r.Delivery)
+    req.Header.Set(SignatureHeader, Sign(r.Secret, c.now(), r.Payload))
+
+    res, err := c.HTTP.Do(req)
+    if err != nil {
+        return err
+    }
+    defer res.Body.Close()
+    if res.StatusCode < 200 || res.StatusCode >= 300 {
+        return fmt.Errorf("webhook %s answered %d", r.URL, res.StatusCode)
+    }
+    return nil
+}
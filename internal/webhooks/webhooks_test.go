@@ -0,0 +1,86 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MainTestSuite struct {
+    suite.Suite
+}
+
+func TestWebhooks(t *testing.T) {
+    suite.Run(t, new(MainTestSuite))
+}
+
+func (t *MainTestSuite) TestSignature() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    payload := []byte(`{"type": "plant.updated"}`)
+    header := Sign("s3cr3t", now, payload)
+    t.Regexp(`^t=1651478400,v1=[0-9a-f]{64}$`, header)
+
+    t.NoError(Verify("s3cr3t", header, payload, now.Add(time.Minute), 5*time.Minute))
+    t.ErrorIs(Verify("other", header, payload, now, 0), ErrSignature)
+    t.ErrorIs(Verify("s3cr3t", header, []byte(`{"type": "plant.deleted"}`), now, 0), ErrSignature)
+    t.ErrorIs(Verify("s3cr3t", "v1=abc", payload, now, 0), ErrSignature)
+    // replayed later on
+    t.ErrorIs(Verify("s3cr3t", header, payload, now.Add(time.Hour), 5*time.Minute), ErrSignatureExpired)
+}
+
+func (t *MainTestSuite) TestBackoff() {
+    backoff := Backoff{Base: 30 * time.Second, Max: 10 * time.Minute}
+    t.Equal(30*time.Second, backoff.Delay(0))
+    t.Equal(30*time.Second, backoff.Delay(1))
+    t.Equal(time.Minute, backoff.Delay(2))
+    t.Equal(8*time.Minute, backoff.Delay(5))
+    t.Equal(10*time.Minute, backoff.Delay(6))
+    t.Equal(10*time.Minute, backoff.Delay(100))
+}
+
+func (t *MainTestSuite) TestDeliver() {
+    now := time.Date(2022, 5, 2, 8, 0, 0, 0, time.UTC)
+    received := make(chan *http.Request, 1)
+    status := http.StatusNoContent
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        payload, _ := io.ReadAll(r.Body)
+        if err := Verify("s3cr3t", r.Header.Get(SignatureHeader), payload, now, time.Minute); err != nil {
+            w.WriteHeader(http.StatusUnauthorized)
+        } else {
+            w.WriteHeader(status)
+        }
+        received <- r
+    }))
+    defer server.Close()
+
+    client := NewClient()
+    client.now = func() time.Time { return now }
+    request := Request{
+        URL: server.URL,
+        Secret: "s3cr3t",
+        Event: "asset.deleted",
+        Delivery: "42",
+        Payload: []byte(`{"data": {"id": 1}}`),
+    }
+    t.Require().NoError(client.Deliver(context.Background(), request))
+    r := <-received
+    t.Equal("application/json", r.Header.Get("Content-Type"))
+    t.Equal("asset.deleted", r.Header.Get(EventHeader))
+    t.Equal("42", r.Header.Get(DeliveryHeader))
+
+    // the receiver does not know this secret
+    request.Secret = "other"
+    t.Require().Error(client.Deliver(context.Background(), request))
+    <-received
+
+    // an error status is reported to the caller
+    request.Secret = "s3cr3t"
+    status = http.StatusServiceUnavailable
+    t.Require().Error(client.Deliver(context.Background(), request))
+    <-received
+}